@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var activityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "show recent account activity",
+	Long: `Shows a timeline of changes to monitors (such as starting or pausing
+them) over the specified period, oldest first. The timeline comes from the
+monitors' logs, which don't record the creation or editing of monitors.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		d, err := parseSince(since)
+		if err != nil {
+			log.Fatal(err)
+		}
+		events, err := client.Activity(time.Now().Add(-d))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(events) == 0 {
+			fmt.Println("No activity found")
+		}
+		for _, e := range events {
+			fmt.Println(e)
+		}
+	},
+}
+
+var since string
+
+// parseSince parses a duration such as '36h' or '7d', allowing the 'd' suffix
+// for a number of days in addition to the units accepted by
+// time.ParseDuration.
+func parseSince(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func init() {
+	activityCmd.Flags().StringVar(&since, "since", "7d", "How far back to look (for example 36h or 7d)")
+	RootCmd.AddCommand(activityCmd)
+}
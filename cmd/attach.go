@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach CONTACT",
+	Short: "add an alert contact to matching monitors",
+	Long: `Add the alert contact with the specified ID or name to every monitor
+whose name or URL matches the search string given with --search. Monitors
+which already have the contact are left unchanged.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if search == "" {
+			log.Fatal("--search is required")
+		}
+		client.RequestInterval = pace
+		IDs, err := client.AddAlertContactBySearch(search, resolveContacts(args)[0])
+		for _, ID := range IDs {
+			fmt.Printf("Monitor ID %d updated\n", ID)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(IDs) == 0 {
+			fmt.Println("No monitors needed updating")
+		}
+	},
+}
+
+var search string
+var pace time.Duration
+
+func init() {
+	attachCmd.Flags().StringVar(&search, "search", "", "Search string matching the monitors to update")
+	attachCmd.Flags().DurationVar(&pace, "pace", 6*time.Second, "Time to wait between API requests, to stay within the rate limit")
+	contactsCmd.AddCommand(attachCmd)
+}
@@ -4,28 +4,86 @@ import (
 	"fmt"
 	"log"
 
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
 	"github.com/spf13/cobra"
 )
 
 var contactsCmd = &cobra.Command{
-	Use:   "contacts",
+	Use:   "contacts [ID]",
 	Short: "list alert contacts",
-	Long:  `Show all alert contacts associated with the account`,
+	Long:  `Show all alert contacts associated with the account, or the alert contact with the specified ID`,
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 {
+			ID, err := uptimerobot.ParseContactID(args[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+			contact, err := client.GetAlertContact(ID)
+			if err != nil {
+				log.Fatal(err)
+			}
+			printItem(contact)
+			return
+		}
 		contacts, err := client.AllAlertContacts()
 		if err != nil {
 			log.Fatal(err)
 		}
-		if len(contacts) == 0 {
+		if len(contacts) == 0 && !structuredOutput() {
 			fmt.Println("No contacts found")
+			return
 		}
-		for _, c := range contacts {
-			fmt.Println(c)
-			fmt.Println()
+		printItems(contacts)
+	},
+}
+
+var contactsAddCmd = &cobra.Command{
+	Use:   "add NAME VALUE",
+	Short: "add an alert contact",
+	Long: `Create a new alert contact with the specified friendly name and value, such
+as an email address or webhook URL. Use --type to give the kind of contact
+(sms, email, twitter, webhook, pushbullet, zapier, pushover, or slack).`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		t, err := uptimerobot.ParseAlertContactType(contactType)
+		if err != nil {
+			log.Fatal(err)
 		}
+		ID, err := client.CreateAlertContact(uptimerobot.AlertContact{
+			FriendlyName: args[0],
+			Type:         int(t),
+			Value:        args[1],
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("New alert contact created with ID %s\n", ID)
 	},
 }
 
+var contactType string
+
+// resolveContacts takes a list of alert contacts, each given either by ID or
+// by friendly name, and returns the corresponding contact IDs.
+func resolveContacts(refs []string) []uptimerobot.ContactID {
+	IDs := []uptimerobot.ContactID{}
+	for _, ref := range refs {
+		if ID, err := uptimerobot.ParseContactID(ref); err == nil {
+			IDs = append(IDs, ID)
+			continue
+		}
+		contact, err := client.AlertContactByName(ref)
+		if err != nil {
+			log.Fatal(err)
+		}
+		IDs = append(IDs, contact.ID)
+	}
+	return IDs
+}
+
 func init() {
+	contactsAddCmd.Flags().StringVar(&contactType, "type", "email", "Type of contact (sms, email, twitter, webhook, pushbullet, zapier, pushover, or slack)")
+	contactsCmd.AddCommand(contactsAddCmd)
 	RootCmd.AddCommand(contactsCmd)
 }
@@ -3,18 +3,33 @@ package cmd
 import (
 	"fmt"
 	"log"
-	"strconv"
+	"time"
 
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
 	"github.com/spf13/cobra"
 )
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete",
+	Use:   "delete [ID]",
 	Short: "delete a monitor",
-	Long:  `Delete the monitor with the specified ID`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Delete the monitor with the specified ID.
+
+To delete every monitor whose name or URL matches a search string (for
+example, the monitors for a temporary environment), use --search instead of
+giving an ID. You will be asked to confirm, unless you give the --yes flag.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		ID, err := strconv.ParseInt(args[0], 10, 64)
+		if deleteSearch != "" {
+			if len(args) > 0 {
+				log.Fatal("give either an ID or --search, not both")
+			}
+			deleteBySearch(deleteSearch)
+			return
+		}
+		if len(args) == 0 {
+			log.Fatal("a monitor ID or --search is required")
+		}
+		ID, err := uptimerobot.ParseMonitorID(args[0])
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -25,6 +40,39 @@ var deleteCmd = &cobra.Command{
 	},
 }
 
+var deleteSearch string
+
+// deleteBySearch deletes every monitor matching the search string, after
+// asking for confirmation. Only the monitors listed for confirmation are
+// deleted, so a monitor which starts to match in the meantime is left alone.
+func deleteBySearch(s string) {
+	monitors, err := client.SearchMonitors(s)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(monitors) == 0 {
+		fmt.Println("No matching monitors found")
+		return
+	}
+	for _, m := range monitors {
+		fmt.Printf("%d %s (%s)\n", m.ID, m.FriendlyName, m.URL)
+	}
+	if !assumeYes && !confirm(fmt.Sprintf("Delete these %d monitors?", len(monitors))) {
+		fmt.Println("Cancelled")
+		return
+	}
+	client.RequestInterval = pace
+	for _, m := range monitors {
+		if err := client.DeleteMonitor(m.ID); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Monitor ID %d successfully deleted\n", m.ID)
+	}
+}
+
 func init() {
+	deleteCmd.Flags().StringVar(&deleteSearch, "search", "", "Delete all monitors matching this search string")
+	deleteCmd.Flags().DurationVar(&pace, "pace", 6*time.Second, "Time to wait between API requests when deleting by search, to stay within the rate limit")
+	deleteCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Don't ask for confirmation")
 	RootCmd.AddCommand(deleteCmd)
 }
@@ -1,28 +1,39 @@
 package cmd
 
 import (
-	"fmt"
 	"log"
-	"strconv"
 
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
 	"github.com/spf13/cobra"
 )
 
 var getCmd = &cobra.Command{
 	Use:   "get",
-	Short: "get monitor by ID",
-	Long:  `Show the monitor details for the specified monitor ID.`,
-	Args:  cobra.ExactArgs(1),
+	Short: "get monitors by ID",
+	Long:  `Show the monitor details for the specified monitor IDs.`,
+	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		ID, err := strconv.ParseInt(args[0], 10, 64)
-		if err != nil {
-			log.Fatal(err)
+		IDs := make([]uptimerobot.MonitorID, len(args))
+		for i, arg := range args {
+			ID, err := uptimerobot.ParseMonitorID(arg)
+			if err != nil {
+				log.Fatal(err)
+			}
+			IDs[i] = ID
+		}
+		if len(IDs) == 1 {
+			monitor, err := client.GetMonitor(IDs[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+			printItem(monitor)
+			return
 		}
-		monitor, err := client.GetMonitor(ID)
+		monitors, err := client.GetMonitorsByIDs(IDs)
 		if err != nil {
 			log.Fatal(err)
 		}
-		fmt.Println(monitor)
+		printItems(monitors)
 	},
 }
 
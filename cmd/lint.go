@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "check monitors for risky configurations",
+	Long: `Checks all monitors for risky configurations, such as monitors with no
+alert contacts, keyword monitors with no keyword, HTTP (not HTTPS) monitors
+for login pages, critical monitors checked too infrequently, and monitors
+which have been paused for a long time.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := uptimerobot.LintConfig{
+			MaxPausedAge: time.Duration(pausedDays) * 24 * time.Hour,
+		}
+		if critical != "" {
+			re, err := regexp.Compile(critical)
+			if err != nil {
+				log.Fatal(err)
+			}
+			cfg.Critical = func(m uptimerobot.Monitor) bool {
+				return re.MatchString(m.FriendlyName) || re.MatchString(m.URL)
+			}
+		}
+		monitors, err := client.AllMonitors(uptimerobot.WithAlertContacts(), uptimerobot.WithLogs())
+		if err != nil {
+			log.Fatal(err)
+		}
+		problems := uptimerobot.Lint(monitors, cfg)
+		if len(problems) == 0 {
+			fmt.Println("No problems found")
+			return
+		}
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		os.Exit(1)
+	},
+}
+
+var critical string
+var pausedDays int
+
+func init() {
+	lintCmd.Flags().StringVar(&critical, "critical", "", "Regular expression matching the names or URLs of critical monitors")
+	lintCmd.Flags().IntVar(&pausedDays, "paused-days", 30, "Report monitors paused for longer than this many days (0 to disable)")
+	RootCmd.AddCommand(lintCmd)
+}
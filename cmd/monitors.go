@@ -3,7 +3,10 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"os"
+	"sort"
 
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
 	"github.com/spf13/cobra"
 )
 
@@ -12,20 +15,103 @@ var monitorCmd = &cobra.Command{
 	Short: "lists monitors",
 	Long:  `Lists all monitors associated with the account`,
 	Run: func(cmd *cobra.Command, args []string) {
-		monitors, err := client.AllMonitors()
-		if err != nil {
-			log.Fatal(err)
+		opts := []uptimerobot.Option{}
+		if len(types) > 0 {
+			t, err := parseMonitorTypes(types)
+			if err != nil {
+				log.Fatal(err)
+			}
+			opts = append(opts, uptimerobot.WithTypes(t...))
 		}
-		if len(monitors) == 0 {
-			log.Fatal("No matching monitors found")
+		if sortOrder != "" {
+			opts = append(opts, uptimerobot.WithSort(sortOrder))
+		}
+		if tree {
+			monitors, err := client.AllMonitors(opts...)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if len(monitors) == 0 {
+				log.Fatal("No matching monitors found")
+			}
+			if structuredOutput() {
+				printStructured(uptimerobot.GroupByDomain(monitors))
+				return
+			}
+			printTree(uptimerobot.GroupByDomain(monitors))
+			return
+		}
+		if outputFormat != "text" {
+			monitors, err := client.AllMonitors(opts...)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if len(monitors) == 0 && !structuredOutput() {
+				log.Fatal("No matching monitors found")
+			}
+			printItems(monitors)
+			if len(monitors) == 0 {
+				os.Exit(1)
+			}
+			return
 		}
-		for _, m := range monitors {
+		found := false
+		err := client.Monitors(func(m uptimerobot.Monitor) bool {
+			found = true
 			fmt.Println(m)
 			fmt.Println()
+			return true
+		}, opts...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !found {
+			log.Fatal("No matching monitors found")
 		}
 	},
 }
 
+var types []string
+var sortOrder string
+var tree bool
+
+// printTree prints each domain in the specified groups, in alphabetical
+// order, followed by a line for each of its monitors.
+func printTree(groups map[string][]uptimerobot.Monitor) {
+	domains := make([]string, 0, len(groups))
+	for d := range groups {
+		domains = append(domains, d)
+	}
+	sort.Strings(domains)
+	for _, d := range domains {
+		if d == "" {
+			fmt.Println("(no domain)")
+		} else {
+			fmt.Println(d)
+		}
+		for _, m := range groups[d] {
+			fmt.Printf("  %d  %s  %s  %s\n", m.ID, m.FriendlyName, m.URL, m.Status)
+		}
+	}
+}
+
+// parseMonitorTypes converts a list of monitor type names such as 'keyword'
+// or 'port' to the corresponding type values.
+func parseMonitorTypes(names []string) ([]int, error) {
+	result := make([]int, len(names))
+	for i, name := range names {
+		t, err := uptimerobot.ParseMonitorType(name)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = int(t)
+	}
+	return result, nil
+}
+
 func init() {
+	monitorCmd.Flags().StringSliceVarP(&types, "type", "t", []string{}, "Comma-separated list of monitor types to show (http, keyword, ping, port, heartbeat)")
+	monitorCmd.Flags().StringVar(&sortOrder, "sort", "", "Sort order for results (for example friendly_name or status)")
+	monitorCmd.Flags().BoolVar(&tree, "tree", false, "Group monitors by domain")
 	RootCmd.AddCommand(monitorCmd)
 }
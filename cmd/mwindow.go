@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"github.com/spf13/cobra"
+)
+
+var mwindowCmd = &cobra.Command{
+	Use:   "mwindow",
+	Short: "manage maintenance windows",
+	Long: `List, add, edit, or delete maintenance windows, during which monitors
+are not checked and no alerts are sent.`,
+}
+
+var mwindowListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list maintenance windows",
+	Long:  `Show all maintenance windows associated with the account`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		windows, err := client.AllMWindows()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(windows) == 0 {
+			fmt.Println("No maintenance windows found")
+		}
+		for _, w := range windows {
+			fmt.Println(w)
+			fmt.Println()
+		}
+	},
+}
+
+var mwindowAddCmd = &cobra.Command{
+	Use:   "add NAME",
+	Short: "add a maintenance window",
+	Long: `Create a new maintenance window with the specified friendly name.
+
+The easiest way to say when the window applies is with one of the --once,
+--daily, --weekly, or --monthly flags, giving a schedule such as:
+
+  --once "2006-01-02 22:00 for 90m"
+  --daily "02:00 for 30m"
+  --weekly "sat,sun 02:00 for 2h"
+  --monthly "1,15 02:00 for 1h"
+
+Times are in the local timezone, or the timezone given with --tz, and are
+converted to the account's timezone as needed.
+
+Alternatively, use --type, --value, --start, and --duration to give the
+window's settings as the API expects them. In this case, the start time of a
+recurring window is in the account's timezone.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		w, err := mwindowFromFlags()
+		if err != nil {
+			log.Fatal(err)
+		}
+		w.FriendlyName = args[0]
+		ID, err := client.CreateMWindow(w)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("New maintenance window created with ID %d\n", ID)
+	},
+}
+
+var mwindowEditCmd = &cobra.Command{
+	Use:   "edit ID",
+	Short: "edit a maintenance window",
+	Long: `Change the name, value, start time, or duration of the maintenance window
+with the specified ID. Settings which are not given are left unchanged.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ID, err := uptimerobot.ParseMWindowID(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		w, err := client.GetMWindow(ID)
+		if err != nil {
+			log.Fatal(err)
+		}
+		flags := cmd.Flags()
+		if flags.Changed("name") {
+			w.FriendlyName = mwindowName
+		}
+		if flags.Changed("value") {
+			w.Value = mwindowValue
+		}
+		if flags.Changed("start") {
+			w.StartTime, err = parseMWindowStart(w.Type, mwindowStart)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		if flags.Changed("duration") {
+			w.Duration = mwindowDuration
+		}
+		if _, err := client.EditMWindow(w); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Maintenance window ID %d updated\n", ID)
+	},
+}
+
+var mwindowDeleteCmd = &cobra.Command{
+	Use:   "delete ID",
+	Short: "delete a maintenance window",
+	Long:  `Delete the maintenance window with the specified ID`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ID, err := uptimerobot.ParseMWindowID(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = client.DeleteMWindow(ID); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Maintenance window ID %d successfully deleted\n", ID)
+	},
+}
+
+var mwindowName, mwindowType, mwindowValue, mwindowStart string
+var mwindowDuration time.Duration
+var mwindowOnce, mwindowDaily, mwindowWeekly, mwindowMonthly, mwindowTZ string
+
+// mwindowFromFlags returns the maintenance window described by the flags of
+// the add command: either a schedule given with one of --once, --daily,
+// --weekly, or --monthly, or the raw --type, --value, --start, and --duration
+// settings.
+func mwindowFromFlags() (uptimerobot.MWindow, error) {
+	var t, schedules int
+	var spec string
+	for typ, s := range map[int]string{
+		uptimerobot.MWindowTypeOnce:    mwindowOnce,
+		uptimerobot.MWindowTypeDaily:   mwindowDaily,
+		uptimerobot.MWindowTypeWeekly:  mwindowWeekly,
+		uptimerobot.MWindowTypeMonthly: mwindowMonthly,
+	} {
+		if s != "" {
+			t, spec = typ, s
+			schedules++
+		}
+	}
+	if schedules > 1 {
+		return uptimerobot.MWindow{}, errors.New("only one of --once, --daily, --weekly, or --monthly may be given")
+	}
+	if schedules == 1 {
+		return mwindowFromSchedule(t, spec)
+	}
+	if mwindowStart == "" {
+		return uptimerobot.MWindow{}, errors.New("a schedule (such as --weekly) or --start is required")
+	}
+	t, err := parseMWindowType(mwindowType)
+	if err != nil {
+		return uptimerobot.MWindow{}, err
+	}
+	start, err := parseMWindowStart(t, mwindowStart)
+	if err != nil {
+		return uptimerobot.MWindow{}, err
+	}
+	return uptimerobot.MWindow{
+		Type:      t,
+		Value:     mwindowValue,
+		StartTime: start,
+		Duration:  mwindowDuration,
+	}, nil
+}
+
+// mwindowFromSchedule parses a schedule for a window of type t, using the
+// timezone given with --tz.
+func mwindowFromSchedule(t int, spec string) (uptimerobot.MWindow, error) {
+	loc := time.Local
+	if mwindowTZ != "" {
+		l, err := time.LoadLocation(mwindowTZ)
+		if err != nil {
+			return uptimerobot.MWindow{}, err
+		}
+		loc = l
+	}
+	// Only recurring windows need converting to the account's timezone
+	acct := loc
+	if t != uptimerobot.MWindowTypeOnce {
+		l, err := client.AccountLocation()
+		if err != nil {
+			return uptimerobot.MWindow{}, err
+		}
+		acct = l
+	}
+	return parseSchedule(t, spec, loc, acct)
+}
+
+// parseMWindowType converts a maintenance window type name such as 'weekly'
+// to the corresponding type value.
+func parseMWindowType(name string) (int, error) {
+	typeValues := map[string]int{
+		"once":    uptimerobot.MWindowTypeOnce,
+		"daily":   uptimerobot.MWindowTypeDaily,
+		"weekly":  uptimerobot.MWindowTypeWeekly,
+		"monthly": uptimerobot.MWindowTypeMonthly,
+	}
+	t, ok := typeValues[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown maintenance window type %q (want once, daily, weekly, or monthly)", name)
+	}
+	return t, nil
+}
+
+// parseMWindowStart parses the start time of a maintenance window of the
+// specified type: a date and time in the local timezone for a one-off window,
+// or a time of day for a recurring window.
+func parseMWindowStart(t int, s string) (time.Time, error) {
+	if t == uptimerobot.MWindowTypeOnce {
+		start, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid start time %q (want a date and time, for example '2006-01-02 15:04')", s)
+		}
+		return start, nil
+	}
+	start, err := time.Parse("15:04", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start time %q (want a time of day, for example '02:00')", s)
+	}
+	return start, nil
+}
+
+func init() {
+	mwindowAddCmd.Flags().StringVar(&mwindowType, "type", "once", "How often the window recurs (once, daily, weekly, or monthly)")
+	mwindowAddCmd.Flags().StringVar(&mwindowValue, "value", "", "Days on which a weekly (1-7, Monday is 1) or monthly (1-31) window applies, for example 6-7")
+	mwindowAddCmd.Flags().StringVar(&mwindowStart, "start", "", "When the window begins (for example '2006-01-02 15:04', or '02:00' for a recurring window)")
+	mwindowAddCmd.Flags().DurationVar(&mwindowDuration, "duration", time.Hour, "How long the window lasts (for example 90m)")
+	mwindowAddCmd.Flags().StringVar(&mwindowOnce, "once", "", "Schedule for a one-off window (for example '2006-01-02 22:00 for 90m')")
+	mwindowAddCmd.Flags().StringVar(&mwindowDaily, "daily", "", "Schedule for a daily window (for example '02:00 for 30m')")
+	mwindowAddCmd.Flags().StringVar(&mwindowWeekly, "weekly", "", "Schedule for a weekly window (for example 'sat,sun 02:00 for 2h')")
+	mwindowAddCmd.Flags().StringVar(&mwindowMonthly, "monthly", "", "Schedule for a monthly window (for example '1,15 02:00 for 1h')")
+	mwindowAddCmd.Flags().StringVar(&mwindowTZ, "tz", "", "Timezone of the times in a schedule (for example Europe/London; default local time)")
+	mwindowEditCmd.Flags().StringVar(&mwindowName, "name", "", "New friendly name for the window")
+	mwindowEditCmd.Flags().StringVar(&mwindowValue, "value", "", "Days on which a weekly (1-7, Monday is 1) or monthly (1-31) window applies, for example 6-7")
+	mwindowEditCmd.Flags().StringVar(&mwindowStart, "start", "", "When the window begins (for example '2006-01-02 15:04', or '02:00' for a recurring window)")
+	mwindowEditCmd.Flags().DurationVar(&mwindowDuration, "duration", 0, "How long the window lasts (for example 90m)")
+	mwindowCmd.AddCommand(mwindowListCmd)
+	mwindowCmd.AddCommand(mwindowAddCmd)
+	mwindowCmd.AddCommand(mwindowEditCmd)
+	mwindowCmd.AddCommand(mwindowDeleteCmd)
+	RootCmd.AddCommand(mwindowCmd)
+}
@@ -1,9 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"strings"
+	"time"
 
 	uptimerobot "github.com/bitfield/uptimerobot/pkg"
 	"github.com/spf13/cobra"
@@ -12,18 +13,19 @@ import (
 var newCmd = &cobra.Command{
 	Use:   "new",
 	Short: "add a new monitor",
-	Long:  `Create a new monitor with the specified URL and friendly name`,
-	Args:  cobra.ExactArgs(2),
+	Long: `Create a new monitor with the specified URL and friendly name.
+
+The kind of monitor is worked out from the URL: an http or https URL gives an
+HTTP monitor, a URL such as ftp://example.com or example.com:8443 gives a port
+monitor, and a host on its own, such as example.com, gives a ping monitor. Use
+--type to choose a keyword, ping, port, or heartbeat monitor instead. Keyword
+monitors need --keyword, and port monitors need --subtype (and --port, for a
+custom port) if they can't be inferred from the URL.`,
+	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		m := uptimerobot.Monitor{
-			URL:           args[0],
-			FriendlyName:  args[1],
-			Type:          uptimerobot.TypeHTTP,
-			AlertContacts: contacts,
-			Port:          80,
-		}
-		if strings.HasPrefix(m.URL, "https") {
-			m.Port = 443
+		m, err := monitorFromFlags(args[0], args[1])
+		if err != nil {
+			log.Fatal(err)
 		}
 		ID, err := client.CreateMonitor(m)
 		if err != nil {
@@ -34,8 +36,88 @@ var newCmd = &cobra.Command{
 }
 
 var contacts []string
+var interval, timeout time.Duration
+var ignoreSSLErrors bool
+var monitorType, monitorSubType, keyword string
+var port int
+var alertIfFound bool
+
+// monitorFromFlags returns a monitor with the specified URL and friendly name,
+// and the settings given by the flags of the new and ensure commands. Unless
+// --type is given, the kind of monitor is inferred from the URL.
+func monitorFromFlags(URL, name string) (uptimerobot.Monitor, error) {
+	m, err := uptimerobot.InferMonitorFromURL(URL)
+	if err != nil && monitorType == "" {
+		return uptimerobot.Monitor{}, err
+	}
+	if monitorType != "" {
+		t, err := uptimerobot.ParseMonitorType(monitorType)
+		if err != nil {
+			return uptimerobot.Monitor{}, err
+		}
+		if int(t) != m.Type {
+			inferred := m
+			m = uptimerobot.Monitor{
+				URL:  URL,
+				Type: int(t),
+			}
+			// A keyword monitor checks a web page, just like an HTTP monitor
+			if t == uptimerobot.TypeKeyword && inferred.Type == uptimerobot.TypeHTTP {
+				m.Port = inferred.Port
+			}
+		}
+	}
+	m.FriendlyName = name
+	m.AlertContacts = resolveContacts(contacts)
+	m.Interval = interval
+	m.Timeout = timeout
+	m.IgnoreSSLErrors = ignoreSSLErrors
+	if m.Type == uptimerobot.TypeKeyword {
+		if keyword == "" {
+			return uptimerobot.Monitor{}, errors.New("keyword monitors need --keyword")
+		}
+		m.KeywordValue = keyword
+		m.KeywordType = uptimerobot.KeywordNotExists
+		if alertIfFound {
+			m.KeywordType = uptimerobot.KeywordExists
+		}
+	}
+	if m.Type == uptimerobot.TypePort {
+		if monitorSubType != "" {
+			st, err := uptimerobot.ParseSubType(monitorSubType)
+			if err != nil {
+				return uptimerobot.Monitor{}, err
+			}
+			m.SubType = int(st)
+		}
+		if port != 0 {
+			m.Port = port
+		}
+		if m.SubType == 0 {
+			return uptimerobot.Monitor{}, errors.New("port monitors need --subtype, or a URL such as example.com:8443")
+		}
+		if m.SubType == uptimerobot.SubTypeCustomPort && m.Port == 0 {
+			return uptimerobot.Monitor{}, errors.New("custom port monitors need --port")
+		}
+	}
+	return m, nil
+}
+
+// addMonitorFlags registers the flags used by monitorFromFlags on cmd.
+func addMonitorFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&contacts, "contacts", "c", []string{}, "Comma-separated list of contact IDs or names to notify")
+	flags.DurationVar(&interval, "interval", 0, "Check interval (for example 5m)")
+	flags.DurationVar(&timeout, "timeout", 0, "Request timeout for HTTP monitors (for example 30s)")
+	flags.BoolVar(&ignoreSSLErrors, "ignore-ssl-errors", false, "Don't alert on SSL certificate errors (for example, self-signed certificates)")
+	flags.StringVarP(&monitorType, "type", "t", "", "Monitor type (http, keyword, ping, port, or heartbeat; default inferred from the URL)")
+	flags.StringVar(&monitorSubType, "subtype", "", "Service checked by a port monitor (http, https, ftp, smtp, pop3, imap, or custom)")
+	flags.IntVar(&port, "port", 0, "Port checked by a custom port monitor")
+	flags.StringVar(&keyword, "keyword", "", "Keyword checked by a keyword monitor (alerts if the keyword is missing)")
+	flags.BoolVar(&alertIfFound, "alert-if-found", false, "Make a keyword monitor alert if the keyword is found, instead of missing")
+}
 
 func init() {
-	newCmd.Flags().StringSliceVarP(&contacts, "contacts", "c", []string{}, "Comma-separated list of contact IDs to notify")
+	addMonitorFlags(newCmd)
 	RootCmd.AddCommand(newCmd)
 }
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat is the format in which commands print their results, as set by
+// the --output flag: 'text' (the default), 'table', 'json', 'yaml', or 'csv'.
+var outputFormat string
+
+// checkOutputFormat exits with an error if the --output flag is invalid.
+func checkOutputFormat() {
+	switch outputFormat {
+	case "text", "table", "json", "yaml", "csv":
+	default:
+		log.Fatalf("unknown output format %q (want text, table, json, yaml, or csv)", outputFormat)
+	}
+}
+
+// structuredOutput reports whether the output format is one for other
+// programs to read (JSON, YAML, or CSV), rather than for people.
+func structuredOutput() bool {
+	switch outputFormat {
+	case "json", "yaml", "csv":
+		return true
+	}
+	return false
+}
+
+// printItem prints a single result, such as a monitor, in the output format:
+// as text, using its String method, as a table or CSV with a single row, or
+// as a JSON or YAML object. Results other than monitors and alert contacts
+// are printed as text in table format.
+func printItem(v interface{}) {
+	if outputFormat == "table" && printTable(v) {
+		return
+	}
+	if structuredOutput() {
+		printStructured(v)
+		return
+	}
+	fmt.Println(v)
+}
+
+// printItems prints a list of results in the output format: as text, each
+// followed by a blank line, as a table or CSV, with a header, or as a JSON or
+// YAML list.
+func printItems[T any](items []T) {
+	if outputFormat == "table" && printTable(items) {
+		return
+	}
+	if structuredOutput() {
+		if items == nil {
+			items = []T{}
+		}
+		printStructured(items)
+		return
+	}
+	for _, item := range items {
+		fmt.Println(item)
+		fmt.Println()
+	}
+}
+
+// printJSON prints v as indented JSON.
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// printStructured prints v as JSON, YAML, or CSV, according to the output
+// format.
+func printStructured(v interface{}) {
+	switch outputFormat {
+	case "yaml":
+		printYAML(outputValue(v))
+	case "csv":
+		printCSV(v)
+	default:
+		printJSON(outputValue(v))
+	}
+}
+
+// monitorFields has the same fields as Monitor, but none of its methods, so
+// that monitors are printed as plain structs, with their alert contacts as a
+// list of IDs, rather than in the format the API expects when creating them.
+type monitorFields uptimerobot.Monitor
+
+// monitorOutput is a monitor as printed in JSON or YAML output. Its interval
+// and timeout are given in seconds, as in CSV output, the API, and the
+// --interval flag of the 'new' command, rather than as the nanoseconds of a
+// time.Duration.
+type monitorOutput struct {
+	monitorFields
+	Interval int64 `json:"interval,omitempty"`
+	Timeout  int64 `json:"timeout,omitempty"`
+}
+
+// newMonitorOutput returns m converted to monitorOutput.
+func newMonitorOutput(m uptimerobot.Monitor) monitorOutput {
+	return monitorOutput{
+		monitorFields: monitorFields(m),
+		Interval:      int64(m.Interval / time.Second),
+		Timeout:       int64(m.Timeout / time.Second),
+	}
+}
+
+// outputValue returns v with any monitors in it, whether a single monitor, a
+// list, or a map of lists, converted to monitorOutput.
+func outputValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case uptimerobot.Monitor:
+		return newMonitorOutput(v)
+	case []uptimerobot.Monitor:
+		out := make([]monitorOutput, len(v))
+		for i, m := range v {
+			out[i] = newMonitorOutput(m)
+		}
+		return out
+	case map[string][]uptimerobot.Monitor:
+		out := make(map[string]interface{}, len(v))
+		for k, ms := range v {
+			out[k] = outputValue(ms)
+		}
+		return out
+	}
+	return v
+}
+
+// printYAML prints v as YAML, with the same field names and values as its JSON
+// encoding, so that monitors printed with outputValue have the same layout in
+// both formats.
+func printYAML(v interface{}) {
+	if err := writeYAML(os.Stdout, v); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeYAML writes v to w as YAML, as described for printYAML.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	return enc.Encode(yamlNumbers(generic))
+}
+
+// yamlNumbers replaces the JSON numbers in a decoded JSON value with integers
+// or floats, so that they are encoded as YAML numbers rather than strings.
+func yamlNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = yamlNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = yamlNumbers(e)
+		}
+	}
+	return v
+}
+
+// printCSV prints the monitors or alert contacts in v, which may be a single
+// item or a list, as CSV with a header row. Other results can't be printed as
+// CSV.
+func printCSV(v interface{}) {
+	header, rows, ok := records(v)
+	if !ok {
+		log.Fatal("CSV output is only supported for monitors and alert contacts")
+	}
+	w := csv.NewWriter(os.Stdout)
+	w.Write(header)
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// records returns a header and a row of fields for each of the monitors or
+// alert contacts in v, which may be a single item or a list. It returns false
+// if v is neither.
+func records(v interface{}) (header []string, rows [][]string, ok bool) {
+	switch v := v.(type) {
+	case uptimerobot.Monitor:
+		return records([]uptimerobot.Monitor{v})
+	case uptimerobot.AlertContact:
+		return records([]uptimerobot.AlertContact{v})
+	case []uptimerobot.Monitor:
+		header = []string{"id", "friendly_name", "url", "type", "sub_type", "port", "keyword_type", "keyword_value", "interval", "timeout", "status", "alert_contacts"}
+		for _, m := range v {
+			contacts := make([]string, len(m.AlertContacts))
+			for i, c := range m.AlertContacts {
+				contacts[i] = c.String()
+			}
+			row := []string{m.ID.String(), m.FriendlyName, m.URL, m.FriendlyType(), "", "", "", m.KeywordValue,
+				strconv.Itoa(int(m.Interval / time.Second)), strconv.Itoa(int(m.Timeout / time.Second)),
+				m.FriendlyStatus(), strings.Join(contacts, " ")}
+			if m.SubType != 0 {
+				row[4] = uptimerobot.MonitorSubType(m.SubType).String()
+			}
+			if m.Port != 0 {
+				row[5] = strconv.Itoa(m.Port)
+			}
+			if m.KeywordType != 0 {
+				row[6] = m.FriendlyKeywordType()
+			}
+			rows = append(rows, row)
+		}
+		return header, rows, true
+	case []uptimerobot.AlertContact:
+		header = []string{"id", "friendly_name", "type", "status", "value"}
+		for _, c := range v {
+			rows = append(rows, []string{c.ID.String(), c.FriendlyName, c.FriendlyType(), strconv.Itoa(c.Status), c.Value})
+		}
+		return header, rows, true
+	}
+	return nil, nil, false
+}
+
+// tableColumns are the fields shown in table format, and their headings.
+var tableColumns = []struct {
+	field, heading string
+	width          int
+}{
+	{"id", "ID", 0},
+	{"friendly_name", "NAME", 30},
+	{"url", "URL", 40},
+	{"value", "VALUE", 40},
+	{"type", "TYPE", 0},
+	{"status", "STATUS", 0},
+}
+
+// printTable prints the monitors or alert contacts in v, which may be a single
+// item or a list, as a table with aligned columns, truncating long names and
+// URLs. It returns false, printing nothing, if v is neither.
+func printTable(v interface{}) bool {
+	header, rows, ok := records(v)
+	if !ok {
+		return false
+	}
+	index := map[string]int{}
+	for i, h := range header {
+		index[h] = i
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	line := func(fields []string) {
+		fmt.Fprintln(tw, strings.Join(fields, "\t"))
+	}
+	headings := []string{}
+	for _, col := range tableColumns {
+		if _, ok := index[col.field]; ok {
+			headings = append(headings, col.heading)
+		}
+	}
+	line(headings)
+	for _, row := range rows {
+		fields := []string{}
+		for _, col := range tableColumns {
+			if i, ok := index[col.field]; ok {
+				fields = append(fields, truncate(row[i], col.width))
+			}
+		}
+		line(fields)
+	}
+	if err := tw.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	return true
+}
+
+// truncate shortens s to at most width characters, ending in '...' if it was
+// shortened. A width of zero means no limit.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width == 0 || len(r) <= width {
+		return s
+	}
+	return string(r[:width-3]) + "..."
+}
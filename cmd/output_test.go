@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOutputValueJSONIntervalInSeconds(t *testing.T) {
+	t.Parallel()
+	m := uptimerobot.Monitor{
+		ID:       780689017,
+		URL:      "https://www.example.com/",
+		Interval: 5 * time.Minute,
+		Timeout:  30 * time.Second,
+	}
+	data, err := json.Marshal(outputValue(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["interval"] != 300.0 {
+		t.Errorf("want interval 300, got %v", got["interval"])
+	}
+	if got["timeout"] != 30.0 {
+		t.Errorf("want timeout 30, got %v", got["timeout"])
+	}
+}
+
+func TestWriteYAMLIntervalInSeconds(t *testing.T) {
+	t.Parallel()
+	m := uptimerobot.Monitor{
+		ID:       780689017,
+		URL:      "https://www.example.com/",
+		Interval: 5 * time.Minute,
+	}
+	buf := &bytes.Buffer{}
+	if err := writeYAML(buf, outputValue([]uptimerobot.Monitor{m})); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]interface{}
+	if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("want 1 monitor, got %d: %q", len(got), buf.String())
+	}
+	if got[0]["interval"] != 300 {
+		t.Errorf("want interval 300, got %v", got[0]["interval"])
+	}
+}
@@ -1,24 +1,50 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"strconv"
+	"os"
+	"os/signal"
+	"time"
 
 	"github.com/bitfield/uptimerobot/pkg"
 	"github.com/spf13/cobra"
 )
 
 var pauseCmd = &cobra.Command{
-	Use:   "pause",
+	Use:   "pause [ID]",
 	Short: "pause a monitor",
-	Long:  `Pause the monitor with the specified ID`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Pause the monitor with the specified ID.
+
+To pause every monitor whose name or URL matches a search string (for example,
+during maintenance affecting a whole domain), use --search instead of giving
+an ID, or --all to pause every monitor.
+
+To pause a monitor for a short maintenance, give the length of time with
+--for. The command waits for this long and then resumes the monitor; if it is
+interrupted, it resumes the monitor straight away.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		ID, err := strconv.ParseInt(args[0], 10, 64)
+		if bulk(args) {
+			runBulk(client.PauseAll, "paused")
+			return
+		}
+		ID, err := uptimerobot.ParseMonitorID(args[0])
 		if err != nil {
 			log.Fatal(err)
 		}
+		if pauseFor > 0 {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			fmt.Printf("Pausing monitor ID %d for %s\n", ID, pauseFor)
+			if err := client.PauseMonitorFor(ctx, ID, pauseFor); err != nil && !errors.Is(err, context.Canceled) {
+				log.Fatal(err)
+			}
+			fmt.Printf("Monitor ID %d started\n", ID)
+			return
+		}
 		m := uptimerobot.Monitor{
 			ID: ID,
 		}
@@ -30,6 +56,60 @@ var pauseCmd = &cobra.Command{
 	},
 }
 
+var all bool
+var pauseFor time.Duration
+
+// bulk reports whether the command should act on several monitors (given
+// --search or --all) rather than the single monitor whose ID is in args. It
+// exits with an error if the arguments are inconsistent.
+func bulk(args []string) bool {
+	if search != "" && all {
+		log.Fatal("give either --search or --all, not both")
+	}
+	if search == "" && !all {
+		if len(args) == 0 {
+			log.Fatal("a monitor ID, --search, or --all is required")
+		}
+		return false
+	}
+	if len(args) > 0 {
+		log.Fatal("give either an ID or --search/--all, not both")
+	}
+	return true
+}
+
+// runBulk calls fn with the search string given with --search (which is empty
+// if --all was given), pacing its API requests, and prints the outcome for
+// each monitor. It exits with an error status if any of them failed.
+func runBulk(fn func(string) ([]uptimerobot.MonitorResult, error), verb string) {
+	client.RequestInterval = pace
+	results, err := fn(search)
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Printf("Monitor ID %d (%s) failed: %v\n", r.Monitor.ID, r.Monitor.FriendlyName, r.Err)
+			failed++
+			continue
+		}
+		fmt.Printf("Monitor ID %d (%s) %s\n", r.Monitor.ID, r.Monitor.FriendlyName, verb)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if failed > 0 {
+		log.Fatalf("%d of %d monitors failed", failed, len(results))
+	}
+	if len(results) == 0 {
+		fmt.Println("No monitors needed updating")
+	}
+}
+
 func init() {
+	for _, cmd := range []*cobra.Command{pauseCmd, startCmd} {
+		cmd.Flags().StringVar(&search, "search", "", "Act on all monitors matching this search string")
+		cmd.Flags().BoolVar(&all, "all", false, "Act on every monitor")
+		cmd.Flags().DurationVar(&pace, "pace", 6*time.Second, "Time to wait between API requests when acting on several monitors, to stay within the rate limit")
+	}
+	pauseCmd.Flags().DurationVar(&pauseFor, "for", 0, "Resume the monitor automatically after this long (for example 30m)")
 	RootCmd.AddCommand(pauseCmd)
 }
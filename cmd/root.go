@@ -47,13 +47,18 @@ func init() {
 	viper.SetEnvPrefix("uptimerobot")
 	viper.AutomaticEnv()
 	cobra.OnInitialize(func() {
-		client = uptimerobot.New(viper.GetString("apiKey"))
+		client = uptimerobot.New(viper.GetString("apiKey"),
+			uptimerobot.WithUserAgent("uptimerobot-cli/"+version),
+			uptimerobot.WithPlanChecks(),
+		)
 		if debug {
 			client.Debug = os.Stdout
 		}
+		checkOutputFormat()
 	})
 	RootCmd.PersistentFlags().StringVar(&apiKey, "apiKey", "", "Uptime Robot API key")
 	viper.BindPFlag("apiKey", RootCmd.PersistentFlags().Lookup("apiKey"))
 	viper.BindEnv("apiKey", "UPTIMEROBOT_API_KEY")
 	RootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Debug mode (show API request and response)")
+	RootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format for monitors, search, get, contacts, and account (text, table, json, yaml, or csv)")
 }
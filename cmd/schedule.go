@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+)
+
+// parseSchedule parses a maintenance window schedule of the specified type,
+// such as "2006-01-02 15:04 for 90m" (once), "02:00 for 30m" (daily),
+// "sat,sun 02:00 for 2h" (weekly), or "1,15 02:00 for 1h" (monthly), and
+// returns the corresponding MWindow.
+//
+// Times are given in loc. Since the API interprets the start times of
+// recurring windows in the account's timezone, acct, they are converted to
+// that timezone, moving the days of a weekly window if the conversion crosses
+// midnight.
+func parseSchedule(t int, spec string, loc, acct *time.Location) (uptimerobot.MWindow, error) {
+	parts := strings.SplitN(spec, " for ", 2)
+	if len(parts) != 2 {
+		return uptimerobot.MWindow{}, fmt.Errorf("invalid schedule %q (want, for example, 'sat,sun 02:00 for 2h')", spec)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return uptimerobot.MWindow{}, fmt.Errorf("invalid duration in schedule %q: %v", spec, err)
+	}
+	fields := strings.Fields(parts[0])
+	if t == uptimerobot.MWindowTypeOnce {
+		start, err := time.ParseInLocation("2006-01-02 15:04", strings.Join(fields, " "), loc)
+		if err != nil {
+			return uptimerobot.MWindow{}, fmt.Errorf("invalid start time in schedule %q (want a date and time, for example '2006-01-02 15:04')", spec)
+		}
+		return uptimerobot.MWindow{
+			Type:      t,
+			StartTime: start,
+			Duration:  d,
+		}, nil
+	}
+	wantFields := 2
+	if t == uptimerobot.MWindowTypeDaily {
+		wantFields = 1
+	}
+	if len(fields) != wantFields {
+		return uptimerobot.MWindow{}, fmt.Errorf("invalid schedule %q", spec)
+	}
+	start, shift, err := parseClock(fields[len(fields)-1], loc, acct)
+	if err != nil {
+		return uptimerobot.MWindow{}, err
+	}
+	switch t {
+	case uptimerobot.MWindowTypeWeekly:
+		days, err := parseWeekdays(fields[0])
+		if err != nil {
+			return uptimerobot.MWindow{}, err
+		}
+		for i, day := range days {
+			days[i] = time.Weekday((int(day) + shift + 7) % 7)
+		}
+		return uptimerobot.WeeklyWindow(days, start, d), nil
+	case uptimerobot.MWindowTypeMonthly:
+		days, err := parseDaysOfMonth(fields[0])
+		if err != nil {
+			return uptimerobot.MWindow{}, err
+		}
+		if shift != 0 {
+			return uptimerobot.MWindow{}, fmt.Errorf("start time %s falls on a different day in the account's timezone (%s), so the days of the month can't be converted; use --tz to give the time in the account's timezone", fields[1], start.Format("-07:00"))
+		}
+		return uptimerobot.MonthlyWindow(days, start, d), nil
+	default:
+		return uptimerobot.MWindow{
+			Type:      t,
+			StartTime: start,
+			Duration:  d,
+		}, nil
+	}
+}
+
+// parseClock parses a time of day such as '02:00' in loc, and returns the
+// same time today in acct, together with the number of days (-1, 0, or 1) by
+// which the conversion moved the date.
+func parseClock(s string, loc, acct *time.Location) (time.Time, int, error) {
+	tod, err := time.Parse("15:04", s)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid time of day %q (want, for example, '02:00')", s)
+	}
+	now := time.Now().In(loc)
+	local := time.Date(now.Year(), now.Month(), now.Day(), tod.Hour(), tod.Minute(), 0, 0, loc)
+	converted := local.In(acct)
+	localDate := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	convertedDate := time.Date(converted.Year(), converted.Month(), converted.Day(), 0, 0, 0, 0, time.UTC)
+	shift := int(convertedDate.Sub(localDate) / (24 * time.Hour))
+	return converted, shift, nil
+}
+
+// parseWeekdays parses a comma-separated list of day names, such as
+// 'sat,sun' or 'Monday,Friday'.
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	names := map[string]time.Weekday{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		names[name] = day
+		names[name[:3]] = day
+	}
+	days := []time.Weekday{}
+	for _, name := range strings.Split(s, ",") {
+		day, ok := names[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown day of the week %q (want, for example, 'mon' or 'sunday')", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// parseDaysOfMonth parses a comma-separated list of days of the month, such
+// as '1,15'.
+func parseDaysOfMonth(s string) ([]int, error) {
+	days := []int{}
+	for _, v := range strings.Split(s, ",") {
+		day, err := strconv.Atoi(v)
+		if err != nil || day < 1 || day > 31 {
+			return nil, fmt.Errorf("invalid day of the month %q (want 1 to 31)", v)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
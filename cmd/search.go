@@ -5,30 +5,37 @@ import (
 	"log"
 	"os"
 
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
 	"github.com/spf13/cobra"
 )
 
 var searchCmd = &cobra.Command{
 	Use:   "search",
 	Short: "search monitors",
-	Long:  `Lists all monitors matching a search string`,
+	Long:  `Lists all monitors matching any of the search strings`,
 	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		monitors, err := client.SearchMonitors(args[0])
+		opts := []uptimerobot.Option{}
+		if sortOrder != "" {
+			opts = append(opts, uptimerobot.WithSort(sortOrder))
+		}
+		monitors, err := client.SearchMonitorsAll(args, opts...)
 		if err != nil {
 			log.Fatal(err)
 		}
 		if len(monitors) == 0 {
-			fmt.Println("No matching monitors found")
+			if structuredOutput() {
+				printItems(monitors)
+			} else {
+				fmt.Println("No matching monitors found")
+			}
 			os.Exit(1)
 		}
-		for _, m := range monitors {
-			fmt.Println(m)
-			fmt.Println()
-		}
+		printItems(monitors)
 	},
 }
 
 func init() {
+	searchCmd.Flags().StringVar(&sortOrder, "sort", "", "Sort order for results (for example friendly_name or status)")
 	RootCmd.AddCommand(searchCmd)
 }
@@ -3,19 +3,25 @@ package cmd
 import (
 	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/bitfield/uptimerobot/pkg"
 	"github.com/spf13/cobra"
 )
 
 var startCmd = &cobra.Command{
-	Use:   "start",
+	Use:   "start [ID]",
 	Short: "start a monitor",
-	Long:  `Start (unpause) the monitor with the specified ID`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Start (unpause) the monitor with the specified ID.
+
+To start every paused monitor whose name or URL matches a search string, use
+--search instead of giving an ID, or --all to start every paused monitor.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		ID, err := strconv.ParseInt(args[0], 10, 64)
+		if bulk(args) {
+			runBulk(client.StartAll, "started")
+			return
+		}
+		ID, err := uptimerobot.ParseMonitorID(args[0])
 		if err != nil {
 			log.Fatal(err)
 		}
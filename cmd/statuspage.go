@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"github.com/spf13/cobra"
+)
+
+var statuspageCmd = &cobra.Command{
+	Use:   "statuspage",
+	Short: "manage public status pages",
+	Long:  `List, add, edit, or delete the public status pages associated with the account`,
+}
+
+var statuspageListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list status pages",
+	Long:  `Show all public status pages associated with the account`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		psps, err := client.AllPSPs()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(psps) == 0 {
+			fmt.Println("No status pages found")
+		}
+		for _, p := range psps {
+			fmt.Println(p)
+			fmt.Println()
+		}
+	},
+}
+
+var statuspageAddCmd = &cobra.Command{
+	Use:   "add NAME",
+	Short: "add a status page",
+	Long: `Create a new public status page with the specified friendly name, showing
+the monitors given with --monitors, or all monitors if none are given.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p := uptimerobot.PSP{
+			FriendlyName: args[0],
+			Monitors:     monitorIDs(pspMonitors),
+			CustomDomain: pspCustomDomain,
+			Password:     pspPassword,
+		}
+		if pspSort != "" {
+			sort, err := parsePSPSort(pspSort)
+			if err != nil {
+				log.Fatal(err)
+			}
+			p.Sort = sort
+		}
+		ID, err := client.CreatePSP(p)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("New status page created with ID %d\n", ID)
+	},
+}
+
+var statuspageEditCmd = &cobra.Command{
+	Use:   "edit ID",
+	Short: "edit a status page",
+	Long: `Change the name, monitors, sort order, custom domain, or password of the
+public status page with the specified ID. Settings which are not given are
+left unchanged. To show all monitors on the page, use --all-monitors.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ID, err := uptimerobot.ParsePSPID(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		p := uptimerobot.EditPSPParams{
+			ID: ID,
+		}
+		flags := cmd.Flags()
+		if flags.Changed("name") {
+			p.FriendlyName = &pspName
+		}
+		if flags.Changed("monitors") {
+			IDs := monitorIDs(pspMonitors)
+			p.Monitors = &IDs
+		}
+		if pspAllMonitors {
+			p.Monitors = &[]uptimerobot.MonitorID{}
+		}
+		if flags.Changed("sort") {
+			sort, err := parsePSPSort(pspSort)
+			if err != nil {
+				log.Fatal(err)
+			}
+			p.Sort = &sort
+		}
+		if flags.Changed("custom-domain") {
+			p.CustomDomain = &pspCustomDomain
+		}
+		if flags.Changed("password") {
+			p.Password = &pspPassword
+		}
+		if _, err := client.EditPSP(p); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Status page ID %d updated\n", ID)
+	},
+}
+
+var statuspageDeleteCmd = &cobra.Command{
+	Use:   "delete ID",
+	Short: "delete a status page",
+	Long: `Delete the public status page with the specified ID. Since the page will
+no longer be available to anyone who uses it, you will be asked to confirm,
+unless you give the --yes flag.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ID, err := uptimerobot.ParsePSPID(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !assumeYes && !confirm(fmt.Sprintf("Delete status page ID %d?", ID)) {
+			fmt.Println("Cancelled")
+			return
+		}
+		if err = client.DeletePSP(ID); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Status page ID %d successfully deleted\n", ID)
+	},
+}
+
+var statuspageAddMonitorCmd = &cobra.Command{
+	Use:   "add-monitor PSP_ID MONITOR_ID",
+	Short: "show a monitor on a status page",
+	Long: `Add the monitor with the specified ID to the public status page with the
+specified ID, keeping any monitors already shown on it.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		pspID, monitorID := parsePSPMonitorArgs(args)
+		if err := client.AddMonitorToPSP(pspID, monitorID); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Monitor ID %d added to status page ID %d\n", monitorID, pspID)
+	},
+}
+
+var statuspageRemoveMonitorCmd = &cobra.Command{
+	Use:   "remove-monitor PSP_ID MONITOR_ID",
+	Short: "stop showing a monitor on a status page",
+	Long: `Remove the monitor with the specified ID from the public status page with
+the specified ID, keeping any other monitors shown on it.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		pspID, monitorID := parsePSPMonitorArgs(args)
+		if err := client.RemoveMonitorFromPSP(pspID, monitorID); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Monitor ID %d removed from status page ID %d\n", monitorID, pspID)
+	},
+}
+
+var assumeYes bool
+var pspName, pspSort, pspCustomDomain, pspPassword string
+var pspMonitors []int64
+var pspAllMonitors bool
+
+// parsePSPSort converts a status page sort order name such as 'down-first'
+// to the corresponding sort value.
+func parsePSPSort(name string) (int, error) {
+	sortValues := map[string]int{
+		"a-z":        uptimerobot.PSPSortFriendlyNameAZ,
+		"z-a":        uptimerobot.PSPSortFriendlyNameZA,
+		"up-first":   uptimerobot.PSPSortStatusUpDown,
+		"down-first": uptimerobot.PSPSortStatusDownUp,
+	}
+	sort, ok := sortValues[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown sort order %q (want a-z, z-a, up-first, or down-first)", name)
+	}
+	return sort, nil
+}
+
+// parsePSPMonitorArgs parses the status page ID and monitor ID arguments of
+// the add-monitor and remove-monitor commands.
+func parsePSPMonitorArgs(args []string) (uptimerobot.PSPID, uptimerobot.MonitorID) {
+	pspID, err := uptimerobot.ParsePSPID(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	monitorID, err := uptimerobot.ParseMonitorID(args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	return pspID, monitorID
+}
+
+// monitorIDs converts the monitor IDs given with --monitors to MonitorIDs.
+func monitorIDs(ints []int64) []uptimerobot.MonitorID {
+	IDs := make([]uptimerobot.MonitorID, len(ints))
+	for i, v := range ints {
+		IDs[i] = uptimerobot.MonitorID(v)
+	}
+	return IDs
+}
+
+// confirm asks the user the specified question on the terminal, and reports
+// whether they answered yes.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	statuspageAddCmd.Flags().Int64SliceVar(&pspMonitors, "monitors", []int64{}, "Comma-separated list of IDs of monitors to show (default all)")
+	statuspageAddCmd.Flags().StringVar(&pspSort, "sort", "", "Order in which to show monitors (a-z, z-a, up-first, or down-first)")
+	statuspageAddCmd.Flags().StringVar(&pspCustomDomain, "custom-domain", "", "Custom domain on which to serve the page (for example status.example.com)")
+	statuspageAddCmd.Flags().StringVar(&pspPassword, "password", "", "Password required to view the page")
+	statuspageEditCmd.Flags().StringVar(&pspName, "name", "", "New friendly name for the page")
+	statuspageEditCmd.Flags().Int64SliceVar(&pspMonitors, "monitors", []int64{}, "Comma-separated list of IDs of monitors to show")
+	statuspageEditCmd.Flags().BoolVar(&pspAllMonitors, "all-monitors", false, "Show all monitors")
+	statuspageEditCmd.Flags().StringVar(&pspSort, "sort", "", "Order in which to show monitors (a-z, z-a, up-first, or down-first)")
+	statuspageEditCmd.Flags().StringVar(&pspCustomDomain, "custom-domain", "", "Custom domain on which to serve the page (empty to remove)")
+	statuspageEditCmd.Flags().StringVar(&pspPassword, "password", "", "Password required to view the page (empty to remove)")
+	statuspageCmd.AddCommand(statuspageListCmd)
+	statuspageCmd.AddCommand(statuspageAddCmd)
+	statuspageCmd.AddCommand(statuspageEditCmd)
+	statuspageCmd.AddCommand(statuspageAddMonitorCmd)
+	statuspageCmd.AddCommand(statuspageRemoveMonitorCmd)
+	statuspageDeleteCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Don't ask for confirmation")
+	statuspageCmd.AddCommand(statuspageDeleteCmd)
+	RootCmd.AddCommand(statuspageCmd)
+}
@@ -3,10 +3,11 @@ package cmd
 import (
 	"fmt"
 
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
 	"github.com/spf13/cobra"
 )
 
-var version = "0.13.2"
+var version = uptimerobot.Version
 
 var versionCmd = &cobra.Command{
 	Use:   "version",
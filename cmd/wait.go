@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"github.com/spf13/cobra"
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait ID",
+	Short: "wait for a monitor to reach a status",
+	Long: `Poll the monitor with the specified ID until it reaches the given status
+(up, by default), and exit with an error if it hasn't done so within the
+timeout. This is useful in deploy pipelines, to wait until Uptime Robot sees a
+new endpoint as up.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ID, err := uptimerobot.ParseMonitorID(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		status, err := uptimerobot.ParseStatus(waitStatus)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if waitInterval <= 0 {
+			log.Fatalf("--interval must be positive, not %s", waitInterval)
+		}
+		ctx := context.Background()
+		if waitTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, waitTimeout)
+			defer cancel()
+		}
+		m, err := client.WaitForStatus(ctx, ID, status, waitInterval)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Monitor ID %d is %s\n", m.ID, m.Status)
+	},
+}
+
+var waitStatus string
+var waitInterval, waitTimeout time.Duration
+
+func init() {
+	waitCmd.Flags().StringVar(&waitStatus, "status", "up", "Status to wait for (up, down, maybedown, paused, or unknown)")
+	waitCmd.Flags().DurationVar(&waitInterval, "interval", 30*time.Second, "How often to check the monitor's status")
+	waitCmd.Flags().DurationVar(&waitTimeout, "timeout", 10*time.Minute, "How long to wait before giving up (0 to wait indefinitely)")
+	RootCmd.AddCommand(waitCmd)
+}
@@ -1,23 +1,171 @@
 package uptimerobot
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
 // Account represents an Uptime Robot account.
 type Account struct {
-	Email           string `json:"email"`
-	MonitorLimit    int    `json:"monitor_limit"`
-	MonitorInterval int    `json:"monitor_interval"`
-	UpMonitors      int    `json:"up_monitors"`
-	DownMonitors    int    `json:"down_monitors"`
-	PausedMonitors  int    `json:"paused_monitors"`
+	Email           string        `json:"email"`
+	MonitorLimit    int           `json:"monitor_limit"`
+	MonitorInterval time.Duration `json:"monitor_interval"`
+	UpMonitors      int           `json:"up_monitors"`
+	DownMonitors    int           `json:"down_monitors"`
+	PausedMonitors  int           `json:"paused_monitors"`
 }
 
-const accountTemplate = `Email: {{ .Email }}
+// AccountTemplate is the template used by Account.String. You can use it as the
+// starting point for your own template, to pass to Account.Render.
+const AccountTemplate = `Email: {{ .Email }}
 Monitor limit: {{ .MonitorLimit }}
-Monitor interval: {{ .MonitorInterval }}
+Monitor interval: {{ duration .MonitorInterval }}
 Up monitors: {{ .UpMonitors }}
 Down monitors: {{ .DownMonitors }}
 Paused monitors: {{ .PausedMonitors }}`
 
 // String returns a pretty-printed version of the account details.
 func (a Account) String() string {
-	return render(accountTemplate, a)
+	return renderString(AccountTemplate, a)
+}
+
+// Render returns the result of executing the specified text/template template
+// with the account details as its data, or an error if the template is invalid
+// or fails. The template can use the functions listed in TemplateFuncs.
+func (a Account) Render(tmpl string) (string, error) {
+	return render(tmpl, a)
+}
+
+// UnmarshalJSON converts a JSON account representation to an Account struct,
+// handling the API's encoding of the minimum monitor interval in minutes, and
+// of integer fields as quoted numbers or empty strings.
+func (a *Account) UnmarshalJSON(data []byte) error {
+	// Use a temporary type definition to avoid infinite recursion when
+	// unmarshaling, overriding the interval field
+	type AccountAlias Account
+	aux := struct {
+		AccountAlias
+		MonitorLimit    FlexInt `json:"monitor_limit"`
+		MonitorInterval FlexInt `json:"monitor_interval"`
+		UpMonitors      FlexInt `json:"up_monitors"`
+		DownMonitors    FlexInt `json:"down_monitors"`
+		PausedMonitors  FlexInt `json:"paused_monitors"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*a = Account(aux.AccountAlias)
+	a.MonitorLimit = int(aux.MonitorLimit)
+	a.UpMonitors = int(aux.UpMonitors)
+	a.DownMonitors = int(aux.DownMonitors)
+	a.PausedMonitors = int(aux.PausedMonitors)
+	a.MonitorInterval = time.Duration(aux.MonitorInterval) * time.Minute
+	return nil
+}
+
+// accountCache caches the account details for Client.Account, so that they
+// are fetched at most once, even by concurrent calls, until reset. A nil
+// accountCache caches nothing.
+type accountCache struct {
+	mu      sync.Mutex
+	account *Account
+}
+
+// get returns the cached account details, first calling fetch to fetch them
+// if necessary.
+func (ac *accountCache) get(ctx context.Context, fetch func(context.Context) (Account, error)) (Account, error) {
+	if ac == nil {
+		return fetch(ctx)
+	}
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	if ac.account == nil {
+		a, err := fetch(ctx)
+		if err != nil {
+			return Account{}, err
+		}
+		ac.account = &a
+	}
+	return *ac.account, nil
+}
+
+// reset empties the cache, so that the account details are fetched again
+// when next needed.
+func (ac *accountCache) reset() {
+	if ac == nil {
+		return
+	}
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.account = nil
+}
+
+// PlanLimitError is returned when creating a monitor, if the client was
+// created with WithPlanChecks, and the monitor would break one of the limits
+// of the account's plan: its interval is shorter than the plan allows, or the
+// account already has as many monitors as the plan allows.
+type PlanLimitError struct {
+	Message string
+}
+
+func (e PlanLimitError) Error() string {
+	return e.Message
+}
+
+// planCache caches the account details used by WithPlanChecks, so that they
+// are fetched at most once, together with the number of monitors in the
+// account, which is kept up to date as the client creates and deletes
+// monitors. A nil planCache checks nothing.
+type planCache struct {
+	mu       sync.Mutex
+	account  *Account
+	monitors int
+}
+
+// check returns a PlanLimitError if a new monitor with the specified interval
+// would break the limits of the account's plan, first calling fetch to fetch
+// the account details if necessary.
+func (pc *planCache) check(ctx context.Context, fetch func(context.Context) (Account, error), interval time.Duration) error {
+	if pc == nil {
+		return nil
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.account == nil {
+		a, err := fetch(ctx)
+		if err != nil {
+			return fmt.Errorf("checking plan limits: %w", err)
+		}
+		pc.account = &a
+		pc.monitors = a.UpMonitors + a.DownMonitors + a.PausedMonitors
+	}
+	min := pc.account.MonitorInterval
+	if interval != 0 && min != 0 && interval < min {
+		return PlanLimitError{
+			Message: fmt.Sprintf("interval %ds below plan minimum %ds", int(interval/time.Second), int(min/time.Second)),
+		}
+	}
+	limit := pc.account.MonitorLimit
+	if limit != 0 && pc.monitors >= limit {
+		return PlanLimitError{
+			Message: fmt.Sprintf("account already has %d monitors, the plan maximum", pc.monitors),
+		}
+	}
+	return nil
+}
+
+// add adjusts the cached number of monitors by n, if the account details have
+// been fetched.
+func (pc *planCache) add(n int) {
+	if pc == nil {
+		return
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.account != nil {
+		pc.monitors += n
+	}
 }
@@ -0,0 +1,87 @@
+package uptimerobot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ActivityEvent represents a change made to a monitor, such as starting or
+// pausing it, as reconstructed from the monitor's logs. The Type field holds
+// the corresponding log type (for example LogTypePaused), which may be one
+// this package has no constant for.
+type ActivityEvent struct {
+	MonitorID    MonitorID
+	FriendlyName string
+	Type         int
+	Time         time.Time
+}
+
+// String returns a one-line description of the event.
+func (e ActivityEvent) String() string {
+	return fmt.Sprintf("%s %s %d (%s)", e.Time.Format("2006-01-02 15:04:05"), e.FriendlyAction(), e.MonitorID, e.FriendlyName)
+}
+
+// FriendlyAction returns a human-readable name for the event type, or the type
+// number if it is not a known type.
+func (e ActivityEvent) FriendlyAction() string {
+	switch e.Type {
+	case LogTypeStarted:
+		return "Started"
+	case LogTypePaused:
+		return "Paused"
+	default:
+		return fmt.Sprintf("%d", e.Type)
+	}
+}
+
+// Activity returns a timeline of changes made to the monitors in the account
+// since the specified time, oldest first. The timeline is reconstructed from
+// each monitor's logs: every entry except those recording that the monitor
+// went down or came back up (LogTypeDown and LogTypeUp) is included, so any
+// log types other than LogTypeStarted and LogTypePaused are reported too.
+//
+// The API doesn't log the creation or editing of monitors, so these changes
+// can't be reported, and nor can changes to monitors which have since been
+// deleted.
+func (c *Client) Activity(since time.Time) ([]ActivityEvent, error) {
+	return c.ActivityContext(context.Background(), since)
+}
+
+// ActivityContext is like Activity, but uses the specified context for its API
+// requests.
+func (c *Client) ActivityContext(ctx context.Context, since time.Time) ([]ActivityEvent, error) {
+	monitors, err := c.AllMonitorsContext(ctx, WithLogsSince(since))
+	if err != nil {
+		return nil, err
+	}
+	return activity(monitors, since), nil
+}
+
+// activity extracts the activity events since the specified time from the
+// logs of the supplied monitors, ignoring any earlier entries, even though
+// ActivityContext asks the API only for logs since then.
+func activity(monitors []Monitor, since time.Time) []ActivityEvent {
+	events := []ActivityEvent{}
+	for _, m := range monitors {
+		for _, l := range m.Logs {
+			if l.Type == LogTypeDown || l.Type == LogTypeUp {
+				continue
+			}
+			if l.Datetime.Before(since) {
+				continue
+			}
+			events = append(events, ActivityEvent{
+				MonitorID:    m.ID,
+				FriendlyName: m.FriendlyName,
+				Type:         l.Type,
+				Time:         l.Datetime,
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events
+}
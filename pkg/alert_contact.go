@@ -1,21 +1,292 @@
 package uptimerobot
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sync"
+)
+
 // AlertContact represents an alert contact.
 type AlertContact struct {
-	ID           string `json:"id"`
-	FriendlyName string `json:"friendly_name"`
-	Type         int    `json:"type"`
-	Status       int    `json:"status"`
-	Value        string `json:"value"`
+	ID           ContactID `json:"id"`
+	FriendlyName string    `json:"friendly_name"`
+	Type         int       `json:"type"`
+	Status       int       `json:"status"`
+	Value        string    `json:"value"`
 }
 
-const alertContactTemplate = `ID: {{ .ID }}
+// AlertContactTemplate is the template used by AlertContact.String. You can use
+// it as the starting point for your own template, to pass to
+// AlertContact.Render.
+const AlertContactTemplate = `ID: {{ .ID }}
 Name: {{ .FriendlyName }}
-Type: {{ .Type }}
+Type: {{ .FriendlyType }}
 Status: {{ .Status }}
 Value: {{ .Value }}`
 
 // String returns a pretty-printed version of the alert contact.
 func (a AlertContact) String() string {
-	return render(alertContactTemplate, a)
+	return renderString(AlertContactTemplate, a)
+}
+
+// Render returns the result of executing the specified text/template template
+// with the alert contact as its data, or an error if the template is invalid or
+// fails. The template can use the functions listed in TemplateFuncs.
+func (a AlertContact) Render(tmpl string) (string, error) {
+	return render(tmpl, a)
+}
+
+// UnmarshalJSON converts a JSON alert contact representation to an
+// AlertContact struct, handling the API's encoding of integer fields as
+// quoted numbers or empty strings.
+func (a *AlertContact) UnmarshalJSON(data []byte) error {
+	type AlertContactAlias AlertContact
+	aux := struct {
+		AlertContactAlias
+		Type   FlexInt `json:"type"`
+		Status FlexInt `json:"status"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*a = AlertContact(aux.AlertContactAlias)
+	a.Type = int(aux.Type)
+	a.Status = int(aux.Status)
+	return nil
+}
+
+// FriendlyType returns a human-readable name for the alert contact type.
+func (a AlertContact) FriendlyType() string {
+	return AlertContactType(a.Type).String()
+}
+
+// phoneNumber matches a phone number in international format, such as
+// +447700900123, with an optional leading plus sign.
+var phoneNumber = regexp.MustCompile(`^\+?[1-9][0-9]{6,14}$`)
+
+// validate checks that the alert contact's Value is well-formed for its Type:
+// an email address for email contacts, an HTTP or HTTPS URL for webhook,
+// Zapier, and Slack contacts, and a phone number for SMS contacts. Values for
+// other types are not checked.
+func (a AlertContact) validate() error {
+	switch a.Type {
+	case AlertContactTypeEmail:
+		addr, err := mail.ParseAddress(a.Value)
+		if err != nil || addr.Address != a.Value {
+			return fmt.Errorf("invalid email address %q", a.Value)
+		}
+	case AlertContactTypeWebhook, AlertContactTypeZapier, AlertContactTypeSlack:
+		u, err := url.Parse(a.Value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid URL %q", a.Value)
+		}
+	case AlertContactTypeSMS:
+		if !phoneNumber.MatchString(a.Value) {
+			return fmt.Errorf("invalid phone number %q (want international format, for example +447700900123)", a.Value)
+		}
+	}
+	return nil
+}
+
+// CreateAlertContact takes an AlertContact with the FriendlyName, Type, and
+// Value fields set, and creates a new alert contact with the specified
+// details. It returns the ID of the newly created contact, or an error if the
+// operation failed. The Value is checked before calling the API, so that, for
+// example, a mistyped email address or webhook URL is reported immediately.
+func (c *Client) CreateAlertContact(a AlertContact) (ContactID, error) {
+	return c.CreateAlertContactContext(context.Background(), a)
+}
+
+// CreateAlertContactContext is like CreateAlertContact, but uses the specified
+// context for its API requests.
+func (c *Client) CreateAlertContactContext(ctx context.Context, a AlertContact) (ContactID, error) {
+	if err := a.validate(); err != nil {
+		return "", err
+	}
+	req := newAlertContactRequest{
+		Type:         a.Type,
+		Value:        a.Value,
+		FriendlyName: a.FriendlyName,
+	}
+	r := Response{}
+	if err := c.call(ctx, "newAlertContact", req, &r); err != nil {
+		return "", err
+	}
+	c.contacts.reset()
+	return r.AlertContact.ID, nil
+}
+
+// AlertContactByName returns the alert contact whose FriendlyName matches the
+// specified name, or an error if there is no such contact, or more than one.
+// The account's alert contacts are fetched on the first call and cached by the
+// client, so that resolving several names makes only one API call.
+func (c *Client) AlertContactByName(name string) (AlertContact, error) {
+	return c.AlertContactByNameContext(context.Background(), name)
+}
+
+// AlertContactByNameContext is like AlertContactByName, but uses the specified
+// context for its API requests.
+func (c *Client) AlertContactByNameContext(ctx context.Context, name string) (AlertContact, error) {
+	contacts, err := c.contacts.get(ctx, c.AllAlertContactsContext)
+	if err != nil {
+		return AlertContact{}, err
+	}
+	matches := []AlertContact{}
+	for _, a := range contacts {
+		if a.FriendlyName == name {
+			matches = append(matches, a)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return AlertContact{}, fmt.Errorf("alert contact %q not found", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return AlertContact{}, fmt.Errorf("%d alert contacts are named %q; use the contact ID instead", len(matches), name)
+	}
+}
+
+// contactCache caches the account's alert contacts for AlertContactByName, so
+// that they are fetched at most once, even by concurrent calls. A nil
+// contactCache caches nothing.
+type contactCache struct {
+	mu       sync.Mutex
+	contacts []AlertContact
+}
+
+// get returns the cached contacts, first calling fetch to fetch them if
+// necessary.
+func (cc *contactCache) get(ctx context.Context, fetch func(context.Context) ([]AlertContact, error)) ([]AlertContact, error) {
+	if cc == nil {
+		return fetch(ctx)
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.contacts == nil {
+		contacts, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cc.contacts = contacts
+	}
+	return cc.contacts, nil
+}
+
+// reset empties the cache, so that the contacts are fetched again when next
+// needed.
+func (cc *contactCache) reset() {
+	if cc == nil {
+		return
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.contacts = nil
+}
+
+// AddAlertContactToMonitor assigns the specified alert contact to an existing
+// monitor, keeping any alert contacts already assigned to it. If the contact is
+// already assigned, the monitor is not changed.
+func (c *Client) AddAlertContactToMonitor(monitorID MonitorID, contactID ContactID) error {
+	return c.AddAlertContactToMonitorContext(context.Background(), monitorID, contactID)
+}
+
+// AddAlertContactToMonitorContext is like AddAlertContactToMonitor, but uses
+// the specified context for its API requests.
+func (c *Client) AddAlertContactToMonitorContext(ctx context.Context, monitorID MonitorID, contactID ContactID) error {
+	m, err := c.GetMonitorContext(ctx, monitorID)
+	if err != nil {
+		return err
+	}
+	_, err = c.addAlertContact(ctx, m, contactID)
+	return err
+}
+
+// AddAlertContactBySearch assigns the specified alert contact to every monitor
+// whose FriendlyName or URL matches the search string, keeping any alert
+// contacts already assigned to them. It returns the IDs of the monitors which
+// were changed. Monitors which already have the contact assigned are not
+// changed.
+//
+// Since this may make many API requests, consider setting the client's
+// RequestInterval to stay within the API's rate limit. If the operation fails,
+// it returns the IDs of the monitors changed so far, together with the error.
+func (c *Client) AddAlertContactBySearch(s string, contactID ContactID) ([]MonitorID, error) {
+	return c.AddAlertContactBySearchContext(context.Background(), s, contactID)
+}
+
+// AddAlertContactBySearchContext is like AddAlertContactBySearch, but uses the
+// specified context for its API requests.
+func (c *Client) AddAlertContactBySearchContext(ctx context.Context, s string, contactID ContactID) ([]MonitorID, error) {
+	monitors, err := c.SearchMonitorsContext(ctx, s, WithAlertContacts())
+	if err != nil {
+		return nil, err
+	}
+	changed := []MonitorID{}
+	for _, m := range monitors {
+		ok, err := c.addAlertContact(ctx, m, contactID)
+		if err != nil {
+			return changed, err
+		}
+		if ok {
+			changed = append(changed, m.ID)
+		}
+	}
+	return changed, nil
+}
+
+// addAlertContact assigns the specified alert contact to the monitor, whose
+// AlertContacts field must be populated, and reports whether the monitor was
+// changed.
+func (c *Client) addAlertContact(ctx context.Context, m Monitor, contactID ContactID) (bool, error) {
+	for _, ID := range m.AlertContacts {
+		if ID == contactID {
+			return false, nil
+		}
+	}
+	contacts := append(m.AlertContacts, contactID)
+	_, err := c.EditMonitorContext(ctx, EditMonitorParams{
+		ID:              m.ID,
+		AlertContacts:   &contacts,
+		ContactSettings: m.ContactSettings,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveAlertContactFromMonitor removes the specified alert contact from an
+// existing monitor, keeping any other alert contacts assigned to it. If the
+// contact is not assigned, the monitor is not changed.
+func (c *Client) RemoveAlertContactFromMonitor(monitorID MonitorID, contactID ContactID) error {
+	return c.RemoveAlertContactFromMonitorContext(context.Background(), monitorID, contactID)
+}
+
+// RemoveAlertContactFromMonitorContext is like RemoveAlertContactFromMonitor,
+// but uses the specified context for its API requests.
+func (c *Client) RemoveAlertContactFromMonitorContext(ctx context.Context, monitorID MonitorID, contactID ContactID) error {
+	m, err := c.GetMonitorContext(ctx, monitorID)
+	if err != nil {
+		return err
+	}
+	contacts := []ContactID{}
+	for _, ID := range m.AlertContacts {
+		if ID != contactID {
+			contacts = append(contacts, ID)
+		}
+	}
+	if len(contacts) == len(m.AlertContacts) {
+		return nil
+	}
+	_, err = c.EditMonitorContext(ctx, EditMonitorParams{
+		ID:              monitorID,
+		AlertContacts:   &contacts,
+		ContactSettings: m.ContactSettings,
+	})
+	return err
 }
@@ -0,0 +1,65 @@
+package uptimerobot
+
+import "context"
+
+// API is the set of Client methods for managing an account's monitors and
+// alert contacts. Code which uses an API, rather than a *Client, can be tested
+// against an in-memory fake, such as uptimerobottest.FakeClient, instead of
+// the real Uptime Robot API.
+type API interface {
+	GetAccountDetails() (Account, error)
+	GetAccountDetailsContext(ctx context.Context) (Account, error)
+	GetMonitor(ID MonitorID, opts ...Option) (Monitor, error)
+	GetMonitorContext(ctx context.Context, ID MonitorID, opts ...Option) (Monitor, error)
+	GetMonitorsByIDs(IDs []MonitorID, opts ...Option) ([]Monitor, error)
+	GetMonitorsByIDsContext(ctx context.Context, IDs []MonitorID, opts ...Option) ([]Monitor, error)
+	AllMonitors(opts ...Option) ([]Monitor, error)
+	AllMonitorsContext(ctx context.Context, opts ...Option) ([]Monitor, error)
+	Monitors(fn func(Monitor) bool, opts ...Option) error
+	MonitorsContext(ctx context.Context, fn func(Monitor) bool, opts ...Option) error
+	GetMonitorsPage(offset, limit int, opts ...Option) (MonitorPage, error)
+	GetMonitorsPageContext(ctx context.Context, offset, limit int, opts ...Option) (MonitorPage, error)
+	MonitorCount(opts ...Option) (int, error)
+	MonitorCountContext(ctx context.Context, opts ...Option) (int, error)
+	SearchMonitors(s string, opts ...Option) ([]Monitor, error)
+	SearchMonitorsContext(ctx context.Context, s string, opts ...Option) ([]Monitor, error)
+	GetMonitorsByURL(URL string, opts ...Option) ([]Monitor, error)
+	GetMonitorsByURLContext(ctx context.Context, URL string, opts ...Option) ([]Monitor, error)
+	CreateMonitor(m Monitor) (MonitorID, error)
+	CreateMonitorContext(ctx context.Context, m Monitor) (MonitorID, error)
+	EditMonitor(p EditMonitorParams) (Monitor, error)
+	EditMonitorContext(ctx context.Context, p EditMonitorParams) (Monitor, error)
+	PauseMonitor(m Monitor) (Monitor, error)
+	PauseMonitorContext(ctx context.Context, m Monitor) (Monitor, error)
+	StartMonitor(m Monitor) (Monitor, error)
+	StartMonitorContext(ctx context.Context, m Monitor) (Monitor, error)
+	DeleteMonitor(ID MonitorID) error
+	DeleteMonitorContext(ctx context.Context, ID MonitorID) error
+	AllAlertContacts() ([]AlertContact, error)
+	AllAlertContactsContext(ctx context.Context) ([]AlertContact, error)
+	GetAlertContact(ID ContactID) (AlertContact, error)
+	GetAlertContactContext(ctx context.Context, ID ContactID) (AlertContact, error)
+	CreateAlertContact(a AlertContact) (ContactID, error)
+	CreateAlertContactContext(ctx context.Context, a AlertContact) (ContactID, error)
+	AddAlertContactToMonitor(monitorID MonitorID, contactID ContactID) error
+	AddAlertContactToMonitorContext(ctx context.Context, monitorID MonitorID, contactID ContactID) error
+	RemoveAlertContactFromMonitor(monitorID MonitorID, contactID ContactID) error
+	RemoveAlertContactFromMonitorContext(ctx context.Context, monitorID MonitorID, contactID ContactID) error
+}
+
+var _ API = (*Client)(nil)
+
+// MonitorStore is the set of monitor operations used by the high-level
+// helpers EnsureMonitorsIn and SyncMonitorsIn, so that they work with any
+// client which manages monitors: *Client, for the v2 API, or the v3 package's
+// Client, for the newer v3 API. Implementations may ignore options which
+// their API doesn't support, but must return each monitor's alert contacts.
+type MonitorStore interface {
+	AllMonitorsContext(ctx context.Context, opts ...Option) ([]Monitor, error)
+	GetMonitorsByURLContext(ctx context.Context, URL string, opts ...Option) ([]Monitor, error)
+	CreateMonitorContext(ctx context.Context, m Monitor) (MonitorID, error)
+	EditMonitorContext(ctx context.Context, p EditMonitorParams) (Monitor, error)
+	DeleteMonitorContext(ctx context.Context, ID MonitorID) error
+}
+
+var _ MonitorStore = (*Client)(nil)
@@ -0,0 +1,54 @@
+package uptimerobot
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchOp is an operation to be run by Batch, such as creating or deleting a
+// monitor. It should use the supplied context and client for its API calls.
+type BatchOp func(ctx context.Context, c *Client) error
+
+// Batch runs the specified operations using up to the specified number of
+// concurrent workers (at least one), and returns the error from each
+// operation, in the same order as the operations, or nil if it succeeded.
+// Operations which need to return results can store them in variables
+// captured by the BatchOp.
+//
+// The workers share the client's rate limiting: requests are spaced at least
+// RequestInterval apart, and rate-limited requests are retried, just as if
+// they were made one at a time.
+func (c *Client) Batch(workers int, ops ...BatchOp) []error {
+	return c.BatchContext(context.Background(), workers, ops...)
+}
+
+// BatchContext is like Batch, but passes the specified context to each
+// operation. If the context is cancelled, operations which have not yet
+// started return the context's error.
+func (c *Client) BatchContext(ctx context.Context, workers int, ops ...BatchOp) []error {
+	if workers < 1 {
+		workers = 1
+	}
+	errs := make([]error, len(ops))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = ops[i](ctx, c)
+			}
+		}()
+	}
+	for i := range ops {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return errs
+}
@@ -2,21 +2,36 @@ package uptimerobot
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
-	"text/template"
 	"time"
 )
 
+// Version is the version of this library, which is sent to the API as part of
+// the User-Agent header.
+const Version = "0.13.2"
+
+// defaultUserAgent identifies requests made by this library.
+const defaultUserAgent = "uptimerobot-go/" + Version
+
 // Client represents an Uptime Robot client.
 //
+// The settings described below can be given as ClientOptions when calling
+// New (for example, WithHTTPClient or WithBaseURL), or by setting the
+// corresponding fields of the returned Client.
+//
 // The HTTPClient field holds a pointer to the HTTP client which will be used to
 // make the requests; the default client is configured with a timeout of 10
 // seconds. If you would like to use a client with different settings, create an
@@ -26,35 +41,474 @@ import (
 // If the Debug field is set to any io.Writer (for example os.Stdout), then the
 // client will dump all HTTP requests and responses to the supplied writer.
 //
+// Listing methods such as AllMonitors fetch records in pages of 50, the most
+// the API allows. If the records are large (for example, monitors requested
+// with WithLogs), set the PageSize field to fetch fewer records per request.
+//
+// If the Logger field is set to a *slog.Logger, the client will log the verb,
+// duration, HTTP status, attempt number, and any API error type of each
+// request at debug level. This is a structured alternative to Debug.
+//
+// To count the client's requests, errors, and rate-limit hits, and measure
+// their latency, add a Hook to the Hooks field with WithHook. The prommetrics
+// package provides a Hook which exports these metrics to Prometheus.
+//
+// Hooks can also trace the client's API calls: the oteltrace package provides
+// a Hook which creates an OpenTelemetry span for each call, recording the
+// verb, HTTP status, and number of attempts.
+//
+// The UserAgent field holds the User-Agent header sent with each request; by
+// default this is 'uptimerobot-go/' followed by the library version. To
+// identify your own program as well, use WithUserAgent.
+//
 // The URL field determines where requests will be sent; by default this is
 // 'https://api.uptimerobot.com', but if you want to use an alternate or test
 // server URL, set it here. For example, if you are writing tests which use the
 // Uptime Robot client and you do not want it to make network calls, create an
 // httptest.NewTLSServer and set the URL field to the test server's URL.
+//
+// The API limits the number of requests you can make per minute (for free
+// accounts, the limit is 10 requests per minute). If a request is rejected
+// because of the limit, the client waits for the time given by the API (or 10
+// seconds, if none is given) and tries again, up to MaxRetries times (3, by
+// default). If the request still fails, the client returns a RateLimitError.
+// To avoid reaching the limit in the first place, set the RequestInterval
+// field, and the client will wait at least this long between requests. For
+// example, to make no more than 10 requests per minute, set RequestInterval to
+// 6 * time.Second. For more control, set the RateLimiter field to a
+// RateLimiter, such as a TokenBucket, which the client consults before every
+// request.
+//
+// Each method which calls the API has a variant whose name ends in Context,
+// such as AllMonitorsContext, which takes a context.Context as its first
+// argument. Use these to cancel calls, or give them deadlines.
+//
+// A Client is safe for concurrent use by multiple goroutines, provided that
+// its exported fields are not changed while it is in use. Concurrent requests
+// share the client's rate limiting: RequestInterval applies across all of
+// them, not to each goroutine separately, and so does RateLimiter.
 type Client struct {
-	apiKey     string
-	HTTPClient *http.Client
-	URL        string
-	Debug      io.Writer
+	apiKey          string
+	HTTPClient      *http.Client
+	URL             string
+	Debug           io.Writer
+	Logger          *slog.Logger
+	Hooks           []Hook
+	UserAgent       string
+	RequestInterval time.Duration
+	RateLimiter     RateLimiter
+	MaxRetries      int
+	PageSize        int
+	pacer           *pacer
+	dryRun          *dryRun
+	readOnly        bool
+	contacts        *contactCache
+	account         *accountCache
+	plan            *planCache
 }
 
-// New takes an Uptime Robot API key and returns a Client. See the documentation
-// for the Client type for configuration options.
-func New(apiKey string) Client {
+// New takes an Uptime Robot API key and returns a Client. The client can be
+// configured by passing any number of ClientOptions, such as WithTimeout:
+//
+//	client := uptimerobot.New(apiKey, uptimerobot.WithTimeout(30*time.Second))
+//
+// See the documentation for the Client type for the default settings.
+func New(apiKey string, opts ...ClientOption) Client {
 	client := Client{
-		apiKey:     apiKey,
-		URL:        "https://api.uptimerobot.com",
-		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		apiKey: apiKey,
+		URL:    "https://api.uptimerobot.com",
+		HTTPClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: newTransport(),
+		},
+		UserAgent:  defaultUserAgent,
+		MaxRetries: 3,
+		pacer:      &pacer{},
+		contacts:   &contactCache{},
+		account:    &accountCache{},
 	}
 	if os.Getenv("UPTIMEROBOT_DEBUG") != "" {
 		client.Debug = os.Stdout
 	}
+	for _, opt := range opts {
+		opt(&client)
+	}
 	return client
 }
 
+// WithKey returns a copy of the client which uses the specified API key, for
+// managing several accounts. The copy shares the client's HTTP client, and so
+// its connections, as well as its logger, hooks, and other settings. Since
+// the API limits each account's requests separately, the copy paces its
+// requests independently of the original; to pace all the requests for an
+// account together, keep and reuse the copy, rather than calling WithKey for
+// each request. The copy shares the client's RateLimiter, if any; to limit its
+// requests separately, set its RateLimiter field to a new one. In dry-run
+// mode, the copy records its own planned requests.
+func (c *Client) WithKey(apiKey string) Client {
+	k := *c
+	k.apiKey = apiKey
+	k.pacer = &pacer{}
+	k.contacts = &contactCache{}
+	k.account = &accountCache{}
+	if c.plan != nil {
+		k.plan = &planCache{}
+	}
+	if c.dryRun != nil {
+		k.dryRun = &dryRun{}
+	}
+	return k
+}
+
+// ClientOption represents a configuration setting which can be passed to New.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to make requests.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithBaseURL sets the URL to which requests are sent, for example the URL of
+// a test server.
+func WithBaseURL(URL string) ClientOption {
+	return func(c *Client) {
+		c.URL = URL
+	}
+}
+
+// WithTimeout sets the timeout for each HTTP request. The client's HTTP client
+// is copied, rather than modified, so that a client passed to WithHTTPClient
+// is not affected.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		hc := *c.HTTPClient
+		hc.Timeout = d
+		c.HTTPClient = &hc
+	}
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections to the API
+// which the client keeps open for reuse by later requests (10, by default).
+// Raise this if you make many concurrent requests, for example with Batch.
+// It has no effect if the HTTP client's Transport is a custom
+// http.RoundTripper, rather than an *http.Transport (see WithTransport).
+func WithMaxIdleConns(n int) ClientOption {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.MaxIdleConns = n
+			t.MaxIdleConnsPerHost = n
+		})
+	}
+}
+
+// WithKeepAlive sets how long an idle connection to the API is kept open for
+// reuse (90 seconds, by default). If d is zero or negative, keep-alives are
+// disabled, and each request uses a new connection. Like WithMaxIdleConns, it
+// has no effect if the HTTP client's Transport is not an *http.Transport.
+func WithKeepAlive(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.DisableKeepAlives = d <= 0
+			if d > 0 {
+				t.IdleConnTimeout = d
+			}
+		})
+	}
+}
+
+// WithProxy sends the client's requests through the HTTP proxy at the
+// specified URL, instead of any proxy given by the environment (see
+// http.ProxyFromEnvironment). If the HTTP client's Transport is a custom
+// http.RoundTripper, rather than an *http.Transport, the proxy is not used;
+// configure it in the RoundTripper instead.
+func WithProxy(proxyURL *url.URL) ClientOption {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.Proxy = http.ProxyURL(proxyURL)
+		})
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for connections to the API,
+// for example to trust an internal certificate authority:
+//
+//	pool := x509.NewCertPool()
+//	pool.AppendCertsFromPEM(caCert)
+//	client := uptimerobot.New(apiKey, uptimerobot.WithTLSConfig(&tls.Config{
+//		RootCAs: pool,
+//	}))
+//
+// The configuration is ignored if the HTTP client's Transport is not an
+// *http.Transport, for example if it was set with WithTransport, so set it
+// in that RoundTripper instead.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.TLSClientConfig = cfg
+		})
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to make requests, keeping the
+// rest of the HTTP client's settings, such as its timeout. Options which tune
+// the transport, such as WithProxy, only apply if it is an *http.Transport.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		hc := *c.HTTPClient
+		hc.Transport = rt
+		c.HTTPClient = &hc
+	}
+}
+
+// WithRetries sets how many times a request which is rejected because of the
+// API's rate limit will be retried.
+func WithRetries(n int) ClientOption {
+	return func(c *Client) {
+		c.MaxRetries = n
+	}
+}
+
+// WithRequestInterval sets the minimum time between requests.
+func WithRequestInterval(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.RequestInterval = d
+	}
+}
+
+// WithRateLimiter sets the RateLimiter which the client consults before each
+// request.
+func WithRateLimiter(l RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.RateLimiter = l
+	}
+}
+
+// WithUserAgent appends a product identifier, such as 'mytool/1.2', to the
+// User-Agent header sent with each request.
+func WithUserAgent(product string) ClientOption {
+	return func(c *Client) {
+		c.UserAgent += " " + product
+	}
+}
+
+// WithPageSize sets the number of records fetched in each request by listing
+// methods such as AllMonitors. Values outside the range 1 to 50 mean 50.
+func WithPageSize(n int) ClientOption {
+	return func(c *Client) {
+		c.PageSize = n
+	}
+}
+
+// WithLogger sets the structured logger to which the outcome of each request
+// is logged.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.Logger = l
+	}
+}
+
+// WithHook adds a Hook which observes the client's API calls.
+func WithHook(h Hook) ClientOption {
+	return func(c *Client) {
+		c.Hooks = append(c.Hooks, h)
+	}
+}
+
+// WithDryRun puts the client in dry-run mode: calls which would change the
+// account, such as CreateMonitor or DeleteMWindow, don't send a request to
+// the API, but record the request they would have sent, which can be
+// retrieved with PlannedRequests. These calls return the zero value for any
+// result, such as the ID of a new monitor. Calls which only read from the
+// account work as normal.
+func WithDryRun() ClientOption {
+	return func(c *Client) {
+		c.dryRun = &dryRun{}
+	}
+}
+
+// WithReadOnly prevents the client from changing the account: calls such as
+// CreateMonitor, EditMonitor, PauseMonitor, or DeleteMonitor return a
+// ReadOnlyError without sending any request. This makes it safe to use an API
+// key with full access for reporting.
+func WithReadOnly() ClientOption {
+	return func(c *Client) {
+		c.readOnly = true
+	}
+}
+
+// WithPlanChecks makes the client check new monitors against the limits of
+// the account's plan before creating them, so that a monitor whose interval
+// is below the plan's minimum, or which would exceed the plan's monitor limit,
+// is rejected with a clear PlanLimitError rather than an API error. The
+// account details are fetched when the first monitor is created, and cached.
+// The number of monitors is tracked as the client creates and deletes them,
+// but monitors created or deleted by other clients aren't noticed.
+func WithPlanChecks() ClientOption {
+	return func(c *Client) {
+		c.plan = &planCache{}
+	}
+}
+
+// WithDebugWriter sets the writer to which HTTP requests and responses are
+// dumped.
+func WithDebugWriter(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.Debug = w
+	}
+}
+
+// defaultMaxIdleConns is the number of idle connections to the API which a
+// new client keeps open for reuse.
+const defaultMaxIdleConns = 10
+
+// newTransport returns the HTTP transport for a new client: a copy of
+// http.DefaultTransport, which negotiates gzip compression and keeps
+// connections alive between requests, but which keeps enough idle connections
+// for concurrent requests to reuse them too.
+func newTransport() *http.Transport {
+	t := &http.Transport{}
+	if dt, ok := http.DefaultTransport.(*http.Transport); ok {
+		t = dt.Clone()
+	}
+	t.MaxIdleConns = defaultMaxIdleConns
+	t.MaxIdleConnsPerHost = defaultMaxIdleConns
+	return t
+}
+
+// configureTransport applies fn to the client's HTTP transport. The HTTP
+// client and transport are copied, rather than modified, so that a client
+// passed to WithHTTPClient is not affected. If the HTTP client uses a custom
+// http.RoundTripper, rather than an *http.Transport, it is left unchanged.
+func (c *Client) configureTransport(fn func(*http.Transport)) {
+	hc := *c.HTTPClient
+	var t *http.Transport
+	switch rt := hc.Transport.(type) {
+	case nil:
+		t = newTransport()
+	case *http.Transport:
+		t = rt.Clone()
+	default:
+		return
+	}
+	fn(t)
+	hc.Transport = t
+	c.HTTPClient = &hc
+}
+
 // Error represents an API error response.
 type Error map[string]interface{}
 
+// NotFoundError is returned when an operation refers to an object, such as a
+// maintenance window, which does not exist. Resource describes the kind of
+// object, and ID gives the ID which was not found.
+type NotFoundError struct {
+	Resource string
+	ID       string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Resource, e.ID)
+}
+
+// ErrMonitorNotFound is matched by the NotFoundError returned when a monitor
+// does not exist, so that callers can distinguish a missing monitor from other
+// failures with errors.Is:
+//
+//	m, err := client.GetMonitor(ID)
+//	if errors.Is(err, uptimerobot.ErrMonitorNotFound) {
+//		// create it
+//	}
+var ErrMonitorNotFound = errors.New("monitor not found")
+
+// Is reports whether target is ErrMonitorNotFound, and e refers to a monitor.
+func (e NotFoundError) Is(target error) bool {
+	return target == ErrMonitorNotFound && e.Resource == "monitor"
+}
+
+// ReadOnlyError is returned when a client created with WithReadOnly is asked
+// to make a change to the account. Verb gives the API call which was refused.
+type ReadOnlyError struct {
+	Verb string
+}
+
+func (e ReadOnlyError) Error() string {
+	return fmt.Sprintf("client is read-only: refusing to call %s", e.Verb)
+}
+
+// maxErrorBodyLength is the maximum number of bytes of a response body
+// included in an APIError.
+const maxErrorBodyLength = 512
+
+// APIError is returned when an API call fails because the API returned an
+// error, a response with an unexpected HTTP status, or a response which
+// couldn't be decoded. Verb gives the API call, and StatusCode the HTTP
+// status of the response. For errors returned by the API, Type and Message
+// give the error type (such as "invalid_parameter") and message, and Details
+// holds the complete error object. Body holds the raw response body,
+// truncated to 512 bytes, and Err any error decoding it.
+type APIError struct {
+	Verb       string
+	StatusCode int
+	Type       string
+	Message    string
+	Details    Error
+	Body       string
+	Err        error
+}
+
+func (e APIError) Error() string {
+	msg := fmt.Sprintf("%s failed (HTTP %d): ", e.Verb, e.StatusCode)
+	switch {
+	case e.Err != nil:
+		return msg + fmt.Sprintf("decoding response: %v: %q", e.Err, e.Body)
+	case e.Details == nil:
+		return msg + fmt.Sprintf("unexpected response: %q", e.Body)
+	}
+	msg += "API error"
+	if e.Type != "" {
+		msg += " " + e.Type
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	extra := []string{}
+	for k, v := range e.Details {
+		if k != "type" && k != "message" {
+			extra = append(extra, fmt.Sprintf("%s: %v", k, v))
+		}
+	}
+	if len(extra) > 0 {
+		sort.Strings(extra)
+		msg += " (" + strings.Join(extra, ", ") + ")"
+	}
+	return msg
+}
+
+func (e APIError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError returns an APIError for the specified response. details is
+// the error object returned by the API, if any, and err any error decoding the
+// response.
+func newAPIError(verb string, status int, body string, details Error, err error) APIError {
+	if len(body) > maxErrorBodyLength {
+		body = strings.ToValidUTF8(body[:maxErrorBodyLength], "") + "..."
+	}
+	e := APIError{
+		Verb:       verb,
+		StatusCode: status,
+		Details:    details,
+		Body:       body,
+		Err:        err,
+	}
+	e.Type, _ = details["type"].(string)
+	e.Message, _ = details["message"].(string)
+	return e
+}
+
 // Pagination represents the pagination info of an API response.
 type Pagination struct {
 	Offset int `json:"offset"`
@@ -62,120 +516,836 @@ type Pagination struct {
 	Total  int `json:"total"`
 }
 
+// UnmarshalJSON decodes pagination details, handling the API's encoding of
+// integer fields as quoted numbers or empty strings.
+func (p *Pagination) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Offset FlexInt `json:"offset"`
+		Limit  FlexInt `json:"limit"`
+		Total  FlexInt `json:"total"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = Pagination{
+		Offset: int(raw.Offset),
+		Limit:  int(raw.Limit),
+		Total:  int(raw.Total),
+	}
+	return nil
+}
+
+// maxRecordsPerRequest is the maximum number of records the API will return
+// in a single response.
+const maxRecordsPerRequest = 50
+
+// pageSize returns the number of records to request in each page of a
+// listing: the client's PageSize, if it is between 1 and
+// maxRecordsPerRequest, or otherwise maxRecordsPerRequest.
+func (c *Client) pageSize() int {
+	if c.PageSize < 1 || c.PageSize > maxRecordsPerRequest {
+		return maxRecordsPerRequest
+	}
+	return c.PageSize
+}
+
 // Response represents an API response.
+//
+// Some API calls, such as getAlertContacts, return their pagination info in
+// the Offset, Limit, and Total fields instead of the Pagination field.
 type Response struct {
 	Stat          string         `json:"stat"`
 	Account       Account        `json:"account"`
 	Monitors      []Monitor      `json:"monitors"`
 	Monitor       Monitor        `json:"monitor"`
 	AlertContacts []AlertContact `json:"alert_contacts"`
+	AlertContact  AlertContact   `json:"alertcontact"`
+	MWindows      []MWindow      `json:"mwindows"`
+	MWindow       MWindow        `json:"mwindow"`
+	PSPs          []PSP          `json:"psps"`
+	PSP           PSP            `json:"psp"`
 	Error         Error          `json:"error,omitempty"`
 	Pagination    Pagination     `json:"pagination"`
+	Offset        int            `json:"offset"`
+	Limit         int            `json:"limit"`
+	Total         int            `json:"total"`
+	Timezone      int            `json:"timezone"`
+	// raw holds the undecoded response body, for Do, which sets keepRaw to ask
+	// for it.
+	raw     []byte
+	keepRaw bool
+}
+
+// UnmarshalJSON decodes an API response, handling the API's encoding of
+// integer fields as quoted numbers or empty strings.
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type ResponseAlias Response
+	aux := struct {
+		ResponseAlias
+		Offset   FlexInt `json:"offset"`
+		Limit    FlexInt `json:"limit"`
+		Total    FlexInt `json:"total"`
+		Timezone FlexInt `json:"timezone"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*r = Response(aux.ResponseAlias)
+	r.Offset = int(aux.Offset)
+	r.Limit = int(aux.Limit)
+	r.Total = int(aux.Total)
+	r.Timezone = int(aux.Timezone)
+	return nil
+}
+
+// Location returns the account's timezone, if it was requested, as a fixed
+// offset from UTC. The API gives the offset in minutes.
+func (r Response) Location() *time.Location {
+	return time.FixedZone("", r.Timezone*60)
+}
+
+// localizeTimes converts the times of all log entries in the response to the
+// account's timezone, if it was requested.
+func (r *Response) localizeTimes() {
+	if r.Timezone == 0 {
+		return
+	}
+	loc := r.Location()
+	for i := range r.Monitors {
+		r.Monitors[i].localizeTimes(loc)
+	}
+	r.Monitor.localizeTimes(loc)
 }
 
 // GetAccountDetails returns an Account representing the account details.
 func (c *Client) GetAccountDetails() (Account, error) {
+	return c.GetAccountDetailsContext(context.Background())
+}
+
+// GetAccountDetailsContext is like GetAccountDetails, but uses the specified
+// context for its API requests.
+func (c *Client) GetAccountDetailsContext(ctx context.Context) (Account, error) {
 	r := Response{}
-	if err := c.MakeAPICall("getAccountDetails", &r, []byte{}); err != nil {
+	if err := c.MakeAPICallContext(ctx, "getAccountDetails", &r, []byte{}); err != nil {
 		return Account{}, err
 	}
 	return r.Account, nil
 }
 
-// GetMonitor takes an int64 representing the ID number of an existing monitor,
-// and returns the corresponding Monitor, or an error if the operation failed.
-func (c *Client) GetMonitor(ID int64) (Monitor, error) {
+// Account returns the account details, fetching them from the API the first
+// time it is called, and returning the same details thereafter, so that
+// features which need the account's limits don't each spend a request. The
+// counts of up, down, and paused monitors are as they were when the details
+// were fetched; to fetch them again, use RefreshAccount, or use
+// GetAccountDetails, which always makes a request.
+func (c *Client) Account() (Account, error) {
+	return c.AccountContext(context.Background())
+}
+
+// AccountContext is like Account, but uses the specified context for its API
+// request, if any.
+func (c *Client) AccountContext(ctx context.Context) (Account, error) {
+	return c.account.get(ctx, c.GetAccountDetailsContext)
+}
+
+// RefreshAccount fetches the account details from the API, replacing those
+// cached by Account, and returns them.
+func (c *Client) RefreshAccount() (Account, error) {
+	return c.RefreshAccountContext(context.Background())
+}
+
+// RefreshAccountContext is like RefreshAccount, but uses the specified context
+// for its API request.
+func (c *Client) RefreshAccountContext(ctx context.Context) (Account, error) {
+	c.account.reset()
+	return c.AccountContext(ctx)
+}
+
+// AccountLocation returns the account's timezone, as a fixed offset from UTC.
+// This is the timezone in which the API interprets the start times of
+// recurring maintenance windows.
+func (c *Client) AccountLocation() (*time.Location, error) {
+	return c.AccountLocationContext(context.Background())
+}
+
+// AccountLocationContext is like AccountLocation, but uses the specified
+// context for its API requests.
+func (c *Client) AccountLocationContext(ctx context.Context) (*time.Location, error) {
+	req := getMonitorsRequest{
+		Timezone: "1",
+		Limit:    "1",
+	}
 	r := Response{}
-	data := []byte(fmt.Sprintf("{\"monitors\": \"%d\"}", ID))
-	if err := c.MakeAPICall("getMonitors", &r, data); err != nil {
+	if err := c.call(ctx, "getMonitors", req, &r); err != nil {
+		return nil, err
+	}
+	return r.Location(), nil
+}
+
+// GetMonitor takes the ID of an existing monitor, and returns the
+// corresponding Monitor, or an error if the operation failed. If there is no
+// such monitor, the error is a NotFoundError, for which errors.Is(err,
+// ErrMonitorNotFound) is true. The monitor's AlertContacts field is always
+// populated, so that the Monitor can safely be modified and passed back to
+// the API.
+func (c *Client) GetMonitor(ID MonitorID, opts ...Option) (Monitor, error) {
+	return c.GetMonitorContext(context.Background(), ID, opts...)
+}
+
+// GetMonitorContext is like GetMonitor, but uses the specified context for its
+// API requests.
+func (c *Client) GetMonitorContext(ctx context.Context, ID MonitorID, opts ...Option) (Monitor, error) {
+	monitors, err := c.GetMonitorsByIDsContext(ctx, []MonitorID{ID}, opts...)
+	if err != nil {
 		return Monitor{}, err
 	}
-	if len(r.Monitors) == 0 {
-		return Monitor{}, fmt.Errorf("monitor %d not found", ID)
+	if len(monitors) == 0 {
+		return Monitor{}, NotFoundError{
+			Resource: "monitor",
+			ID:       ID.String(),
+		}
 	}
-	return r.Monitors[0], nil
+	return monitors[0], nil
 }
 
-// AllMonitors returns a slice of Monitors representing the monitors currently
-// configured in your Uptime Robot account.
-func (c *Client) AllMonitors() ([]Monitor, error) {
+// GetMonitorsByIDs takes a slice of monitor IDs and returns the corresponding
+// Monitors, or an error if the operation failed. This uses a single API call
+// for up to 50 IDs, rather than one call per monitor. IDs with no
+// corresponding monitor are ignored. As with GetMonitor, the monitors'
+// AlertContacts fields are always populated.
+func (c *Client) GetMonitorsByIDs(IDs []MonitorID, opts ...Option) ([]Monitor, error) {
+	return c.GetMonitorsByIDsContext(context.Background(), IDs, opts...)
+}
+
+// GetMonitorsByIDsContext is like GetMonitorsByIDs, but uses the specified
+// context for its API requests.
+func (c *Client) GetMonitorsByIDsContext(ctx context.Context, IDs []MonitorID, opts ...Option) ([]Monitor, error) {
+	opts = append([]Option{WithAlertContacts()}, opts...)
 	monitors := []Monitor{}
-	// This limit is imposed by the API.
-	const maxRecordsPerRequest = 50
-	offset := 0
-	r := Response{}
-	for offset <= r.Pagination.Total {
-		data := []byte(fmt.Sprintf("{\"offset\": \"%d\", \"limit\": \"%d\"}", offset, maxRecordsPerRequest))
-		if err := c.MakeAPICall("getMonitors", &r, data); err != nil {
+	limit := c.pageSize()
+	for start := 0; start < len(IDs); start += limit {
+		end := start + limit
+		if end > len(IDs) {
+			end = len(IDs)
+		}
+		req := getMonitorsRequest{
+			Monitors: joinIDs(IDs[start:end]),
+			Limit:    strconv.Itoa(limit),
+		}
+		o := newOptions(opts)
+		o.apply(&req)
+		r := Response{}
+		if err := c.call(o.callContext(ctx), "getMonitors", req, &r); err != nil {
 			return nil, err
 		}
 		monitors = append(monitors, r.Monitors...)
-		offset = r.Pagination.Offset + maxRecordsPerRequest
 	}
 	return monitors, nil
 }
 
-// SearchMonitors returns a slice of Monitors whose FriendlyName or URL
-// match the search string.
-func (c *Client) SearchMonitors(s string) ([]Monitor, error) {
+// GetLogsSince returns the entries in the event log of the monitor with the
+// specified ID since the specified time, oldest first, so that incident
+// tooling can fetch only recent events rather than the monitor's full history.
+func (c *Client) GetLogsSince(monitorID MonitorID, since time.Time) ([]MonitorLog, error) {
+	return c.GetLogsSinceContext(context.Background(), monitorID, since)
+}
+
+// GetLogsSinceContext is like GetLogsSince, but uses the specified context for
+// its API requests.
+func (c *Client) GetLogsSinceContext(ctx context.Context, monitorID MonitorID, since time.Time) ([]MonitorLog, error) {
+	m, err := c.GetMonitorContext(ctx, monitorID, WithLogsSince(since))
+	if err != nil {
+		return nil, err
+	}
+	// The API only honours the date filters for some plans, so filter the
+	// entries here too
+	logs := []MonitorLog{}
+	for _, l := range m.Logs {
+		if !l.Datetime.Before(since) {
+			logs = append(logs, l)
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Datetime.Before(logs[j].Datetime)
+	})
+	return logs, nil
+}
+
+// AllMonitors returns a slice of Monitors representing the monitors currently
+// configured in your Uptime Robot account. Options such as WithStatuses can be
+// used to restrict the monitors returned.
+func (c *Client) AllMonitors(opts ...Option) ([]Monitor, error) {
+	return c.AllMonitorsContext(context.Background(), opts...)
+}
+
+// AllMonitorsContext is like AllMonitors, but uses the specified context for
+// its API requests.
+func (c *Client) AllMonitorsContext(ctx context.Context, opts ...Option) ([]Monitor, error) {
+	monitors := []Monitor{}
+	err := c.MonitorsContext(ctx, func(m Monitor) bool {
+		monitors = append(monitors, m)
+		return true
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return monitors, nil
+}
+
+// GetMonitorsWithUptimeBelow returns the monitors whose uptime percentage over
+// the last specified number of days is below threshold: for example,
+// GetMonitorsWithUptimeBelow(30, 99.9) returns the monitors which have missed
+// a 99.9% objective over the last 30 days. Each monitor's UptimeRatios field
+// holds its uptime for the period. Monitors for which the API reports no
+// uptime are omitted. Options such as WithStatuses can be used to restrict the
+// monitors considered.
+func (c *Client) GetMonitorsWithUptimeBelow(days int, threshold float64, opts ...Option) ([]Monitor, error) {
+	return c.GetMonitorsWithUptimeBelowContext(context.Background(), days, threshold, opts...)
+}
+
+// GetMonitorsWithUptimeBelowContext is like GetMonitorsWithUptimeBelow, but
+// uses the specified context for its API requests.
+func (c *Client) GetMonitorsWithUptimeBelowContext(ctx context.Context, days int, threshold float64, opts ...Option) ([]Monitor, error) {
+	// Request only the one period, whatever the caller's options asked for,
+	// so that it's the first ratio
+	opts = append(opts, func(o *options) {
+		o.uptimeRatios = []int{days}
+	})
+	monitors := []Monitor{}
+	err := c.MonitorsContext(ctx, func(m Monitor) bool {
+		if len(m.UptimeRatios) > 0 && m.UptimeRatios[0] < threshold {
+			monitors = append(monitors, m)
+		}
+		return true
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return monitors, nil
+}
+
+// Monitors calls fn for each monitor in your Uptime Robot account, fetching
+// them one page at a time, so that even a very large account can be processed
+// without holding all its monitors in memory. If fn returns false, Monitors
+// stops without fetching any more pages. Options such as WithStatuses can be
+// used to restrict the monitors visited.
+func (c *Client) Monitors(fn func(Monitor) bool, opts ...Option) error {
+	return c.MonitorsContext(context.Background(), fn, opts...)
+}
+
+// MonitorsContext is like Monitors, but uses the specified context for its
+// API requests.
+func (c *Client) MonitorsContext(ctx context.Context, fn func(Monitor) bool, opts ...Option) error {
+	_, err := c.MonitorsFromContext(ctx, 0, fn, opts...)
+	return err
+}
+
+// MonitorsFrom is like Monitors, but starts at the specified offset, and
+// returns the offset at which to resume: that of the first monitor not yet
+// passed to fn. If a long listing is interrupted, by an error or because fn
+// returned false, save the offset, and pass it to MonitorsFrom later to carry
+// on from where it stopped, rather than starting again from offset 0. When
+// every monitor has been visited, the offset is the number of monitors.
+//
+// The offset refers to the monitors in the API's order, so it is only
+// meaningful when resuming with the same options, and if monitors are created
+// or deleted in the meantime, some may be skipped or visited twice.
+func (c *Client) MonitorsFrom(offset int, fn func(Monitor) bool, opts ...Option) (int, error) {
+	return c.MonitorsFromContext(context.Background(), offset, fn, opts...)
+}
+
+// MonitorsFromContext is like MonitorsFrom, but uses the specified context for
+// its API requests.
+func (c *Client) MonitorsFromContext(ctx context.Context, offset int, fn func(Monitor) bool, opts ...Option) (int, error) {
+	for {
+		page, err := c.GetMonitorsPageContext(ctx, offset, c.pageSize(), opts...)
+		if err != nil {
+			return offset, err
+		}
+		for _, m := range page.Monitors {
+			offset++
+			if !fn(m) {
+				return offset, nil
+			}
+		}
+		if len(page.Monitors) == 0 || offset >= page.Total {
+			return offset, nil
+		}
+	}
+}
+
+// MonitorPage represents a single page of monitors returned by the API,
+// together with its pagination info. The Total field gives the total number
+// of monitors available.
+type MonitorPage struct {
+	Monitors []Monitor
+	Pagination
+}
+
+// GetMonitorsPage returns the page of monitors starting at the specified
+// offset, containing at most limit monitors (the API allows up to 50). This is
+// useful if you want to control pagination yourself, rather than fetching all
+// monitors at once with AllMonitors.
+func (c *Client) GetMonitorsPage(offset, limit int, opts ...Option) (MonitorPage, error) {
+	return c.GetMonitorsPageContext(context.Background(), offset, limit, opts...)
+}
+
+// GetMonitorsPageContext is like GetMonitorsPage, but uses the specified
+// context for its API requests.
+func (c *Client) GetMonitorsPageContext(ctx context.Context, offset, limit int, opts ...Option) (MonitorPage, error) {
+	req := getMonitorsRequest{
+		Offset: strconv.Itoa(offset),
+		Limit:  strconv.Itoa(limit),
+	}
+	o := newOptions(opts)
+	o.apply(&req)
 	r := Response{}
-	data := []byte(`{"search": "` + s + `"}`)
-	if err := c.MakeAPICall("getMonitors", &r, data); err != nil {
+	if err := c.call(o.callContext(ctx), "getMonitors", req, &r); err != nil {
+		return MonitorPage{}, err
+	}
+	return MonitorPage{
+		Monitors:   r.Monitors,
+		Pagination: r.Pagination,
+	}, nil
+}
+
+// MonitorCount returns the total number of monitors in the account, without
+// fetching them all. Options such as WithStatuses can be used to count only
+// some monitors: for example, those which are down.
+func (c *Client) MonitorCount(opts ...Option) (int, error) {
+	return c.MonitorCountContext(context.Background(), opts...)
+}
+
+// MonitorCountContext is like MonitorCount, but uses the specified context
+// for its API requests.
+func (c *Client) MonitorCountContext(ctx context.Context, opts ...Option) (int, error) {
+	page, err := c.GetMonitorsPageContext(ctx, 0, 1, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return page.Total, nil
+}
+
+// SearchMonitors returns a slice of Monitors whose FriendlyName or URL
+// match the search string, fetching every page of results. Options such as
+// WithSort can be used to control the results.
+func (c *Client) SearchMonitors(s string, opts ...Option) ([]Monitor, error) {
+	return c.SearchMonitorsContext(context.Background(), s, opts...)
+}
+
+// SearchMonitorsContext is like SearchMonitors, but uses the specified context
+// for its API requests.
+func (c *Client) SearchMonitorsContext(ctx context.Context, s string, opts ...Option) ([]Monitor, error) {
+	opts = append(opts[:len(opts):len(opts)], withSearch(s))
+	monitors, err := c.AllMonitorsContext(ctx, opts...)
+	if err != nil {
 		return []Monitor{}, err
 	}
-	return r.Monitors, nil
+	return monitors, nil
+}
+
+// maxSearchWorkers is the maximum number of searches SearchMonitorsAll runs
+// at once.
+const maxSearchWorkers = 4
+
+// SearchMonitorsAll is like SearchMonitors, but searches for each of the
+// specified terms, running up to four searches at once, and returns every
+// monitor which matches any of them. A monitor matching more than one term is
+// returned only once. The monitors are in the order of the terms they match,
+// and then in the order the API returned them. If any search fails, the error
+// is that of the first failed term.
+func (c *Client) SearchMonitorsAll(terms []string, opts ...Option) ([]Monitor, error) {
+	return c.SearchMonitorsAllContext(context.Background(), terms, opts...)
+}
+
+// SearchMonitorsAllContext is like SearchMonitorsAll, but uses the specified
+// context for its API requests.
+func (c *Client) SearchMonitorsAllContext(ctx context.Context, terms []string, opts ...Option) ([]Monitor, error) {
+	results := make([][]Monitor, len(terms))
+	ops := make([]BatchOp, len(terms))
+	for i, term := range terms {
+		i, term := i, term
+		ops[i] = func(ctx context.Context, c *Client) error {
+			var err error
+			results[i], err = c.SearchMonitorsContext(ctx, term, opts...)
+			return err
+		}
+	}
+	for i, err := range c.BatchContext(ctx, maxSearchWorkers, ops...) {
+		if err != nil {
+			return []Monitor{}, fmt.Errorf("searching for %q: %w", terms[i], err)
+		}
+	}
+	seen := map[MonitorID]bool{}
+	monitors := []Monitor{}
+	for _, r := range results {
+		for _, m := range r {
+			if seen[m.ID] {
+				continue
+			}
+			seen[m.ID] = true
+			monitors = append(monitors, m)
+		}
+	}
+	return monitors, nil
 }
 
 // AllAlertContacts returns all the AlertContacts associated with the account.
 func (c *Client) AllAlertContacts() ([]AlertContact, error) {
+	return c.AllAlertContactsContext(context.Background())
+}
+
+// AllAlertContactsContext is like AllAlertContacts, but uses the specified
+// context for its API requests.
+func (c *Client) AllAlertContactsContext(ctx context.Context) ([]AlertContact, error) {
+	contacts := []AlertContact{}
+	offset := 0
 	r := Response{}
-	if err := c.MakeAPICall("getAlertContacts", &r, []byte{}); err != nil {
-		return []AlertContact{}, err
+	for offset <= r.Total {
+		req := getAlertContactsRequest{
+			Offset: strconv.Itoa(offset),
+			Limit:  strconv.Itoa(c.pageSize()),
+		}
+		if err := c.call(ctx, "getAlertContacts", req, &r); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, r.AlertContacts...)
+		offset = r.Offset + c.pageSize()
 	}
-	return r.AlertContacts, nil
+	return contacts, nil
+}
+
+// GetAlertContact takes the ID of an existing alert contact, and returns the
+// corresponding AlertContact, or an error if the operation failed. If there is
+// no such contact, the error is a NotFoundError.
+func (c *Client) GetAlertContact(ID ContactID) (AlertContact, error) {
+	return c.GetAlertContactContext(context.Background(), ID)
+}
+
+// GetAlertContactContext is like GetAlertContact, but uses the specified
+// context for its API requests.
+func (c *Client) GetAlertContactContext(ctx context.Context, ID ContactID) (AlertContact, error) {
+	req := getAlertContactsRequest{
+		AlertContacts: string(ID),
+	}
+	r := Response{}
+	if err := c.call(ctx, "getAlertContacts", req, &r); err != nil {
+		return AlertContact{}, err
+	}
+	if len(r.AlertContacts) == 0 {
+		return AlertContact{}, NotFoundError{
+			Resource: "alert contact",
+			ID:       string(ID),
+		}
+	}
+	return r.AlertContacts[0], nil
+}
+
+// AlertContactCount returns the total number of alert contacts associated
+// with the account, without fetching them all.
+func (c *Client) AlertContactCount() (int, error) {
+	return c.AlertContactCountContext(context.Background())
+}
+
+// AlertContactCountContext is like AlertContactCount, but uses the specified
+// context for its API requests.
+func (c *Client) AlertContactCountContext(ctx context.Context) (int, error) {
+	req := getAlertContactsRequest{
+		Offset: "0",
+		Limit:  "1",
+	}
+	r := Response{}
+	if err := c.call(ctx, "getAlertContacts", req, &r); err != nil {
+		return 0, err
+	}
+	return r.Total, nil
+}
+
+// GetMonitorsByURL returns the monitors whose URL is exactly the specified
+// URL. Unlike SearchMonitors, it doesn't return monitors whose URL or name
+// merely contains the URL. Options such as WithAlertContacts can be used to
+// request additional data.
+func (c *Client) GetMonitorsByURL(URL string, opts ...Option) ([]Monitor, error) {
+	return c.GetMonitorsByURLContext(context.Background(), URL, opts...)
+}
+
+// GetMonitorsByURLContext is like GetMonitorsByURL, but uses the specified
+// context for its API requests.
+func (c *Client) GetMonitorsByURLContext(ctx context.Context, URL string, opts ...Option) ([]Monitor, error) {
+	// Many monitors may contain the URL, so check every page of results, not
+	// just the first
+	matches := []Monitor{}
+	opts = append(opts[:len(opts):len(opts)], withSearch(URL))
+	err := c.MonitorsContext(ctx, func(m Monitor) bool {
+		if m.URL == URL {
+			matches = append(matches, m)
+		}
+		return true
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
 }
 
 // CreateMonitor takes a Monitor and creates a new Uptime Robot monitor with the
 // specified details. It returns the ID of the newly created monitor, or an
-// error if the operation failed.
-func (c *Client) CreateMonitor(m Monitor) (int64, error) {
+// error if the operation failed. If the client was created with
+// WithPlanChecks, the monitor is first checked against the limits of the
+// account's plan.
+func (c *Client) CreateMonitor(m Monitor) (MonitorID, error) {
+	return c.CreateMonitorContext(context.Background(), m)
+}
+
+// CreateMonitorContext is like CreateMonitor, but uses the specified context
+// for its API requests.
+func (c *Client) CreateMonitorContext(ctx context.Context, m Monitor) (MonitorID, error) {
+	if err := c.plan.check(ctx, c.AccountContext, m.Interval); err != nil {
+		return 0, err
+	}
 	r := Response{}
 	data, err := json.Marshal(m)
 	if err != nil {
 		return 0, err
 	}
-	if err := c.MakeAPICall("newMonitor", &r, data); err != nil {
+	if err := c.MakeAPICallContext(ctx, "newMonitor", &r, data); err != nil {
 		return 0, err
 	}
+	c.plan.add(1)
 	return r.Monitor.ID, nil
 }
 
 // EnsureMonitor takes a Monitor and creates a new Uptime Robot monitor with the
-// specified details, if a monitor for the same URL does not already exist. It
-// returns the ID of the newly created monitor or the existing monitor if it
-// already existed, or an error if the operation failed.
-func (c *Client) EnsureMonitor(m Monitor) (int64, error) {
-	monitors, err := c.SearchMonitors(m.URL)
+// specified details, if a monitor for the same URL does not already exist. If
+// it does exist, but its settings differ from those in m, it is edited to
+// match. Settings which are zero in m (for example, an Interval of zero, or no
+// AlertContacts) are left as they are. The monitor's type can't be changed,
+// so if the existing monitor has a different type, EnsureMonitor returns an
+// error.
+//
+// EnsureMonitor returns the ID of the new or existing monitor, and whether it
+// created or changed anything, or an error if the operation failed.
+func (c *Client) EnsureMonitor(m Monitor) (MonitorID, bool, error) {
+	return c.EnsureMonitorContext(context.Background(), m)
+}
+
+// EnsureMonitorContext is like EnsureMonitor, but uses the specified context
+// for its API requests.
+func (c *Client) EnsureMonitorContext(ctx context.Context, m Monitor) (MonitorID, bool, error) {
+	ID, action, err := ensureMonitor(ctx, c, m)
+	return ID, action == EnsureCreated || action == EnsureUpdated, err
+}
+
+// EnsureAction describes what EnsureMonitors did with a monitor. Its String
+// method returns the action's name.
+type EnsureAction int
+
+const (
+	// EnsureFailed means that the monitor couldn't be created or updated.
+	EnsureFailed EnsureAction = iota
+	// EnsureCreated means that the monitor didn't exist, and was created.
+	EnsureCreated
+	// EnsureUpdated means that the monitor existed, and was edited to match.
+	EnsureUpdated
+	// EnsureExisting means that the monitor already existed as specified.
+	EnsureExisting
+)
+
+// String returns the name of the action, such as 'created'.
+func (a EnsureAction) String() string {
+	switch a {
+	case EnsureFailed:
+		return "failed"
+	case EnsureCreated:
+		return "created"
+	case EnsureUpdated:
+		return "updated"
+	case EnsureExisting:
+		return "existing"
+	default:
+		return fmt.Sprintf("%d", int(a))
+	}
+}
+
+// EnsureResult is the outcome of EnsureMonitors for a single monitor. ID is
+// the ID of the new or existing monitor (zero if a new monitor couldn't be
+// created), and Err is nil unless Action is EnsureFailed.
+type EnsureResult struct {
+	Monitor Monitor
+	ID      MonitorID
+	Action  EnsureAction
+	Err     error
+}
+
+// EnsureMonitors calls EnsureMonitor for each of the specified monitors, so
+// that a whole environment can be provisioned in one call. It returns a
+// result for each monitor, in the same order, saying whether it was created,
+// updated, already existed, or failed: a failure for one monitor doesn't stop
+// the others from being ensured. The error is non-nil only if the context was
+// cancelled before all the monitors were ensured.
+//
+// Since this makes at least one API request per monitor, consider setting the
+// client's RequestInterval to stay within the API's rate limit; requests which
+// are rate-limited anyway are retried as usual, up to MaxRetries times.
+func (c *Client) EnsureMonitors(monitors []Monitor) ([]EnsureResult, error) {
+	return c.EnsureMonitorsContext(context.Background(), monitors)
+}
+
+// EnsureMonitorsContext is like EnsureMonitors, but uses the specified context
+// for its API requests.
+func (c *Client) EnsureMonitorsContext(ctx context.Context, monitors []Monitor) ([]EnsureResult, error) {
+	return EnsureMonitorsIn(ctx, c, monitors)
+}
+
+// EnsureMonitorsIn is like EnsureMonitors, but ensures the monitors using the
+// specified MonitorStore, such as a client for the v3 API.
+func EnsureMonitorsIn(ctx context.Context, s MonitorStore, monitors []Monitor) ([]EnsureResult, error) {
+	results := []EnsureResult{}
+	for _, m := range monitors {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		ID, action, err := ensureMonitor(ctx, s, m)
+		results = append(results, EnsureResult{
+			Monitor: m,
+			ID:      ID,
+			Action:  action,
+			Err:     err,
+		})
+	}
+	return results, nil
+}
+
+// ensureMonitor does the work of EnsureMonitor using the specified store, and
+// returns the ID of the new or existing monitor, and what it did.
+func ensureMonitor(ctx context.Context, s MonitorStore, m Monitor) (MonitorID, EnsureAction, error) {
+	monitors, err := s.GetMonitorsByURLContext(ctx, m.URL, WithAlertContacts())
 	if err != nil {
-		return 0, err
+		return 0, EnsureFailed, err
 	}
-	if len(monitors) == 0 {
-		ID, err := c.CreateMonitor(m)
+	if len(monitors) > 0 {
+		existing := monitors[0]
+		p, changes, err := reconcileParams(existing, m)
 		if err != nil {
-			return 0, err
+			return existing.ID, EnsureFailed, err
 		}
-		return ID, nil
+		if len(changes) == 0 {
+			return existing.ID, EnsureExisting, nil
+		}
+		if _, err := s.EditMonitorContext(ctx, p); err != nil {
+			return existing.ID, EnsureFailed, err
+		}
+		return existing.ID, EnsureUpdated, nil
+	}
+	ID, err := s.CreateMonitorContext(ctx, m)
+	if err != nil {
+		return 0, EnsureFailed, err
 	}
-	return monitors[0].ID, nil
+	return ID, EnsureCreated, nil
+}
+
+// reconcileParams returns the EditMonitorParams needed to make the existing
+// monitor match the desired one, and the changes they make. Fields which are
+// zero in desired are not changed, and nor are the port and subtype of
+// monitors other than port monitors, since the API ignores them.
+func reconcileParams(existing, desired Monitor) (EditMonitorParams, []Change, error) {
+	p := EditMonitorParams{ID: existing.ID}
+	changes := []Change{}
+	for _, ch := range Diff(existing, desired) {
+		changed := false
+		switch ch.Field {
+		case "Type":
+			if desired.Type != 0 {
+				return EditMonitorParams{}, nil, fmt.Errorf("monitor %d has type %s, and the API can't change a monitor's type", existing.ID, existing.FriendlyType())
+			}
+		case "FriendlyName":
+			if desired.FriendlyName != "" {
+				p.FriendlyName = String(desired.FriendlyName)
+				changed = true
+			}
+		case "SubType":
+			if desired.SubType != 0 && existing.Type == TypePort {
+				p.SubType = Int(desired.SubType)
+				changed = true
+			}
+		case "Port":
+			if desired.Port != 0 && existing.Type == TypePort {
+				p.Port = Int(desired.Port)
+				changed = true
+			}
+		case "KeywordType":
+			if desired.KeywordType != 0 {
+				p.KeywordType = Int(desired.KeywordType)
+				changed = true
+			}
+		case "KeywordValue":
+			if desired.KeywordValue != "" {
+				p.KeywordValue = String(desired.KeywordValue)
+				changed = true
+			}
+		case "AlertContacts":
+			if len(desired.AlertContacts) > 0 {
+				contacts := desired.AlertContacts
+				p.AlertContacts = &contacts
+				p.ContactSettings = mergeContactSettings(existing.ContactSettings, desired.ContactSettings)
+				changed = true
+			}
+		case "Interval":
+			if desired.Interval != 0 {
+				p.Interval = Duration(desired.Interval)
+				changed = true
+			}
+		case "Timeout":
+			if desired.Timeout != 0 {
+				p.Timeout = Duration(desired.Timeout)
+				changed = true
+			}
+		case "IgnoreSSLErrors":
+			if desired.IgnoreSSLErrors {
+				p.IgnoreSSLErrors = Bool(true)
+				changed = true
+			}
+		}
+		if changed {
+			changes = append(changes, ch)
+		}
+	}
+	return p, changes, nil
+}
+
+// mergeContactSettings returns the existing alert contact settings, updated
+// with any given in desired, so that changing a monitor's alert contacts
+// doesn't reset the thresholds and recurrences of the contacts it keeps.
+func mergeContactSettings(existing, desired map[ContactID]ContactSettings) map[ContactID]ContactSettings {
+	if len(existing) == 0 {
+		return desired
+	}
+	merged := make(map[ContactID]ContactSettings, len(existing)+len(desired))
+	for ID, cs := range existing {
+		merged[ID] = cs
+	}
+	for ID, cs := range desired {
+		merged[ID] = cs
+	}
+	return merged
 }
 
 // PauseMonitor takes a Monitor with the ID field set, and attempts to set the
 // monitor status to paused via the API. It returns a Monitor with the ID field
 // set to the ID of the monitor, or an error if the operation failed.
 func (c *Client) PauseMonitor(m Monitor) (Monitor, error) {
+	return c.PauseMonitorContext(context.Background(), m)
+}
+
+// PauseMonitorContext is like PauseMonitor, but uses the specified context for
+// its API requests.
+func (c *Client) PauseMonitorContext(ctx context.Context, m Monitor) (Monitor, error) {
+	req := editMonitorStatusRequest{
+		ID:     m.ID,
+		Status: EditStatusPause,
+	}
 	r := Response{}
-	data := []byte(fmt.Sprintf("{\"id\": \"%d\",\"status\": %d}", m.ID, StatusPaused))
-	if err := c.MakeAPICall("editMonitor", &r, data); err != nil {
+	if err := c.call(ctx, "editMonitor", req, &r); err != nil {
 		return Monitor{}, err
 	}
 	return r.Monitor, nil
@@ -186,76 +1356,353 @@ func (c *Client) PauseMonitor(m Monitor) (Monitor, error) {
 // the ID field set to the ID of the monitor, or an error if the operation
 // failed.
 func (c *Client) StartMonitor(m Monitor) (Monitor, error) {
+	return c.StartMonitorContext(context.Background(), m)
+}
+
+// StartMonitorContext is like StartMonitor, but uses the specified context for
+// its API requests.
+func (c *Client) StartMonitorContext(ctx context.Context, m Monitor) (Monitor, error) {
+	req := editMonitorStatusRequest{
+		ID:     m.ID,
+		Status: EditStatusResume,
+	}
 	r := Response{}
-	data := []byte(fmt.Sprintf("{\"id\": \"%d\",\"status\": %d}", m.ID, StatusResumed))
-	if err := c.MakeAPICall("editMonitor", &r, data); err != nil {
+	if err := c.call(ctx, "editMonitor", req, &r); err != nil {
 		return Monitor{}, err
 	}
 	return r.Monitor, nil
 }
 
+// WaitForStatus polls the monitor with the specified ID every pollInterval
+// until its status is want, and returns the monitor. If ctx expires first,
+// WaitForStatus returns an error wrapping the context's error, which records
+// the monitor's last known status. This is useful, for example, to hold up a
+// deployment until Uptime Robot sees the new endpoint as up. pollInterval
+// must be positive.
+func (c *Client) WaitForStatus(ctx context.Context, monitorID MonitorID, want Status, pollInterval time.Duration) (Monitor, error) {
+	if pollInterval <= 0 {
+		return Monitor{}, fmt.Errorf("poll interval must be positive, not %s", pollInterval)
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	var last *Monitor
+	expired := func() (Monitor, error) {
+		return *last, fmt.Errorf("monitor %d is %s, not %s: %w", monitorID, last.Status, want, ctx.Err())
+	}
+	for {
+		m, err := c.GetMonitorContext(ctx, monitorID)
+		if err != nil {
+			if last != nil && ctx.Err() != nil {
+				return expired()
+			}
+			return Monitor{}, err
+		}
+		if m.Status == want {
+			return m, nil
+		}
+		last = &m
+		select {
+		case <-ctx.Done():
+			return expired()
+		case <-ticker.C:
+		}
+	}
+}
+
+// PauseMonitorFor pauses the monitor with the specified ID, waits for the
+// duration d, and then resumes it, so that a short maintenance doesn't depend
+// on someone remembering to start the monitor again. If ctx is cancelled while
+// waiting, the monitor is resumed straight away, and PauseMonitorFor returns
+// the context's error.
+func (c *Client) PauseMonitorFor(ctx context.Context, monitorID MonitorID, d time.Duration) error {
+	m := Monitor{
+		ID: monitorID,
+	}
+	if _, err := c.PauseMonitorContext(ctx, m); err != nil {
+		return err
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	var waitErr error
+	select {
+	case <-ctx.Done():
+		waitErr = ctx.Err()
+	case <-timer.C:
+	}
+	// Resume the monitor even if ctx has been cancelled, so that it isn't
+	// left paused
+	if _, err := c.StartMonitorContext(context.WithoutCancel(ctx), m); err != nil {
+		return fmt.Errorf("resuming monitor %d: %w", monitorID, err)
+	}
+	return waitErr
+}
+
+// MonitorResult is the outcome of a bulk operation, such as PauseAll, on a
+// single monitor. Err is nil if the operation succeeded for that monitor.
+type MonitorResult struct {
+	Monitor Monitor
+	Err     error
+}
+
+// PauseAll pauses every monitor whose FriendlyName or URL matches the search
+// string, or every monitor if the search string is empty. Monitors which are
+// already paused are left unchanged.
+//
+// It returns a result for each monitor it tried to pause: a failure to pause
+// one monitor doesn't stop the others from being paused. The error is non-nil
+// only if the monitors couldn't be listed, or the context was cancelled before
+// all the monitors were paused. Since this may make many API requests,
+// consider setting the client's RequestInterval to stay within the API's rate
+// limit.
+func (c *Client) PauseAll(s string) ([]MonitorResult, error) {
+	return c.PauseAllContext(context.Background(), s)
+}
+
+// PauseAllContext is like PauseAll, but uses the specified context for its API
+// requests.
+func (c *Client) PauseAllContext(ctx context.Context, s string) ([]MonitorResult, error) {
+	return c.setStatusAll(ctx, s, func(m Monitor) bool {
+		return m.Status != StatusPaused
+	}, c.PauseMonitorContext)
+}
+
+// StartAll starts (unpauses) every paused monitor whose FriendlyName or URL
+// matches the search string, or every paused monitor if the search string is
+// empty. It reports results and errors in the same way as PauseAll.
+func (c *Client) StartAll(s string) ([]MonitorResult, error) {
+	return c.StartAllContext(context.Background(), s)
+}
+
+// StartAllContext is like StartAll, but uses the specified context for its API
+// requests.
+func (c *Client) StartAllContext(ctx context.Context, s string) ([]MonitorResult, error) {
+	return c.setStatusAll(ctx, s, func(m Monitor) bool {
+		return m.Status == StatusPaused
+	}, c.StartMonitorContext)
+}
+
+// setStatusAll calls set for every monitor matching the search string (or
+// every monitor, if it is empty) for which want returns true.
+func (c *Client) setStatusAll(ctx context.Context, s string, want func(Monitor) bool, set func(context.Context, Monitor) (Monitor, error)) ([]MonitorResult, error) {
+	opts := []Option{}
+	if s != "" {
+		opts = append(opts, withSearch(s))
+	}
+	monitors, err := c.AllMonitorsContext(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	results := []MonitorResult{}
+	for _, m := range monitors {
+		if !want(m) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		_, err := set(ctx, m)
+		results = append(results, MonitorResult{Monitor: m, Err: err})
+	}
+	return results, nil
+}
+
 // DeleteMonitor takes a monitor ID and deletes the corresponding monitor. It returns
 // an error if the operation failed.
-func (c *Client) DeleteMonitor(ID int64) error {
-	data := []byte(fmt.Sprintf("{\"id\": \"%d\"}", ID))
-	if err := c.MakeAPICall("deleteMonitor", &Response{}, data); err != nil {
+func (c *Client) DeleteMonitor(ID MonitorID) error {
+	return c.DeleteMonitorContext(context.Background(), ID)
+}
+
+// DeleteMonitorContext is like DeleteMonitor, but uses the specified context
+// for its API requests.
+func (c *Client) DeleteMonitorContext(ctx context.Context, ID MonitorID) error {
+	req := deleteMonitorRequest{
+		ID: ID,
+	}
+	if err := c.call(ctx, "deleteMonitor", req, &Response{}); err != nil {
 		return err
 	}
+	c.plan.add(-1)
 	return nil
 }
 
+// DeleteMonitorsBySearch deletes every monitor whose FriendlyName or URL
+// matches the search string, and returns the IDs of the deleted monitors. To
+// avoid deleting every monitor by accident, the search string must not be
+// empty. Every page of matching monitors is fetched before any are deleted,
+// so that deleting them doesn't shift later monitors onto pages already read.
+//
+// Since this may make many API requests, consider setting the client's
+// RequestInterval to stay within the API's rate limit. If the operation fails,
+// it returns the IDs of the monitors deleted so far, together with the error.
+func (c *Client) DeleteMonitorsBySearch(s string) ([]MonitorID, error) {
+	return c.DeleteMonitorsBySearchContext(context.Background(), s)
+}
+
+// DeleteMonitorsBySearchContext is like DeleteMonitorsBySearch, but uses the
+// specified context for its API requests.
+func (c *Client) DeleteMonitorsBySearchContext(ctx context.Context, s string) ([]MonitorID, error) {
+	if s == "" {
+		return nil, errors.New("search string must not be empty")
+	}
+	monitors, err := c.SearchMonitorsContext(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	deleted := []MonitorID{}
+	for _, m := range monitors {
+		if err := c.DeleteMonitorContext(ctx, m.ID); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, m.ID)
+	}
+	return deleted, nil
+}
+
 // MakeAPICall calls the Uptime Robot API with the specified verb and data, and
 // stores the returned data in the Response struct.
 func (c *Client) MakeAPICall(verb string, r *Response, data []byte) error {
-	data, err := decorateRequestData(data, c.apiKey)
+	return c.MakeAPICallContext(context.Background(), verb, r, data)
+}
+
+// MakeAPICallContext is like MakeAPICall, but uses the specified context for
+// the HTTP request, so that the call can be cancelled or given a deadline.
+func (c *Client) MakeAPICallContext(ctx context.Context, verb string, r *Response, data []byte) (err error) {
+	if c.readOnly && isMutating(verb) {
+		return ReadOnlyError{Verb: verb}
+	}
+	if c.dryRun != nil && isMutating(verb) {
+		c.dryRun.record(verb, data)
+		r.Stat = "ok"
+		return nil
+	}
+	ctx, hooks := c.startCall(ctx, verb)
+	defer func() { hooks.end(err) }()
+	data, err = decorateRequestData(data, c.apiKey)
 	if err != nil {
 		return err
 	}
+	maxRetries := c.MaxRetries
+	if call := callOptionsFrom(ctx); call.setRetries {
+		maxRetries = call.retries
+	}
+	for attempt := 0; ; attempt++ {
+		if err := c.pacer.wait(ctx, c.RequestInterval); err != nil {
+			return err
+		}
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		start := time.Now()
+		status, err := c.do(ctx, verb, r, data)
+		d := time.Since(start)
+		c.logRequest(ctx, verb, attempt, d, status, r, err)
+		a := Attempt{Verb: verb, Number: attempt, Duration: d, Status: status, Err: err}
+		if err != nil {
+			a.ErrorType = errorType(status, r, err)
+		}
+		hooks.attempt(a)
+		var rl RateLimitError
+		if !errors.As(err, &rl) || attempt >= maxRetries {
+			return err
+		}
+		wait := rl.RetryAfter
+		if wait == 0 {
+			wait = defaultRetryAfter
+		}
+		hooks.retry(wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// do makes a single API request with the specified verb and decorated data,
+// storing the returned data in the Response struct. If the request was
+// rejected because of the rate limit, it returns a RateLimitError.
+func (c *Client) do(ctx context.Context, verb string, r *Response, data []byte) (status int, err error) {
+	hc, debug := c.HTTPClient, c.Debug
+	call := callOptionsFrom(ctx)
+	if call.timeout > 0 {
+		withTimeout := *hc
+		withTimeout.Timeout = call.timeout
+		hc = &withTimeout
+	}
+	if call.setDebug {
+		debug = call.debug
+	}
 	requestURL := c.URL + "/v2/" + verb
-	req, err := http.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewBuffer(data))
 	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %v", err)
+		return status, fmt.Errorf("failed to create HTTP request: %v", err)
 	}
 	req.Header.Add("content-type", "application/json")
-	if c.Debug != nil {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if debug != nil {
 		requestDump, err := httputil.DumpRequestOut(req, true)
 		if err != nil {
-			return fmt.Errorf("error dumping HTTP request: %v", err)
+			return status, fmt.Errorf("error dumping HTTP request: %v", err)
 		}
-		fmt.Fprintln(c.Debug, string(requestDump))
-		fmt.Fprintln(c.Debug)
+		fmt.Fprintln(debug, string(requestDump))
+		fmt.Fprintln(debug)
 	}
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := hc.Do(req)
 	if err != nil {
-		return fmt.Errorf("HTTP request failed: %v", err)
+		return status, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
-	if c.Debug != nil {
+	status = resp.StatusCode
+	if debug != nil {
 		responseDump, err := httputil.DumpResponse(resp, true)
 		if err != nil {
-			return fmt.Errorf("error dumping HTTP response: %v", err)
+			return status, fmt.Errorf("error dumping HTTP response: %v", err)
 		}
-		fmt.Fprintln(c.Debug, string(responseDump))
-		fmt.Fprintln(c.Debug)
+		fmt.Fprintln(debug, string(responseDump))
+		fmt.Fprintln(debug)
 	}
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("reading response body: %v", err)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return status, RateLimitError{
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+	// Keep the start of the body for error messages, and the whole body only
+	// if it was asked for, so that large responses are decoded as they arrive
+	head := &headBuffer{max: maxErrorBodyLength + 1}
+	var body io.Reader = io.TeeReader(resp.Body, head)
+	var raw *bytes.Buffer
+	if r.keepRaw {
+		raw = &bytes.Buffer{}
+		body = io.TeeReader(body, raw)
 	}
-	resp.Body.Close()
-	respString := string(respBytes)
-	resp.Body = ioutil.NopCloser(strings.NewReader(respString))
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected response status %d: %q", resp.StatusCode, respString)
+		io.Copy(io.Discard, body)
+		return status, newAPIError(verb, status, head.String(), nil, nil)
 	}
-	if err = json.NewDecoder(resp.Body).Decode(&r); err != nil {
-		return fmt.Errorf("decoding error for %q: %v", respString, err)
+	if err = decodeResponse(json.NewDecoder(body), r); err != nil {
+		return status, newAPIError(verb, status, head.String(), nil, err)
 	}
 	if r.Stat != "ok" {
-		e, _ := json.MarshalIndent(r.Error, "", " ")
-		return fmt.Errorf("API error: %s", e)
+		if r.Error.isRateLimit() {
+			return status, RateLimitError{
+				RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			}
+		}
+		details := r.Error
+		if details == nil {
+			details = Error{}
+		}
+		return status, newAPIError(verb, status, head.String(), details, nil)
 	}
-	return nil
+	if raw != nil {
+		r.raw = raw.Bytes()
+	}
+	r.localizeTimes()
+	return status, nil
 }
 
 // decorateRequestData takes JSON data representing an API request, and adds the
@@ -280,18 +1727,3 @@ func decorateRequestData(data []byte, apiKey string) ([]byte, error) {
 	}
 	return data, nil
 }
-
-// render takes a template and a data value, and returns the string result of
-// executing the template in the context of the value.
-func render(templateName string, value interface{}) string {
-	var output bytes.Buffer
-	tmpl, err := template.New("").Parse(templateName)
-	if err != nil {
-		log.Fatal(err)
-	}
-	err = tmpl.Execute(&output, value)
-	if err != nil {
-		log.Fatal(err)
-	}
-	return output.String()
-}
@@ -12,6 +12,10 @@ const TypePing = 3
 // TypePort represents a port monitor.
 const TypePort = 4
 
+// TypeHeartbeat represents a heartbeat monitor, which expects to be sent
+// regular requests, rather than checking a URL.
+const TypeHeartbeat = 5
+
 // SubTypeHTTP represents an HTTP monitor subtype.
 const SubTypeHTTP = 1
 
@@ -41,24 +45,108 @@ const KeywordExists = 1
 // is not found.
 const KeywordNotExists = 2
 
-// StatusPaused is the status value which sets a monitor to paused status when
-// calling EditMonitor.
-const StatusPaused = 0
+// EditStatusPause is the status value which pauses a monitor when sent in an
+// editMonitor request. PauseMonitor uses it.
+const EditStatusPause = 0
+
+// EditStatusResume is the status value which resumes (unpauses) a monitor
+// when sent in an editMonitor request. StartMonitor uses it.
+const EditStatusResume = 1
+
+// StatusResumed is the status value which resumes a monitor when calling
+// EditMonitor.
+//
+// Deprecated: use EditStatusResume, which isn't confused with StatusUnknown.
+const StatusResumed = EditStatusResume
+
+// StatusPaused is the status of a monitor which is paused.
+const StatusPaused Status = 0
+
+// StatusUnknown is the status of a monitor which has not been checked yet.
+const StatusUnknown Status = 1
+
+// StatusUp is the status of a monitor which is currently up.
+const StatusUp Status = 2
+
+// StatusMaybeDown is the status of a monitor which may be down, but this has
+// not yet been confirmed.
+const StatusMaybeDown Status = 8
+
+// StatusDown is the status of a monitor which is currently down.
+const StatusDown Status = 9
+
+// LogTypeDown is the log type indicating that the monitor went down.
+const LogTypeDown = 1
+
+// LogTypeUp is the log type indicating that the monitor came back up.
+const LogTypeUp = 2
+
+// LogTypeStarted is the log type indicating that the monitor was started.
+const LogTypeStarted = 98
+
+// LogTypePaused is the log type indicating that the monitor was paused.
+const LogTypePaused = 99
+
+// AlertTypeDown is the webhook alert type indicating that the monitor went
+// down.
+const AlertTypeDown = 1
+
+// AlertTypeUp is the webhook alert type indicating that the monitor came back
+// up.
+const AlertTypeUp = 2
+
+// AlertTypeSSLExpiry is the webhook alert type indicating that the monitor's
+// SSL certificate is about to expire.
+const AlertTypeSSLExpiry = 3
+
+// AlertContactTypeSMS represents an SMS alert contact.
+const AlertContactTypeSMS = 1
+
+// AlertContactTypeEmail represents an email alert contact.
+const AlertContactTypeEmail = 2
+
+// AlertContactTypeTwitter represents a Twitter direct message alert contact.
+const AlertContactTypeTwitter = 3
+
+// AlertContactTypeWebhook represents a webhook alert contact.
+const AlertContactTypeWebhook = 5
+
+// AlertContactTypePushbullet represents a Pushbullet alert contact.
+const AlertContactTypePushbullet = 6
+
+// AlertContactTypeZapier represents a Zapier alert contact.
+const AlertContactTypeZapier = 7
+
+// AlertContactTypePushover represents a Pushover alert contact.
+const AlertContactTypePushover = 9
+
+// AlertContactTypeSlack represents a Slack alert contact.
+const AlertContactTypeSlack = 11
+
+// MWindowTypeOnce represents a maintenance window which applies only once.
+const MWindowTypeOnce = 1
+
+// MWindowTypeDaily represents a maintenance window which recurs every day.
+const MWindowTypeDaily = 2
+
+// MWindowTypeWeekly represents a maintenance window which recurs on certain
+// days of the week.
+const MWindowTypeWeekly = 3
 
-// StatusResumed is the status value which sets a monitor to resumed (unpaused)
-// status when calling EditMonitor.
-const StatusResumed = 1
+// MWindowTypeMonthly represents a maintenance window which recurs on certain
+// days of the month.
+const MWindowTypeMonthly = 4
 
-// StatusUnknown is the status value indicating that the monitor status is
-// currently unknown.
-const StatusUnknown = 1
+// PSPSortFriendlyNameAZ sorts the monitors on a status page by name, A to Z.
+const PSPSortFriendlyNameAZ = 1
 
-// StatusUp is the status value indicating that the monitor is currently up.
-const StatusUp = 2
+// PSPSortFriendlyNameZA sorts the monitors on a status page by name, Z to A.
+const PSPSortFriendlyNameZA = 2
 
-// StatusMaybeDown is the status value indicating that the monitor may be down,
-// but this has not yet been confirmed.
-const StatusMaybeDown = 8
+// PSPSortStatusUpDown sorts the monitors on a status page by status, with up
+// monitors first.
+const PSPSortStatusUpDown = 3
 
-// StatusDown is the status value indicating that the monitor is currently down.
-const StatusDown = 9
+// PSPSortStatusDownUp sorts the monitors on a status page by status, with down
+// monitors first.
+const PSPSortStatusDownUp = 4
@@ -0,0 +1,97 @@
+package uptimerobot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change describes a field which differs between two monitors, as reported by
+// Diff. Field is the name of the Monitor field, and Old and New hold its
+// values in the existing and desired monitors respectively.
+type Change struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// String returns a one-line description of the change, such as:
+//
+//	FriendlyName: "Example" -> "Example.com website"
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, formatChangeValue(c.Old), formatChangeValue(c.New))
+}
+
+func formatChangeValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []ContactID:
+		s := make([]string, len(v))
+		for i, ID := range v {
+			s[i] = string(ID)
+		}
+		return "[" + strings.Join(s, ",") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Diff compares an existing monitor with the desired configuration, and
+// returns a Change for each field which differs, or an empty slice if they
+// match. Fields set by Uptime Robot rather than the user (ID, Status, Logs,
+// ResponseTimes, and the uptime ratios) are ignored, and alert contacts are
+// compared regardless of their order, and of their ContactSettings. All other
+// fields are compared as they are, including zero values.
+func Diff(existing, desired Monitor) []Change {
+	changes := []Change{}
+	compare := func(field string, old, new interface{}) {
+		if old != new {
+			changes = append(changes, Change{Field: field, Old: old, New: new})
+		}
+	}
+	compare("FriendlyName", existing.FriendlyName, desired.FriendlyName)
+	compare("URL", existing.URL, desired.URL)
+	compare("Type", existing.Type, desired.Type)
+	compare("SubType", existing.SubType, desired.SubType)
+	compare("KeywordType", existing.KeywordType, desired.KeywordType)
+	compare("Port", existing.Port, desired.Port)
+	compare("KeywordValue", existing.KeywordValue, desired.KeywordValue)
+	if !sameContacts(existing.AlertContacts, desired.AlertContacts) {
+		changes = append(changes, Change{
+			Field: "AlertContacts",
+			Old:   existing.AlertContacts,
+			New:   desired.AlertContacts,
+		})
+	}
+	compare("Interval", existing.Interval, desired.Interval)
+	compare("Timeout", existing.Timeout, desired.Timeout)
+	compare("IgnoreSSLErrors", existing.IgnoreSSLErrors, desired.IgnoreSSLErrors)
+	return changes
+}
+
+// sameContacts reports whether a and b contain the same alert contact IDs, in
+// any order.
+func sameContacts(a, b []ContactID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	as := append([]ContactID(nil), a...)
+	bs := append([]ContactID(nil), b...)
+	sort.Slice(as, func(i, j int) bool { return as[i] < as[j] })
+	sort.Slice(bs, func(i, j int) bool { return bs[i] < bs[j] })
+	for i := range as {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MonitorsEquivalent reports whether a and b have the same configuration,
+// ignoring the fields set by Uptime Robot rather than the user, as Diff does.
+// It's useful in tests, and in reconcilers which compare the desired monitors
+// with those which exist.
+func MonitorsEquivalent(a, b Monitor) bool {
+	return len(Diff(a, b)) == 0
+}
@@ -0,0 +1,53 @@
+package uptimerobot
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// PlannedRequest describes an API request which a client in dry-run mode
+// would have made. Body holds the request parameters as JSON, without the API
+// key.
+type PlannedRequest struct {
+	Verb string
+	Body json.RawMessage
+}
+
+// dryRun records the mutating requests made by a client in dry-run mode.
+type dryRun struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+func (d *dryRun) record(verb string, data []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.requests = append(d.requests, PlannedRequest{
+		Verb: verb,
+		Body: append(json.RawMessage(nil), data...),
+	})
+}
+
+// isMutating reports whether an API call with the specified verb changes the
+// account, as opposed to just reading from it.
+func isMutating(verb string) bool {
+	for _, prefix := range []string{"new", "edit", "delete", "reset"} {
+		if strings.HasPrefix(verb, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PlannedRequests returns the requests which the client would have made, in
+// order, if it were not in dry-run mode (see WithDryRun). If the client is not
+// in dry-run mode, it returns nil.
+func (c *Client) PlannedRequests() []PlannedRequest {
+	if c.dryRun == nil {
+		return nil
+	}
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	return append([]PlannedRequest(nil), c.dryRun.requests...)
+}
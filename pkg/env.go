@@ -0,0 +1,88 @@
+package uptimerobot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames lists the names of the config files read by NewFromEnv, in
+// order of preference. These are the formats most commonly used with the
+// uptimerobot command-line tool.
+var configFileNames = []string{
+	".uptimerobot.yaml",
+	".uptimerobot.yml",
+	".uptimerobot.json",
+}
+
+// NewFromEnv returns a Client configured from the environment, so that small
+// programs need no setup code. The API key is read from the UPTIMEROBOT_API_KEY
+// environment variable or, if that isn't set, from the apiKey setting of the
+// config file used by the uptimerobot command-line tool (.uptimerobot.yaml,
+// .uptimerobot.yml, or .uptimerobot.json in the home directory or the current
+// directory). If UPTIMEROBOT_URL is set, requests are sent to that URL, and if
+// UPTIMEROBOT_DEBUG is set, requests and responses are dumped to standard
+// output. Any ClientOptions given are applied after these settings.
+//
+// NewFromEnv returns an error if no API key can be found.
+func NewFromEnv(opts ...ClientOption) (Client, error) {
+	key := os.Getenv("UPTIMEROBOT_API_KEY")
+	if key == "" {
+		dirs := []string{}
+		if home, err := os.UserHomeDir(); err == nil {
+			dirs = append(dirs, home)
+		}
+		dirs = append(dirs, ".")
+		var err error
+		key, err = configAPIKey(dirs...)
+		if err != nil {
+			return Client{}, err
+		}
+	}
+	if key == "" {
+		return Client{}, errors.New("no API key found: set UPTIMEROBOT_API_KEY, or apiKey in .uptimerobot.yaml")
+	}
+	if URL := os.Getenv("UPTIMEROBOT_URL"); URL != "" {
+		opts = append([]ClientOption{WithBaseURL(URL)}, opts...)
+	}
+	return New(key, opts...), nil
+}
+
+// configAPIKey returns the apiKey setting from the first config file found in
+// the specified directories, or the empty string if there is none. As with the
+// command-line tool, the setting's name is not case-sensitive.
+func configAPIKey(dirs ...string) (string, error) {
+	for _, dir := range dirs {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			data, err := os.ReadFile(path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			settings := map[string]interface{}{}
+			if strings.HasSuffix(name, ".json") {
+				err = json.Unmarshal(data, &settings)
+			} else {
+				err = yaml.Unmarshal(data, &settings)
+			}
+			if err != nil {
+				return "", fmt.Errorf("reading config file %s: %v", path, err)
+			}
+			for k, v := range settings {
+				if strings.EqualFold(k, "apiKey") {
+					return fmt.Sprint(v), nil
+				}
+			}
+			return "", nil
+		}
+	}
+	return "", nil
+}
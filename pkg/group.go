@@ -0,0 +1,50 @@
+package uptimerobot
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Domain returns the registered domain of the host the monitor checks, using
+// the Public Suffix List: for example, a monitor of
+// https://www.example.co.uk/login has the domain example.co.uk. A monitor of
+// an IP address has that address as its domain. If the monitor has no URL,
+// or its host has no registered domain (such as localhost), Domain returns
+// the host as it is.
+func (m Monitor) Domain() string {
+	host := m.URL
+	if strings.Contains(host, "://") {
+		u, err := url.Parse(host)
+		if err != nil {
+			return host
+		}
+		host = u.Hostname()
+	} else if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" || net.ParseIP(host) != nil {
+		return host
+	}
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+	return domain
+}
+
+// GroupByDomain groups the specified monitors by their registered domains,
+// as given by Domain, so that, for example, monitors of www.example.com and
+// api.example.com are grouped together under example.com. Each group holds
+// its monitors in the order they were given.
+func GroupByDomain(monitors []Monitor) map[string][]Monitor {
+	groups := map[string][]Monitor{}
+	for _, m := range monitors {
+		d := m.Domain()
+		groups[d] = append(groups[d], m)
+	}
+	return groups
+}
@@ -0,0 +1,100 @@
+package uptimerobot
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Hook observes a client's API calls, so that they can be measured or traced
+// without this package depending on any particular metrics or tracing
+// library. The prommetrics package, for example, provides a Hook which
+// exports Prometheus metrics. Add hooks to a client with WithHook.
+//
+// StartCall is called at the start of each API call, before its first
+// attempt, and returns the context to use for the call (which may carry, for
+// example, a tracing span) and a CallHook to observe the rest of it.
+type Hook interface {
+	StartCall(ctx context.Context, verb string) (context.Context, CallHook)
+}
+
+// CallHook observes a single API call, which may make several attempts if
+// the rate limit is reached. Attempt is called after each attempt, Retry
+// before the client waits to try again, and End when the call returns, with
+// its error, if any.
+type CallHook interface {
+	Attempt(a Attempt)
+	Retry(wait time.Duration)
+	End(err error)
+}
+
+// Attempt describes a single API request made by a call. Number counts the
+// attempts from zero, and Status is the HTTP status of the response, or zero
+// if none was received.
+//
+// If the request failed, Err is the error, and ErrorType classifies it:
+// 'rate_limit', the error type given by the API (such as 'not_found'),
+// 'http_' followed by an unexpected HTTP status, 'invalid_response', or
+// 'transport' if no response was received.
+type Attempt struct {
+	Verb      string
+	Number    int
+	Duration  time.Duration
+	Status    int
+	ErrorType string
+	Err       error
+}
+
+// callHooks holds the CallHooks observing a single API call.
+type callHooks []CallHook
+
+// startCall calls StartCall on each of the client's hooks, in order, passing
+// each the context returned by the last.
+func (c *Client) startCall(ctx context.Context, verb string) (context.Context, callHooks) {
+	if len(c.Hooks) == 0 {
+		return ctx, nil
+	}
+	hooks := make(callHooks, len(c.Hooks))
+	for i, h := range c.Hooks {
+		ctx, hooks[i] = h.StartCall(ctx, verb)
+	}
+	return ctx, hooks
+}
+
+func (hs callHooks) attempt(a Attempt) {
+	for _, h := range hs {
+		h.Attempt(a)
+	}
+}
+
+func (hs callHooks) retry(wait time.Duration) {
+	for _, h := range hs {
+		h.Retry(wait)
+	}
+}
+
+func (hs callHooks) end(err error) {
+	for _, h := range hs {
+		h.End(err)
+	}
+}
+
+// errorType classifies a failed request, as described for Attempt.
+func errorType(status int, r *Response, err error) string {
+	var rl RateLimitError
+	if errors.As(err, &rl) {
+		return "rate_limit"
+	}
+	if t, ok := r.Error["type"].(string); ok && r.Stat != "ok" {
+		return t
+	}
+	switch status {
+	case 0:
+		return "transport"
+	case 200:
+		return "invalid_response"
+	default:
+		return "http_" + strconv.Itoa(status)
+	}
+}
@@ -0,0 +1,143 @@
+package uptimerobot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MonitorID is the ID of a monitor. Monitors, alert contacts, maintenance
+// windows, and status pages each have their own ID type, so that passing the
+// wrong kind of ID is a compile-time error.
+type MonitorID int64
+
+// ContactID is the ID of an alert contact. Unlike other IDs, it is a string,
+// since the API gives alert contact IDs as strings which may have leading
+// zeros, such as "0102759".
+type ContactID string
+
+// MWindowID is the ID of a maintenance window.
+type MWindowID int64
+
+// PSPID is the ID of a public status page.
+type PSPID int64
+
+// String returns the ID as a decimal number.
+func (id MonitorID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// String returns the ID as a decimal number.
+func (id MWindowID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// String returns the ID as a decimal number.
+func (id PSPID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// String returns the ID.
+func (id ContactID) String() string {
+	return string(id)
+}
+
+// UnmarshalJSON decodes a monitor ID in any of the encodings accepted by
+// FlexInt.
+func (id *MonitorID) UnmarshalJSON(data []byte) error {
+	v, err := parseFlexInt(data)
+	if err != nil {
+		return err
+	}
+	*id = MonitorID(v)
+	return nil
+}
+
+// UnmarshalJSON decodes a maintenance window ID in any of the encodings
+// accepted by FlexInt.
+func (id *MWindowID) UnmarshalJSON(data []byte) error {
+	v, err := parseFlexInt(data)
+	if err != nil {
+		return err
+	}
+	*id = MWindowID(v)
+	return nil
+}
+
+// UnmarshalJSON decodes a status page ID in any of the encodings accepted by
+// FlexInt.
+func (id *PSPID) UnmarshalJSON(data []byte) error {
+	v, err := parseFlexInt(data)
+	if err != nil {
+		return err
+	}
+	*id = PSPID(v)
+	return nil
+}
+
+// UnmarshalJSON decodes an alert contact ID given either as a string or as a
+// number. Null decodes as an empty ID.
+func (id *ContactID) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case nil:
+		*id = ""
+	case string:
+		*id = ContactID(v)
+	case float64:
+		*id = ContactID(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("invalid alert contact ID %s", data)
+	}
+	return nil
+}
+
+// ParseMonitorID parses a monitor ID given as a decimal number, such as
+// "780689017".
+func ParseMonitorID(s string) (MonitorID, error) {
+	id, err := parseID("monitor", s)
+	return MonitorID(id), err
+}
+
+// ParseMWindowID parses a maintenance window ID given as a decimal number.
+func ParseMWindowID(s string) (MWindowID, error) {
+	id, err := parseID("maintenance window", s)
+	return MWindowID(id), err
+}
+
+// ParsePSPID parses a status page ID given as a decimal number.
+func ParsePSPID(s string) (PSPID, error) {
+	id, err := parseID("status page", s)
+	return PSPID(id), err
+}
+
+// ParseContactID parses an alert contact ID, which must consist only of
+// digits. Leading zeros are kept, since they are part of the ID.
+func ParseContactID(s string) (ContactID, error) {
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return "", fmt.Errorf("invalid alert contact ID %q", s)
+	}
+	return ContactID(s), nil
+}
+
+// parseID parses the positive decimal ID of the specified kind of resource.
+func parseID(resource, s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s ID %q", resource, s)
+	}
+	return id, nil
+}
+
+// joinIDs is like joinInts, but for IDs such as monitor IDs.
+func joinIDs[T MonitorID | MWindowID | PSPID](IDs []T) string {
+	s := make([]string, len(IDs))
+	for i, v := range IDs {
+		s[i] = strconv.FormatInt(int64(v), 10)
+	}
+	return strings.Join(s, "-")
+}
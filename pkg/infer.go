@@ -0,0 +1,116 @@
+package uptimerobot
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// subTypeSchemes maps URL schemes to the port monitor subtypes which check
+// the corresponding services.
+var subTypeSchemes = map[string]int{
+	"ftp":  SubTypeFTP,
+	"smtp": SubTypeSMTP,
+	"pop3": SubTypePOP3,
+	"imap": SubTypeIMAP,
+}
+
+// subTypePorts maps the standard ports of the services checked by port
+// monitors to the corresponding subtypes.
+var subTypePorts = map[int]int{
+	80:  SubTypeHTTP,
+	443: SubTypeHTTPS,
+	21:  SubTypeFTP,
+	25:  SubTypeSMTP,
+	110: SubTypePOP3,
+	143: SubTypeIMAP,
+}
+
+// InferMonitorFromURL returns a Monitor suitable for checking the specified
+// URL, with the Type, SubType, Port, URL, and FriendlyName fields set:
+//
+//   - An http or https URL gives an HTTP monitor, on the port given in the
+//     URL, or otherwise on port 80 or 443.
+//   - An ftp, smtp, pop3, or imap URL, such as ftp://example.com, gives a port
+//     monitor for that service, or a custom port monitor if the URL gives a
+//     port other than the service's standard one.
+//   - A host and port, such as example.com:8443, gives a port monitor for the
+//     service usually found on that port, or a custom port monitor.
+//   - A host on its own, such as example.com or 192.0.2.1, gives a ping
+//     monitor.
+//
+// The friendly name is the host name, which you can change before creating
+// the monitor. InferMonitorFromURL returns an error if it can't tell what
+// kind of monitor is wanted.
+func InferMonitorFromURL(rawURL string) (Monitor, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return Monitor{}, errors.New("empty URL")
+	}
+	s := rawURL
+	if !strings.Contains(s, "://") {
+		s = "//" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return Monitor{}, fmt.Errorf("invalid URL %q: %v", rawURL, err)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return Monitor{}, fmt.Errorf("invalid URL %q: no host", rawURL)
+	}
+	port := 0
+	if p := u.Port(); p != "" {
+		port, err = strconv.Atoi(p)
+		if err != nil || port < 1 || port > 65535 {
+			return Monitor{}, fmt.Errorf("invalid port in URL %q", rawURL)
+		}
+	}
+	m := Monitor{
+		FriendlyName: host,
+		URL:          host,
+	}
+	scheme := strings.ToLower(u.Scheme)
+	switch scheme {
+	case "http", "https":
+		m.Type = TypeHTTP
+		m.URL = rawURL
+		m.Port = port
+		if port == 0 {
+			m.Port = 80
+			if scheme == "https" {
+				m.Port = 443
+			}
+		}
+		return m, nil
+	case "":
+		if port == 0 {
+			if u.Path != "" {
+				return Monitor{}, fmt.Errorf("can't tell what kind of monitor to use for %q (give a scheme, such as https://)", rawURL)
+			}
+			m.Type = TypePing
+			return m, nil
+		}
+		m.Type = TypePort
+		m.SubType = SubTypeCustomPort
+		m.Port = port
+		if st, ok := subTypePorts[port]; ok {
+			m.SubType = st
+			m.Port = 0
+		}
+		return m, nil
+	}
+	st, ok := subTypeSchemes[scheme]
+	if !ok {
+		return Monitor{}, fmt.Errorf("can't tell what kind of monitor to use for %q (unsupported scheme %q)", rawURL, u.Scheme)
+	}
+	m.Type = TypePort
+	m.SubType = st
+	if port != 0 && subTypePorts[port] != st {
+		m.SubType = SubTypeCustomPort
+		m.Port = port
+	}
+	return m, nil
+}
@@ -0,0 +1,115 @@
+package uptimerobot
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LintConfig controls the checks performed by Lint.
+//
+// The Critical field, if set, is a function which reports whether a given
+// monitor is a critical production check; such monitors are expected to be
+// checked more often than every CriticalInterval. If Critical is nil, this
+// check is skipped. If CriticalInterval is zero, a default of 5 minutes is
+// used.
+//
+// The MaxPausedAge field sets how long a monitor may remain paused before it
+// is reported. If it is zero, paused monitors are not reported.
+type LintConfig struct {
+	Critical         func(Monitor) bool
+	CriticalInterval time.Duration
+	MaxPausedAge     time.Duration
+}
+
+// Problem represents a risky monitor configuration reported by Lint.
+type Problem struct {
+	Monitor Monitor
+	Message string
+}
+
+// String returns a one-line description of the problem.
+func (p Problem) String() string {
+	return fmt.Sprintf("%d (%s): %s", p.Monitor.ID, p.Monitor.FriendlyName, p.Message)
+}
+
+// loginPathHints are the URL path fragments which suggest that a page
+// handles user credentials.
+var loginPathHints = []string{"login", "signin", "sign-in", "logon", "auth"}
+
+// Lint checks the supplied monitors for risky configurations, and returns a
+// slice of Problems describing any it finds. The checks are:
+//
+//   - monitors with no alert contacts
+//   - critical monitors checked less often than the configured interval
+//   - keyword monitors with no keyword
+//   - HTTP (not HTTPS) monitors for login pages
+//   - monitors which have been paused for longer than the configured age
+//
+// The alert contact and paused age checks rely on the monitors' AlertContacts
+// and Logs fields, so fetch the monitors with the WithAlertContacts and
+// WithLogs options:
+//
+//	monitors, err := client.AllMonitors(uptimerobot.WithAlertContacts(), uptimerobot.WithLogs())
+func Lint(monitors []Monitor, cfg LintConfig) []Problem {
+	criticalInterval := cfg.CriticalInterval
+	if criticalInterval == 0 {
+		criticalInterval = 5 * time.Minute
+	}
+	problems := []Problem{}
+	report := func(m Monitor, format string, args ...interface{}) {
+		problems = append(problems, Problem{
+			Monitor: m,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	for _, m := range monitors {
+		if len(m.AlertContacts) == 0 {
+			report(m, "no alert contacts")
+		}
+		if cfg.Critical != nil && cfg.Critical(m) && m.Interval >= criticalInterval {
+			report(m, "critical monitor checked only every %s", m.Interval)
+		}
+		if m.Type == TypeKeyword && m.KeywordValue == "" {
+			report(m, "keyword monitor has no keyword")
+		}
+		if isPlainHTTPLogin(m.URL) {
+			report(m, "login page monitored over plain HTTP")
+		}
+		if m.Status == StatusPaused && cfg.MaxPausedAge > 0 {
+			if paused, ok := pausedSince(m); ok && time.Since(paused) > cfg.MaxPausedAge {
+				report(m, "paused since %s", paused.Format("2006-01-02"))
+			}
+		}
+	}
+	return problems
+}
+
+// isPlainHTTPLogin reports whether the specified URL uses plain HTTP and looks
+// like a login page.
+func isPlainHTTPLogin(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "http" {
+		return false
+	}
+	path := strings.ToLower(u.Path)
+	for _, hint := range loginPathHints {
+		if strings.Contains(path, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// pausedSince returns the time of the most recent pause event in the
+// monitor's logs, and false if there is none.
+func pausedSince(m Monitor) (time.Time, bool) {
+	var latest time.Time
+	for _, l := range m.Logs {
+		if l.Type == LogTypePaused && l.Datetime.After(latest) {
+			latest = l.Datetime
+		}
+	}
+	return latest, !latest.IsZero()
+}
@@ -0,0 +1,32 @@
+package uptimerobot
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// logRequest logs the outcome of a single API request to the client's Logger,
+// if it has one, at debug level.
+func (c *Client) logRequest(ctx context.Context, verb string, attempt int, d time.Duration, status int, r *Response, err error) {
+	if c.Logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("verb", verb),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", d),
+	}
+	if status != 0 {
+		attrs = append(attrs, slog.Int("status", status))
+	}
+	if err == nil {
+		c.Logger.LogAttrs(ctx, slog.LevelDebug, "uptimerobot API request", attrs...)
+		return
+	}
+	if code, ok := r.Error["type"].(string); ok && r.Stat != "ok" {
+		attrs = append(attrs, slog.String("error_type", code))
+	}
+	attrs = append(attrs, slog.String("error", err.Error()))
+	c.Logger.LogAttrs(ctx, slog.LevelDebug, "uptimerobot API request failed", attrs...)
+}
@@ -3,53 +3,133 @@ package uptimerobot
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
-	"strings"
+	"time"
 )
 
 // Monitor represents an Uptime Robot monitor.
 type Monitor struct {
-	ID            int64    `json:"id,omitempty"`
-	FriendlyName  string   `json:"friendly_name"`
-	URL           string   `json:"url"`
-	Type          int      `json:"type"`
-	SubType       int      `json:"sub_type,omitempty"`
-	KeywordType   int      `json:"keyword_type,omitempty"`
-	Port          int      `json:"port"`
-	KeywordValue  string   `json:"keyword_value,omitempty"`
-	AlertContacts []string `json:"alert_contacts,omitempty"`
-	Status        int      `json:"status,omitempty"`
+	ID              MonitorID      `json:"id,omitempty"`
+	FriendlyName    string         `json:"friendly_name"`
+	URL             string         `json:"url"`
+	Type            int            `json:"type"`
+	SubType         int            `json:"sub_type,omitempty"`
+	KeywordType     int            `json:"keyword_type,omitempty"`
+	Port            int            `json:"port"`
+	KeywordValue    string         `json:"keyword_value,omitempty"`
+	AlertContacts   []ContactID    `json:"alert_contacts,omitempty"`
+	Status          Status         `json:"status,omitempty"`
+	Interval        time.Duration  `json:"interval,omitempty"`
+	Timeout         time.Duration  `json:"timeout,omitempty"`
+	IgnoreSSLErrors bool           `json:"ignore_ssl_errors,omitempty"`
+	Logs            []MonitorLog   `json:"logs,omitempty"`
+	ResponseTimes   []ResponseTime `json:"response_times,omitempty"`
+	// ContactSettings holds the notification settings of any alert contacts
+	// which don't notify as soon as the monitor goes down, or which repeat
+	// their notifications. Other contacts use the defaults.
+	ContactSettings map[ContactID]ContactSettings `json:"contact_settings,omitempty"`
+	// UptimeRatios holds the monitor's uptime percentage over each of the
+	// periods requested with WithUptimeRatios, in the same order.
+	UptimeRatios []float64 `json:"custom_uptime_ratio,omitempty"`
+	// UptimeRanges holds the monitor's uptime percentage over each of the
+	// time ranges requested with WithUptimeRanges, in the same order.
+	UptimeRanges []float64 `json:"custom_uptime_ranges,omitempty"`
+	// AllTimeUptimeRatio holds the monitor's uptime percentage since it was
+	// created, if requested with WithAllTimeUptimeRatio.
+	AllTimeUptimeRatio float64 `json:"all_time_uptime_ratio,omitempty"`
 }
 
-const monitorTemplate = `ID: {{ .ID }}
+// ContactSettings represents the notification settings of an alert contact
+// assigned to a monitor. Threshold is the number of minutes the monitor must
+// be down before the contact is notified, and Recurrence is the number of
+// minutes between repeated notifications while it stays down (zero means the
+// notification is not repeated).
+type ContactSettings struct {
+	Threshold  int `json:"threshold,omitempty"`
+	Recurrence int `json:"recurrence,omitempty"`
+}
+
+// MonitorLog represents an entry in a monitor's event log. The Type field
+// indicates the kind of event (for example LogTypeDown or LogTypePaused), and
+// the Duration field gives the time the monitor spent in the resulting state.
+type MonitorLog struct {
+	Type     int           `json:"type"`
+	Datetime time.Time     `json:"datetime"`
+	Duration time.Duration `json:"duration"`
+}
+
+// UnmarshalJSON converts a JSON log entry to a MonitorLog struct, handling the
+// API's encoding of the log time as a Unix timestamp, and the duration in
+// seconds.
+func (l *MonitorLog) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type     FlexInt `json:"type"`
+		Datetime FlexInt `json:"datetime"`
+		Duration FlexInt `json:"duration"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*l = MonitorLog{
+		Type:     int(raw.Type),
+		Datetime: time.Unix(int64(raw.Datetime), 0).UTC(),
+		Duration: time.Duration(raw.Duration) * time.Second,
+	}
+	return nil
+}
+
+// ResponseTime represents a single measurement of a monitor's response time:
+// the time of the check, and how long the monitored service took to respond.
+type ResponseTime struct {
+	Datetime time.Time     `json:"datetime"`
+	Value    time.Duration `json:"value"`
+}
+
+// UnmarshalJSON converts a JSON response time to a ResponseTime struct,
+// handling the API's encoding of the check time as a Unix timestamp, and the
+// value in milliseconds.
+func (r *ResponseTime) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Datetime FlexInt `json:"datetime"`
+		Value    FlexInt `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = ResponseTime{
+		Datetime: time.Unix(int64(raw.Datetime), 0).UTC(),
+		Value:    time.Duration(raw.Value) * time.Millisecond,
+	}
+	return nil
+}
+
+// MonitorTemplate is the template used by Monitor.String. You can use it as the
+// starting point for your own template, to pass to Monitor.Render.
+const MonitorTemplate = `ID: {{ .ID }}
 Name: {{ .FriendlyName }}
 URL: {{ .URL }}
 Status: {{ .FriendlyStatus -}}
 {{ if .Port }}{{ printf "\nPort: %d" .Port }}{{ end -}}
 {{ if .Type }}{{ printf "\nType: %s" .FriendlyType }}{{ end -}}
+{{ if .Interval }}{{ printf "\nInterval: %s" (duration .Interval) }}{{ end -}}
 {{ if .SubType }}{{ printf "\nSubtype: %s" .FriendlySubType }}{{ end -}}
 {{ if .KeywordType }}{{ printf "\nKeywordType: %s" .FriendlyKeywordType }}{{ end -}}
 {{ if .KeywordValue }}{{ printf "\nKeyword: %s" .KeywordValue }}{{ end }}`
 
 // String returns a pretty-printed version of the monitor.
 func (m Monitor) String() string {
-	return render(monitorTemplate, m)
+	return renderString(MonitorTemplate, m)
+}
+
+// Render returns the result of executing the specified text/template template
+// with the monitor as its data, or an error if the template is invalid or
+// fails. The template can use the functions listed in TemplateFuncs.
+func (m Monitor) Render(tmpl string) (string, error) {
+	return render(tmpl, m)
 }
 
 // FriendlyType returns a human-readable name for the monitor type.
 func (m Monitor) FriendlyType() string {
-	switch m.Type {
-	case TypeHTTP:
-		return "HTTP"
-	case TypeKeyword:
-		return "Keyword"
-	case TypePing:
-		return "Ping"
-	case TypePort:
-		return "Port"
-	default:
-		return fmt.Sprintf("%d", m.Type)
-	}
+	return MonitorType(m.Type).String()
 }
 
 // FriendlySubType returns a human-readable name for the monitor subtype,
@@ -88,19 +168,17 @@ func (m Monitor) FriendlyKeywordType() string {
 }
 
 func (m Monitor) FriendlyStatus() string {
-	switch m.Status {
-	case StatusPaused:
-		return "Paused"
-	case StatusUnknown:
-		return "Unknown"
-	case StatusUp:
-		return "Up"
-	case StatusMaybeDown:
-		return "MaybeDown"
-	case StatusDown:
-		return "Down"
-	default:
-		return fmt.Sprintf("%d", m.Status)
+	return m.Status.String()
+}
+
+// localizeTimes converts the times of the monitor's log entries and response
+// times to the specified location.
+func (m *Monitor) localizeTimes(loc *time.Location) {
+	for i := range m.Logs {
+		m.Logs[i].Datetime = m.Logs[i].Datetime.In(loc)
+	}
+	for i := range m.ResponseTimes {
+		m.ResponseTimes[i].Datetime = m.ResponseTimes[i].Datetime.In(loc)
 	}
 }
 
@@ -121,11 +199,26 @@ func (m Monitor) MarshalJSON() ([]byte, error) {
 	if err != nil {
 		return []byte{}, err
 	}
-	contacts := make([]string, len(m.AlertContacts))
-	for i, c := range m.AlertContacts {
-		contacts[i] = c + "_0_0"
+	tmp["alert_contacts"] = encodeAlertContacts(m.AlertContacts, m.ContactSettings)
+	delete(tmp, "contact_settings")
+	// The API expects durations in seconds
+	for f, d := range map[string]time.Duration{
+		"interval": m.Interval,
+		"timeout":  m.Timeout,
+	} {
+		if d != 0 {
+			tmp[f] = int(d / time.Second)
+		}
+	}
+	// The API expects booleans as 0 or 1
+	if m.IgnoreSSLErrors {
+		tmp["ignore_ssl_errors"] = 1
+	}
+	// Logs, response times, and uptime ratios are read-only, so never send
+	// them to the API
+	for _, f := range []string{"logs", "response_times", "custom_uptime_ratio", "custom_uptime_ranges", "all_time_uptime_ratio"} {
+		delete(tmp, f)
 	}
-	tmp["alert_contacts"] = strings.Join(contacts, "-")
 	// Marshal the cleaned-up data back to JSON again
 	data, err = json.Marshal(tmp)
 	if err != nil {
@@ -136,51 +229,82 @@ func (m Monitor) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON converts a JSON monitor representation to a Monitor struct,
 // handling the Uptime Robot API's invalid encoding of integer zeros as empty
-// strings.
+// strings, and its encoding of durations in seconds.
 func (m *Monitor) UnmarshalJSON(data []byte) error {
-	// We need a custom unmarshaler because keyword_type, sub_type, and port
-	// are returned as either a quoted integer (if set) or an empty string
-	// (if unset), which Go's JSON library won't parse for integer fields:
-	// https://github.com/golang/go/issues/22182
+	// Integer fields such as keyword_type, sub_type, and port are returned
+	// as either a quoted integer (if set) or an empty string (if unset),
+	// which Go's JSON library won't parse for integer fields, so we decode
+	// them as FlexInts (the ID and status types decode themselves the same
+	// way): https://github.com/golang/go/issues/22182
 	//
-	// Create a temporary map and unmarshal the data into it
-	raw := map[string]interface{}{}
-	err := json.Unmarshal(data, &raw)
-	if err != nil {
+	// Use a temporary type definition to avoid infinite recursion when
+	// unmarshaling, overriding the fields which need special handling
+	type MonitorAlias Monitor
+	aux := struct {
+		MonitorAlias
+		Type               FlexInt     `json:"type"`
+		SubType            FlexInt     `json:"sub_type"`
+		KeywordType        FlexInt     `json:"keyword_type"`
+		Port               FlexInt     `json:"port"`
+		Interval           FlexInt     `json:"interval"`
+		Timeout            FlexInt     `json:"timeout"`
+		IgnoreSSLErrors    interface{} `json:"ignore_ssl_errors"`
+		UptimeRatios       ratioList   `json:"custom_uptime_ratio"`
+		UptimeRanges       ratioList   `json:"custom_uptime_ranges"`
+		AllTimeUptimeRatio ratioList   `json:"all_time_uptime_ratio"`
+		AlertContacts      []struct {
+			ID         ContactID `json:"id"`
+			Threshold  FlexInt   `json:"threshold"`
+			Recurrence FlexInt   `json:"recurrence"`
+		} `json:"alert_contacts"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
-	// Check and clean up any problematic fields
-	fields := []string{
-		"sub_type",
-		"keyword_type",
-		"port",
+	*m = Monitor(aux.MonitorAlias)
+	m.Type = int(aux.Type)
+	m.SubType = int(aux.SubType)
+	m.KeywordType = int(aux.KeywordType)
+	m.Port = int(aux.Port)
+	// Convert durations from seconds to the nanoseconds used by
+	// time.Duration
+	m.Interval = time.Duration(aux.Interval) * time.Second
+	m.Timeout = time.Duration(aux.Timeout) * time.Second
+	// Uptime ratios are given as dash-separated strings of percentages
+	m.UptimeRatios = aux.UptimeRatios
+	m.UptimeRanges = aux.UptimeRanges
+	if len(aux.AllTimeUptimeRatio) > 0 {
+		m.AllTimeUptimeRatio = aux.AllTimeUptimeRatio[0]
 	}
-	for _, f := range fields {
-		// If the field is empty string, that means zero.
-		if raw[f] == "" {
-			raw[f] = 0
-		}
-		// Otherwise, try to convert it to int.
-		if s, ok := raw[f].(string); ok {
-			v, err := strconv.Atoi(s)
-			if err != nil {
-				return err
+	// Booleans are given as 0 or 1
+	switch v := aux.IgnoreSSLErrors.(type) {
+	case bool:
+		m.IgnoreSSLErrors = v
+	case float64:
+		m.IgnoreSSLErrors = v == 1
+	case string:
+		m.IgnoreSSLErrors = v == "1"
+	}
+	// When alert contacts are requested, the API returns them as a list of
+	// objects, but we only need the IDs, and any non-default notification
+	// settings, so that they can be sent back unchanged when editing.
+	if aux.AlertContacts != nil {
+		m.AlertContacts = make([]ContactID, 0, len(aux.AlertContacts))
+		for _, c := range aux.AlertContacts {
+			if c.ID == "" {
+				continue
+			}
+			m.AlertContacts = append(m.AlertContacts, c.ID)
+			if c.Threshold != 0 || c.Recurrence != 0 {
+				if m.ContactSettings == nil {
+					m.ContactSettings = map[ContactID]ContactSettings{}
+				}
+				m.ContactSettings[c.ID] = ContactSettings{
+					Threshold:  int(c.Threshold),
+					Recurrence: int(c.Recurrence),
+				}
 			}
-			raw[f] = v
 		}
 	}
-	// Marshal the cleaned-up data back to JSON
-	data, err = json.Marshal(raw)
-	if err != nil {
-		return err
-	}
-	// Use a temporary type definition to avoid infinite recursion when unmarshaling
-	type MonitorAlias Monitor
-	var ma MonitorAlias
-	if err := json.Unmarshal(data, &ma); err != nil {
-		return err
-	}
-	// Finally, convert the temporary type back to a Monitor
-	*m = Monitor(ma)
 	return nil
 }
@@ -0,0 +1,340 @@
+package uptimerobot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// MWindow represents a maintenance window, during which monitors are not
+// checked and no alerts are sent.
+//
+// The Type field gives how often the window recurs (MWindowTypeOnce,
+// MWindowTypeDaily, MWindowTypeWeekly, or MWindowTypeMonthly). For weekly
+// windows, Value lists the days of the week on which the window applies, as
+// dash-separated numbers from 1 (Monday) to 7 (Sunday), such as "2-4". For
+// monthly windows, Value lists the days of the month, such as "1-15". Value is
+// not used for other types.
+//
+// For a one-off window, StartTime gives the date and time at which the window
+// begins. For recurring windows, only the time of day of StartTime is used.
+type MWindow struct {
+	ID           MWindowID
+	FriendlyName string
+	Type         int
+	Value        string
+	StartTime    time.Time
+	Duration     time.Duration
+	Status       int
+}
+
+// MWindowTemplate is the template used by MWindow.String. You can use it as the
+// starting point for your own template, to pass to MWindow.Render.
+const MWindowTemplate = `ID: {{ .ID }}
+Name: {{ .FriendlyName }}
+Type: {{ .FriendlyType -}}
+{{ if .Value }}{{ printf "\nValue: %s" .Value }}{{ end }}
+Start: {{ .FriendlyStartTime }}
+Duration: {{ duration .Duration }}`
+
+// String returns a pretty-printed version of the maintenance window.
+func (w MWindow) String() string {
+	return renderString(MWindowTemplate, w)
+}
+
+// Render returns the result of executing the specified text/template template
+// with the maintenance window as its data, or an error if the template is
+// invalid or fails. The template can use the functions listed in TemplateFuncs.
+func (w MWindow) Render(tmpl string) (string, error) {
+	return render(tmpl, w)
+}
+
+// FriendlyType returns a human-readable name for the maintenance window type.
+func (w MWindow) FriendlyType() string {
+	switch w.Type {
+	case MWindowTypeOnce:
+		return "Once"
+	case MWindowTypeDaily:
+		return "Daily"
+	case MWindowTypeWeekly:
+		return "Weekly"
+	case MWindowTypeMonthly:
+		return "Monthly"
+	default:
+		return fmt.Sprintf("%d", w.Type)
+	}
+}
+
+// FriendlyStartTime returns the start time of the maintenance window: the
+// date and time for a one-off window, or just the time of day for a recurring
+// window.
+func (w MWindow) FriendlyStartTime() string {
+	if w.Type == MWindowTypeOnce {
+		return w.StartTime.Format("2006-01-02 15:04")
+	}
+	return w.StartTime.Format("15:04")
+}
+
+// newMWindowRequest returns the request parameters representing the
+// maintenance window. The API expects the start time of a one-off window as a
+// Unix timestamp, the start time of a recurring window as "HH:mm", and the
+// duration in minutes.
+func newMWindowRequest(w MWindow) mwindowRequest {
+	req := mwindowRequest{
+		FriendlyName: w.FriendlyName,
+		Type:         w.Type,
+		Value:        w.Value,
+		StartTime:    w.StartTime.Format("15:04"),
+		Duration:     int(w.Duration / time.Minute),
+	}
+	if w.Type == MWindowTypeOnce {
+		req.StartTime = strconv.FormatInt(w.StartTime.Unix(), 10)
+	}
+	return req
+}
+
+// UnmarshalJSON converts a JSON maintenance window representation to an
+// MWindow struct, handling the API's encoding of the start time as either a
+// Unix timestamp or "HH:mm", and of the duration in minutes.
+func (w *MWindow) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID           MWindowID   `json:"id"`
+		FriendlyName string      `json:"friendly_name"`
+		Type         FlexInt     `json:"type"`
+		Value        string      `json:"value"`
+		StartTime    interface{} `json:"start_time"`
+		Duration     FlexInt     `json:"duration"`
+		Status       FlexInt     `json:"status"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*w = MWindow{
+		ID:           raw.ID,
+		FriendlyName: raw.FriendlyName,
+		Type:         int(raw.Type),
+		Value:        raw.Value,
+		Duration:     time.Duration(raw.Duration) * time.Minute,
+		Status:       int(raw.Status),
+	}
+	switch v := raw.StartTime.(type) {
+	case float64:
+		w.StartTime = time.Unix(int64(v), 0).UTC()
+	case string:
+		if v == "" {
+			break
+		}
+		if ts, err := strconv.ParseInt(v, 10, 64); err == nil {
+			w.StartTime = time.Unix(ts, 0).UTC()
+			break
+		}
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			return fmt.Errorf("invalid maintenance window start time %q: %v", v, err)
+		}
+		w.StartTime = t
+	}
+	return nil
+}
+
+// AllMWindows returns all the maintenance windows associated with the
+// account.
+func (c *Client) AllMWindows() ([]MWindow, error) {
+	return c.AllMWindowsContext(context.Background())
+}
+
+// AllMWindowsContext is like AllMWindows, but uses the specified context for
+// its API requests.
+func (c *Client) AllMWindowsContext(ctx context.Context) ([]MWindow, error) {
+	windows := []MWindow{}
+	offset := 0
+	total := 0
+	for offset <= total {
+		req := getMWindowsRequest{
+			Offset: strconv.Itoa(offset),
+			Limit:  strconv.Itoa(c.pageSize()),
+		}
+		r := Response{}
+		if err := c.call(ctx, "getMWindows", req, &r); err != nil {
+			return nil, err
+		}
+		windows = append(windows, r.MWindows...)
+		total = r.Pagination.Total
+		offset = r.Pagination.Offset + c.pageSize()
+	}
+	return windows, nil
+}
+
+// GetMWindow takes the ID of an existing maintenance window, and returns the
+// corresponding MWindow. If there is no such window, it returns a
+// NotFoundError.
+func (c *Client) GetMWindow(ID MWindowID) (MWindow, error) {
+	return c.GetMWindowContext(context.Background(), ID)
+}
+
+// GetMWindowContext is like GetMWindow, but uses the specified context for its
+// API requests.
+func (c *Client) GetMWindowContext(ctx context.Context, ID MWindowID) (MWindow, error) {
+	req := getMWindowsRequest{
+		MWindows: ID.String(),
+	}
+	r := Response{}
+	if err := c.call(ctx, "getMWindows", req, &r); err != nil {
+		return MWindow{}, err
+	}
+	if len(r.MWindows) == 0 {
+		return MWindow{}, NotFoundError{
+			Resource: "maintenance window",
+			ID:       ID.String(),
+		}
+	}
+	return r.MWindows[0], nil
+}
+
+// CreateMWindow takes an MWindow and creates a new maintenance window with the
+// specified details. It returns the ID of the newly created window, or an
+// error if the operation failed.
+func (c *Client) CreateMWindow(w MWindow) (MWindowID, error) {
+	return c.CreateMWindowContext(context.Background(), w)
+}
+
+// CreateMWindowContext is like CreateMWindow, but uses the specified context
+// for its API requests.
+func (c *Client) CreateMWindowContext(ctx context.Context, w MWindow) (MWindowID, error) {
+	r := Response{}
+	if err := c.call(ctx, "newMWindow", newMWindowRequest(w), &r); err != nil {
+		return 0, err
+	}
+	return r.MWindow.ID, nil
+}
+
+// EditMWindow takes an MWindow with the ID field set, and updates the
+// corresponding maintenance window with the specified name, value, start time,
+// and duration. This is useful for shifting or extending a scheduled window,
+// for example if a deployment is delayed. The API does not allow the type of a
+// window to be changed, but the Type field must still be set, since it
+// determines how the start time is sent. It returns an MWindow with the ID
+// field set to the ID of the window, or an error if the operation failed.
+func (c *Client) EditMWindow(w MWindow) (MWindow, error) {
+	return c.EditMWindowContext(context.Background(), w)
+}
+
+// EditMWindowContext is like EditMWindow, but uses the specified context for
+// its API requests.
+func (c *Client) EditMWindowContext(ctx context.Context, w MWindow) (MWindow, error) {
+	n := newMWindowRequest(w)
+	req := editMWindowRequest{
+		ID:           w.ID,
+		FriendlyName: n.FriendlyName,
+		Value:        n.Value,
+		StartTime:    n.StartTime,
+		Duration:     n.Duration,
+	}
+	r := Response{}
+	if err := c.call(ctx, "editMWindow", req, &r); err != nil {
+		return MWindow{}, err
+	}
+	return r.MWindow, nil
+}
+
+// DeleteMWindow takes a maintenance window ID and deletes the corresponding
+// window. If there is no such window, it returns a NotFoundError; otherwise,
+// it returns an error if the operation failed.
+func (c *Client) DeleteMWindow(ID MWindowID) error {
+	return c.DeleteMWindowContext(context.Background(), ID)
+}
+
+// DeleteMWindowContext is like DeleteMWindow, but uses the specified context
+// for its API requests.
+func (c *Client) DeleteMWindowContext(ctx context.Context, ID MWindowID) error {
+	req := deleteMWindowRequest{
+		ID: ID,
+	}
+	r := Response{}
+	if err := c.call(ctx, "deleteMWindow", req, &r); err != nil {
+		if r.Error["type"] == "not_found" {
+			return NotFoundError{
+				Resource: "maintenance window",
+				ID:       ID.String(),
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// WeeklyWindow returns an MWindow which recurs on each of the specified days
+// of the week, starting at the time of day given by start, and lasting for
+// duration d. Set the FriendlyName field before passing it to CreateMWindow.
+// Use the Weekdays method to decode the days of an existing weekly window.
+func WeeklyWindow(days []time.Weekday, start time.Time, d time.Duration) MWindow {
+	// The API numbers the days from 1 (Monday) to 7 (Sunday)
+	values := make([]int, len(days))
+	for i, day := range days {
+		values[i] = int(day)
+		if day == time.Sunday {
+			values[i] = 7
+		}
+	}
+	return MWindow{
+		Type:      MWindowTypeWeekly,
+		Value:     joinInts(values),
+		StartTime: start,
+		Duration:  d,
+	}
+}
+
+// MonthlyWindow returns an MWindow which recurs on each of the specified days
+// of the month (from 1 to 31), starting at the time of day given by start, and
+// lasting for duration d. Set the FriendlyName field before passing it to
+// CreateMWindow. Use the DaysOfMonth method to decode the days of an existing
+// monthly window.
+func MonthlyWindow(days []int, start time.Time, d time.Duration) MWindow {
+	return MWindow{
+		Type:      MWindowTypeMonthly,
+		Value:     joinInts(days),
+		StartTime: start,
+		Duration:  d,
+	}
+}
+
+// Weekdays returns the days of the week on which a weekly maintenance window
+// applies, or an error if the window is not weekly or its Value is invalid.
+func (w MWindow) Weekdays() ([]time.Weekday, error) {
+	if w.Type != MWindowTypeWeekly {
+		return nil, fmt.Errorf("maintenance window %d is not weekly", w.ID)
+	}
+	values, err := splitInts(w.Value)
+	if err != nil {
+		return nil, err
+	}
+	days := make([]time.Weekday, len(values))
+	for i, v := range values {
+		if v < 1 || v > 7 {
+			return nil, fmt.Errorf("invalid day of the week %d in %q", v, w.Value)
+		}
+		days[i] = time.Weekday(v % 7)
+	}
+	return days, nil
+}
+
+// DaysOfMonth returns the days of the month on which a monthly maintenance
+// window applies, or an error if the window is not monthly or its Value is
+// invalid.
+func (w MWindow) DaysOfMonth() ([]int, error) {
+	if w.Type != MWindowTypeMonthly {
+		return nil, fmt.Errorf("maintenance window %d is not monthly", w.ID)
+	}
+	days, err := splitInts(w.Value)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range days {
+		if d < 1 || d > 31 {
+			return nil, fmt.Errorf("invalid day of the month %d in %q", d, w.Value)
+		}
+	}
+	return days, nil
+}
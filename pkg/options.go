@@ -0,0 +1,328 @@
+package uptimerobot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Option represents an optional parameter which can be passed to API calls
+// that list monitors, such as AllMonitors and SearchMonitors. For example:
+//
+//	monitors, err := client.AllMonitors(uptimerobot.WithStatuses(uptimerobot.StatusDown))
+type Option func(*options)
+
+// options holds the values set by a list of Options.
+type options struct {
+	statuses      []int
+	types         []int
+	logs          bool
+	logsSince     time.Time
+	responseTimes bool
+	rtSince       time.Time
+	uptimeRatios  []int
+	uptimeRanges  []TimeRange
+	allTimeRatio  bool
+	alertContacts bool
+	sort          string
+	timezone      bool
+	search        string
+	call          callOptions
+}
+
+// callOptions holds the settings of the client which are overridden for a
+// single call, by Options such as WithCallTimeout.
+type callOptions struct {
+	timeout    time.Duration
+	retries    int
+	setRetries bool
+	debug      io.Writer
+	setDebug   bool
+}
+
+// WithStatuses restricts the monitors returned to those whose status matches
+// one of the specified values (for example StatusDown or StatusMaybeDown).
+func WithStatuses(statuses ...Status) Option {
+	return func(o *options) {
+		for _, s := range statuses {
+			o.statuses = append(o.statuses, int(s))
+		}
+	}
+}
+
+// WithTypes restricts the monitors returned to those whose type matches one of
+// the specified values (for example TypeKeyword or TypePort).
+func WithTypes(types ...int) Option {
+	return func(o *options) {
+		o.types = append(o.types, types...)
+	}
+}
+
+// WithLogs requests the event log for each monitor, which will be available
+// in the monitor's Logs field.
+func WithLogs() Option {
+	return func(o *options) {
+		o.logs = true
+	}
+}
+
+// WithLogsSince requests the entries in each monitor's event log since the
+// specified time, which will be available in the monitor's Logs field.
+func WithLogsSince(since time.Time) Option {
+	return func(o *options) {
+		o.logs = true
+		o.logsSince = since
+	}
+}
+
+// WithResponseTimes requests the recent response times of each monitor, which
+// will be available in the monitor's ResponseTimes field.
+func WithResponseTimes() Option {
+	return func(o *options) {
+		o.responseTimes = true
+	}
+}
+
+// WithResponseTimesSince requests each monitor's response times since the
+// specified time, which will be available in the monitor's ResponseTimes
+// field.
+func WithResponseTimesSince(since time.Time) Option {
+	return func(o *options) {
+		o.responseTimes = true
+		o.rtSince = since
+	}
+}
+
+// WithUptimeRatios requests each monitor's uptime percentage over each of the
+// specified periods, in days. The results will be available in the monitor's
+// UptimeRatios field, in the same order. For example,
+// WithUptimeRatios(7, 30, 365) requests the uptime over the last week, month,
+// and year.
+func WithUptimeRatios(days ...int) Option {
+	return func(o *options) {
+		o.uptimeRatios = append(o.uptimeRatios, days...)
+	}
+}
+
+// TimeRange represents the period between two times.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// WithUptimeRanges requests each monitor's uptime percentage over each of the
+// specified time ranges. The results will be available in the monitor's
+// UptimeRanges field, in the same order.
+func WithUptimeRanges(ranges ...TimeRange) Option {
+	return func(o *options) {
+		o.uptimeRanges = append(o.uptimeRanges, ranges...)
+	}
+}
+
+// WithAllTimeUptimeRatio requests each monitor's uptime percentage since it
+// was created, which will be available in the monitor's AllTimeUptimeRatio
+// field.
+func WithAllTimeUptimeRatio() Option {
+	return func(o *options) {
+		o.allTimeRatio = true
+	}
+}
+
+// WithAlertContacts requests the IDs of the alert contacts assigned to each
+// monitor, which will be available in the monitor's AlertContacts field.
+func WithAlertContacts() Option {
+	return func(o *options) {
+		o.alertContacts = true
+	}
+}
+
+// WithSort sets the order in which monitors are returned, using the values
+// accepted by the API's sort parameter (for example "friendly_name" or
+// "status").
+func WithSort(sort string) Option {
+	return func(o *options) {
+		o.sort = sort
+	}
+}
+
+// WithTimezone requests the account's timezone along with the results, and
+// converts the times of any returned log entries to that timezone.
+func WithTimezone() Option {
+	return func(o *options) {
+		o.timezone = true
+	}
+}
+
+// WithCallTimeout sets the timeout for each HTTP request made by this call,
+// overriding the client's timeout: for example, a longer one for a bulk
+// export of monitors with their logs.
+func WithCallTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.call.timeout = d
+	}
+}
+
+// WithCallRetries sets how many times a request made by this call will be
+// retried if it is rejected because of the API's rate limit, overriding the
+// client's MaxRetries. For example, WithCallRetries(0) makes a quick status
+// check fail at once rather than wait.
+func WithCallRetries(n int) Option {
+	return func(o *options) {
+		o.call.retries = n
+		o.call.setRetries = true
+	}
+}
+
+// WithCallDebug sets the writer to which the HTTP requests and responses of
+// this call are dumped, overriding the client's Debug writer. A nil writer
+// turns off debug output for the call.
+func WithCallDebug(w io.Writer) Option {
+	return func(o *options) {
+		o.call.debug = w
+		o.call.setDebug = true
+	}
+}
+
+// callOptionsKey is the context key under which the overrides set by call
+// options are passed to MakeAPICallContext.
+type callOptionsKey struct{}
+
+// callContext returns a context carrying the overrides set by the call
+// options, if any.
+func (o options) callContext(ctx context.Context) context.Context {
+	if o.call.timeout == 0 && !o.call.setRetries && !o.call.setDebug {
+		return ctx
+	}
+	return context.WithValue(ctx, callOptionsKey{}, o.call)
+}
+
+// callOptionsFrom returns the call overrides carried by ctx, if any.
+func callOptionsFrom(ctx context.Context) callOptions {
+	call, _ := ctx.Value(callOptionsKey{}).(callOptions)
+	return call
+}
+
+// withSearch restricts the monitors returned to those whose friendly name or
+// URL contains s. It's used by SearchMonitors, so that searches are paged
+// like any other listing.
+func withSearch(s string) Option {
+	return func(o *options) {
+		o.search = s
+	}
+}
+
+// newOptions applies the supplied Options in order and returns the result.
+func newOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// FilterMonitors returns those of the specified monitors which satisfy the
+// filtering options, WithStatuses and WithTypes, in the same way as the API.
+// Other options are ignored. This is useful for implementing fakes of the API,
+// or for filtering monitors which have already been fetched.
+func FilterMonitors(monitors []Monitor, opts ...Option) []Monitor {
+	o := newOptions(opts)
+	matches := []Monitor{}
+	for _, m := range monitors {
+		if len(o.statuses) > 0 && !containsInt(o.statuses, int(m.Status)) {
+			continue
+		}
+		if len(o.types) > 0 && !containsInt(o.types, m.Type) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// containsInt reports whether v is one of the values in ints.
+func containsInt(ints []int, v int) bool {
+	for _, i := range ints {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
+
+// apply sets the request parameters corresponding to the options.
+func (o options) apply(req *getMonitorsRequest) {
+	if len(o.statuses) > 0 {
+		req.Statuses = joinInts(o.statuses)
+	}
+	if len(o.types) > 0 {
+		req.Types = joinInts(o.types)
+	}
+	if o.logs {
+		req.Logs = "1"
+	}
+	if !o.logsSince.IsZero() {
+		req.LogsStartDate = strconv.FormatInt(o.logsSince.Unix(), 10)
+		req.LogsEndDate = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	if o.responseTimes {
+		req.ResponseTimes = "1"
+	}
+	if !o.rtSince.IsZero() {
+		req.RTStartDate = strconv.FormatInt(o.rtSince.Unix(), 10)
+		req.RTEndDate = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	if len(o.uptimeRatios) > 0 {
+		req.UptimeRatios = joinInts(o.uptimeRatios)
+	}
+	if len(o.uptimeRanges) > 0 {
+		ranges := make([]string, len(o.uptimeRanges))
+		for i, r := range o.uptimeRanges {
+			ranges[i] = fmt.Sprintf("%d_%d", r.Start.Unix(), r.End.Unix())
+		}
+		req.UptimeRanges = strings.Join(ranges, "-")
+	}
+	if o.allTimeRatio {
+		req.AllTimeRatio = "1"
+	}
+	if o.alertContacts {
+		req.AlertContacts = "1"
+	}
+	req.Sort = o.sort
+	if o.timezone {
+		req.Timezone = "1"
+	}
+	if o.search != "" {
+		req.Search = o.search
+	}
+}
+
+// joinInts returns a string containing the specified integers separated by
+// dashes, which is how the API expects lists of values to be encoded.
+func joinInts(ints []int) string {
+	s := make([]string, len(ints))
+	for i, v := range ints {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, "-")
+}
+
+// splitInts is the inverse of joinInts: it takes a dash-separated string of
+// integers, such as "2-4-5", and returns the corresponding values.
+func splitInts(s string) ([]int, error) {
+	if s == "" {
+		return []int{}, nil
+	}
+	parts := strings.Split(s, "-")
+	ints := make([]int, len(parts))
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid list of integers %q", s)
+		}
+		ints[i] = v
+	}
+	return ints, nil
+}
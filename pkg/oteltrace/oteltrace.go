@@ -0,0 +1,78 @@
+// Package oteltrace creates OpenTelemetry spans for an uptimerobot client's
+// API calls.
+package oteltrace
+
+import (
+	"context"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName identifies the spans created by this package.
+const tracerName = "github.com/bitfield/uptimerobot/pkg"
+
+// Hook is an uptimerobot.Hook which creates a span for each API call, named
+// after the API verb (for example, 'uptimerobot.getMonitors'), recording the
+// HTTP status, the number of attempts, and any retries because of the rate
+// limit. Add it to a client with WithHook:
+//
+//	client := uptimerobot.New(apiKey, uptimerobot.WithHook(oteltrace.New(otel.GetTracerProvider())))
+type Hook struct {
+	tracer trace.Tracer
+}
+
+// New returns a Hook which creates spans using the specified TracerProvider.
+// If tp is nil, the spans do nothing.
+func New(tp trace.TracerProvider) Hook {
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	return Hook{
+		tracer: tp.Tracer(tracerName, trace.WithInstrumentationVersion(uptimerobot.Version)),
+	}
+}
+
+// StartCall implements uptimerobot.Hook, starting a span for the call.
+func (h Hook) StartCall(ctx context.Context, verb string) (context.Context, uptimerobot.CallHook) {
+	ctx, span := h.tracer.Start(ctx, "uptimerobot."+verb,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("uptimerobot.verb", verb)),
+	)
+	return ctx, callHook{span}
+}
+
+// callHook records the progress of a single API call in its span.
+type callHook struct {
+	span trace.Span
+}
+
+// Attempt records the number of attempts made so far, and the HTTP status of
+// the latest one, if it received a response.
+func (h callHook) Attempt(a uptimerobot.Attempt) {
+	h.span.SetAttributes(attribute.Int("uptimerobot.attempts", a.Number+1))
+	if a.Status != 0 {
+		h.span.SetAttributes(attribute.Int("http.response.status_code", a.Status))
+	}
+}
+
+// Retry records that a rate-limited request will be retried after the
+// specified wait.
+func (h callHook) Retry(wait time.Duration) {
+	h.span.AddEvent("rate limited; retrying", trace.WithAttributes(
+		attribute.String("uptimerobot.retry_after", wait.String()),
+	))
+}
+
+// End ends the span, marking it as failed if err is not nil.
+func (h callHook) End(err error) {
+	if err != nil {
+		h.span.RecordError(err)
+		h.span.SetStatus(codes.Error, err.Error())
+	}
+	h.span.End()
+}
@@ -0,0 +1,66 @@
+package oteltrace
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHookRecordsSpans(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"stat": "fail", "error": {"type": "not_found", "message": "monitor not found"}}`)
+	}))
+	defer ts.Close()
+	sr := tracetest.NewSpanRecorder()
+	client := uptimerobot.New("dummy",
+		uptimerobot.WithHTTPClient(ts.Client()),
+		uptimerobot.WithBaseURL(ts.URL),
+		uptimerobot.WithHook(New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))),
+	)
+	if err := client.DeleteMonitor(777810874); err == nil {
+		t.Fatal("want error from failed API call, got nil")
+	}
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("want 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "uptimerobot.deleteMonitor" {
+		t.Errorf("want span name %q, got %q", "uptimerobot.deleteMonitor", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("want error status, got %v", span.Status().Code)
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, a := range span.Attributes() {
+		attrs[a.Key] = a.Value
+	}
+	if got := attrs["uptimerobot.verb"].AsString(); got != "deleteMonitor" {
+		t.Errorf("want verb %q, got %q", "deleteMonitor", got)
+	}
+	if got := attrs["uptimerobot.attempts"].AsInt64(); got != 2 {
+		t.Errorf("want 2 attempts, got %d", got)
+	}
+	if got := attrs["http.response.status_code"].AsInt64(); got != http.StatusOK {
+		t.Errorf("want status %d, got %d", http.StatusOK, got)
+	}
+	events := span.Events()
+	if len(events) < 1 || events[0].Name != "rate limited; retrying" {
+		t.Errorf("want retry event, got %v", events)
+	}
+}
@@ -0,0 +1,227 @@
+package uptimerobot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CreateMonitorParams represents the details of a monitor to be created with
+// CreateMonitorWithParams. The FriendlyName, URL, and Type fields are
+// required. The remaining fields are optional, and are only sent to the API if
+// they are set, so that the API's defaults apply to anything left unset. Use
+// the helper functions String, Int, Bool, and Duration to set them:
+//
+//	p := uptimerobot.CreateMonitorParams{
+//		FriendlyName: "Example",
+//		URL:          "https://example.com",
+//		Type:         uptimerobot.TypeHTTP,
+//		Interval:     uptimerobot.Duration(5 * time.Minute),
+//	}
+type CreateMonitorParams struct {
+	FriendlyName    string
+	URL             string
+	Type            int
+	SubType         *int
+	Port            *int
+	KeywordType     *int
+	KeywordValue    *string
+	Interval        *time.Duration
+	Timeout         *time.Duration
+	IgnoreSSLErrors *bool
+	AlertContacts   []ContactID
+	ContactSettings map[ContactID]ContactSettings
+}
+
+// MarshalJSON converts the parameters to the JSON representation expected by
+// the API, omitting any unset fields.
+func (p CreateMonitorParams) MarshalJSON() ([]byte, error) {
+	params := map[string]interface{}{
+		"friendly_name": p.FriendlyName,
+		"url":           p.URL,
+		"type":          p.Type,
+	}
+	setOptionalParams(params, optionalParams{
+		SubType:         p.SubType,
+		Port:            p.Port,
+		KeywordType:     p.KeywordType,
+		KeywordValue:    p.KeywordValue,
+		Interval:        p.Interval,
+		Timeout:         p.Timeout,
+		IgnoreSSLErrors: p.IgnoreSSLErrors,
+	})
+	if p.AlertContacts != nil {
+		params["alert_contacts"] = encodeAlertContacts(p.AlertContacts, p.ContactSettings)
+	}
+	return json.Marshal(params)
+}
+
+// EditMonitorParams represents the changes to be made to an existing monitor
+// with EditMonitor. The ID field is required. The remaining fields are
+// optional, and only those which are set will be changed, so that you can
+// safely update one setting without affecting the others. To remove all alert
+// contacts from a monitor, set AlertContacts to a pointer to an empty slice.
+// ContactSettings gives the notification settings for the contacts in
+// AlertContacts, and is ignored unless AlertContacts is set.
+type EditMonitorParams struct {
+	ID              MonitorID
+	FriendlyName    *string
+	URL             *string
+	SubType         *int
+	Port            *int
+	KeywordType     *int
+	KeywordValue    *string
+	Interval        *time.Duration
+	Timeout         *time.Duration
+	IgnoreSSLErrors *bool
+	AlertContacts   *[]ContactID
+	ContactSettings map[ContactID]ContactSettings
+}
+
+// MarshalJSON converts the parameters to the JSON representation expected by
+// the API, omitting any unset fields.
+func (p EditMonitorParams) MarshalJSON() ([]byte, error) {
+	params := map[string]interface{}{
+		"id": p.ID.String(),
+	}
+	if p.FriendlyName != nil {
+		params["friendly_name"] = *p.FriendlyName
+	}
+	if p.URL != nil {
+		params["url"] = *p.URL
+	}
+	setOptionalParams(params, optionalParams{
+		SubType:         p.SubType,
+		Port:            p.Port,
+		KeywordType:     p.KeywordType,
+		KeywordValue:    p.KeywordValue,
+		Interval:        p.Interval,
+		Timeout:         p.Timeout,
+		IgnoreSSLErrors: p.IgnoreSSLErrors,
+	})
+	if p.AlertContacts != nil {
+		params["alert_contacts"] = encodeAlertContacts(*p.AlertContacts, p.ContactSettings)
+	}
+	return json.Marshal(params)
+}
+
+// optionalParams holds the optional monitor settings shared by
+// CreateMonitorParams and EditMonitorParams.
+type optionalParams struct {
+	SubType         *int
+	Port            *int
+	KeywordType     *int
+	KeywordValue    *string
+	Interval        *time.Duration
+	Timeout         *time.Duration
+	IgnoreSSLErrors *bool
+}
+
+// setOptionalParams adds any settings which are set in o to the request
+// parameters, in the encoding expected by the API.
+func setOptionalParams(params map[string]interface{}, o optionalParams) {
+	if o.SubType != nil {
+		params["sub_type"] = *o.SubType
+	}
+	if o.Port != nil {
+		params["port"] = *o.Port
+	}
+	if o.KeywordType != nil {
+		params["keyword_type"] = *o.KeywordType
+	}
+	if o.KeywordValue != nil {
+		params["keyword_value"] = *o.KeywordValue
+	}
+	if o.Interval != nil {
+		params["interval"] = int(*o.Interval / time.Second)
+	}
+	if o.Timeout != nil {
+		params["timeout"] = int(*o.Timeout / time.Second)
+	}
+	if o.IgnoreSSLErrors != nil {
+		params["ignore_ssl_errors"] = 0
+		if *o.IgnoreSSLErrors {
+			params["ignore_ssl_errors"] = 1
+		}
+	}
+}
+
+// encodeAlertContacts returns the alert contact IDs in the form expected by
+// the API: each ID followed by its notification threshold and recurrence from
+// settings (zero if it has none), separated by dashes.
+func encodeAlertContacts(IDs []ContactID, settings map[ContactID]ContactSettings) string {
+	contacts := make([]string, len(IDs))
+	for i, c := range IDs {
+		s := settings[c]
+		contacts[i] = fmt.Sprintf("%s_%d_%d", c, s.Threshold, s.Recurrence)
+	}
+	return strings.Join(contacts, "-")
+}
+
+// CreateMonitorWithParams creates a new Uptime Robot monitor with the
+// specified details. It returns the ID of the newly created monitor, or an
+// error if the operation failed.
+func (c *Client) CreateMonitorWithParams(p CreateMonitorParams) (MonitorID, error) {
+	return c.CreateMonitorWithParamsContext(context.Background(), p)
+}
+
+// CreateMonitorWithParamsContext is like CreateMonitorWithParams, but uses the
+// specified context for its API requests.
+func (c *Client) CreateMonitorWithParamsContext(ctx context.Context, p CreateMonitorParams) (MonitorID, error) {
+	var interval time.Duration
+	if p.Interval != nil {
+		interval = *p.Interval
+	}
+	if err := c.plan.check(ctx, c.AccountContext, interval); err != nil {
+		return 0, err
+	}
+	r := Response{}
+	if err := c.call(ctx, "newMonitor", p, &r); err != nil {
+		return 0, err
+	}
+	c.plan.add(1)
+	return r.Monitor.ID, nil
+}
+
+// EditMonitor makes the specified changes to an existing monitor, leaving any
+// settings not set in p unchanged. It returns a Monitor with the ID field set
+// to the ID of the monitor, or an error if the operation failed.
+func (c *Client) EditMonitor(p EditMonitorParams) (Monitor, error) {
+	return c.EditMonitorContext(context.Background(), p)
+}
+
+// EditMonitorContext is like EditMonitor, but uses the specified context for
+// its API requests.
+func (c *Client) EditMonitorContext(ctx context.Context, p EditMonitorParams) (Monitor, error) {
+	r := Response{}
+	if err := c.call(ctx, "editMonitor", p, &r); err != nil {
+		return Monitor{}, err
+	}
+	return r.Monitor, nil
+}
+
+// String returns a pointer to the specified string, for setting optional
+// fields in CreateMonitorParams, EditMonitorParams, and EditPSPParams.
+func String(s string) *string {
+	return &s
+}
+
+// Int returns a pointer to the specified int, for setting optional fields in
+// CreateMonitorParams, EditMonitorParams, and EditPSPParams.
+func Int(i int) *int {
+	return &i
+}
+
+// Bool returns a pointer to the specified bool, for setting optional fields in
+// CreateMonitorParams and EditMonitorParams.
+func Bool(b bool) *bool {
+	return &b
+}
+
+// Duration returns a pointer to the specified duration, for setting optional
+// fields in CreateMonitorParams and EditMonitorParams.
+func Duration(d time.Duration) *time.Duration {
+	return &d
+}
@@ -0,0 +1,102 @@
+// Package prommetrics records statistics about an uptimerobot client's API
+// requests, for export to Prometheus.
+package prommetrics
+
+import (
+	"context"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics records statistics about a client's API requests: how many were
+// made, how long they took, how many failed (by error type), and how many
+// were rejected because of the rate limit. It is an uptimerobot.Hook, so it
+// can be added to a client with WithHook, and it implements
+// prometheus.Collector, so it can be registered with any Prometheus
+// registry:
+//
+//	m := prommetrics.New()
+//	prometheus.MustRegister(m)
+//	client := uptimerobot.New(apiKey, uptimerobot.WithHook(m))
+//
+// One Metrics can be shared by several clients.
+type Metrics struct {
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	errors      *prometheus.CounterVec
+	rateLimited *prometheus.CounterVec
+}
+
+// New returns a new Metrics, with all counts set to zero.
+func New() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "uptimerobot",
+			Name:      "requests_total",
+			Help:      "Number of Uptime Robot API requests made, including retries.",
+		}, []string{"verb"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "uptimerobot",
+			Name:      "request_duration_seconds",
+			Help:      "Time taken by Uptime Robot API requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"verb"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "uptimerobot",
+			Name:      "errors_total",
+			Help:      "Number of failed Uptime Robot API requests, by error type.",
+		}, []string{"verb", "error_type"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "uptimerobot",
+			Name:      "rate_limited_total",
+			Help:      "Number of Uptime Robot API requests rejected because of the rate limit.",
+		}, []string{"verb"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.duration.Describe(ch)
+	m.errors.Describe(ch)
+	m.rateLimited.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.duration.Collect(ch)
+	m.errors.Collect(ch)
+	m.rateLimited.Collect(ch)
+}
+
+// StartCall implements uptimerobot.Hook.
+func (m *Metrics) StartCall(ctx context.Context, verb string) (context.Context, uptimerobot.CallHook) {
+	return ctx, callHook{m}
+}
+
+// callHook records the attempts made by a single API call.
+type callHook struct {
+	m *Metrics
+}
+
+// Attempt records the outcome of a single API request.
+func (h callHook) Attempt(a uptimerobot.Attempt) {
+	h.m.requests.WithLabelValues(a.Verb).Inc()
+	h.m.duration.WithLabelValues(a.Verb).Observe(a.Duration.Seconds())
+	if a.Err == nil {
+		return
+	}
+	h.m.errors.WithLabelValues(a.Verb, a.ErrorType).Inc()
+	if a.ErrorType == "rate_limit" {
+		h.m.rateLimited.WithLabelValues(a.Verb).Inc()
+	}
+}
+
+// Retry does nothing, since retries are counted as requests.
+func (callHook) Retry(time.Duration) {}
+
+// End does nothing, since each request has already been recorded.
+func (callHook) End(error) {}
@@ -0,0 +1,68 @@
+package prommetrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsRecordRequests(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 777810874}}`)
+		default:
+			fmt.Fprint(w, `{"stat": "fail", "error": {"type": "not_found", "message": "monitor not found"}}`)
+		}
+	}))
+	defer ts.Close()
+	m := New()
+	client := uptimerobot.New("dummy",
+		uptimerobot.WithHTTPClient(ts.Client()),
+		uptimerobot.WithBaseURL(ts.URL),
+		uptimerobot.WithHook(m),
+	)
+	if err := client.DeleteMonitor(777810874); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteMonitor(777810874); err == nil {
+		t.Fatal("want error from failed API call, got nil")
+	}
+	tcs := []struct {
+		name      string
+		collector prometheus.Collector
+		want      float64
+	}{
+		{"requests", m.requests.WithLabelValues("deleteMonitor"), 3},
+		{"rate limited", m.rateLimited.WithLabelValues("deleteMonitor"), 1},
+		{"rate limit errors", m.errors.WithLabelValues("deleteMonitor", "rate_limit"), 1},
+		{"not found errors", m.errors.WithLabelValues("deleteMonitor", "not_found"), 1},
+	}
+	for _, tc := range tcs {
+		got := testutil.ToFloat64(tc.collector)
+		if tc.want != got {
+			t.Errorf("%s: want %v, got %v", tc.name, tc.want, got)
+		}
+	}
+	if got := testutil.CollectAndCount(m.duration); got != 1 {
+		t.Errorf("want 1 duration histogram, got %d", got)
+	}
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.Gather(); err != nil {
+		t.Fatal(err)
+	}
+}
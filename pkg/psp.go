@@ -0,0 +1,365 @@
+package uptimerobot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PSP represents a public status page.
+//
+// The Monitors field lists the IDs of the monitors shown on the page; if it is
+// empty, the page shows all monitors. The Sort field gives the order in which
+// they are shown (for example PSPSortStatusDownUp). If the page is served on
+// a custom domain, CustomDomain gives its URL. PasswordProtected reports
+// whether visitors need a password to view the page. The API never returns
+// the password itself, but you can set the Password field to protect a new
+// page with a password.
+type PSP struct {
+	ID                PSPID
+	FriendlyName      string
+	Monitors          []MonitorID
+	Sort              int
+	Status            int
+	StandardURL       string
+	CustomDomain      string
+	PasswordProtected bool
+	Password          string
+}
+
+// PSPTemplate is the template used by PSP.String. You can use it as the
+// starting point for your own template, to pass to PSP.Render.
+const PSPTemplate = `ID: {{ .ID }}
+Name: {{ .FriendlyName }}
+URL: {{ .StandardURL -}}
+{{ if .CustomDomain }}{{ printf "\nCustom domain: %s" .CustomDomain }}{{ end }}
+Monitors: {{ if .Monitors }}{{ len .Monitors }}{{ else }}All{{ end }}
+Password protected: {{ if .PasswordProtected }}Yes{{ else }}No{{ end }}`
+
+// String returns a pretty-printed version of the status page.
+func (p PSP) String() string {
+	return renderString(PSPTemplate, p)
+}
+
+// Render returns the result of executing the specified text/template template
+// with the status page as its data, or an error if the template is invalid or
+// fails. The template can use the functions listed in TemplateFuncs.
+func (p PSP) Render(tmpl string) (string, error) {
+	return render(tmpl, p)
+}
+
+// UnmarshalJSON converts a JSON status page representation to a PSP struct,
+// handling the API's encoding of "all monitors" as 0 rather than a list of
+// monitor IDs.
+func (p *PSP) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID           PSPID           `json:"id"`
+		FriendlyName string          `json:"friendly_name"`
+		Monitors     json.RawMessage `json:"monitors"`
+		Sort         FlexInt         `json:"sort"`
+		Status       FlexInt         `json:"status"`
+		StandardURL  string          `json:"standard_url"`
+		CustomURL    string          `json:"custom_url"`
+		Password     interface{}     `json:"password"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = PSP{
+		ID:           raw.ID,
+		FriendlyName: raw.FriendlyName,
+		Monitors:     []MonitorID{},
+		Sort:         int(raw.Sort),
+		Status:       int(raw.Status),
+		StandardURL:  raw.StandardURL,
+		CustomDomain: raw.CustomURL,
+	}
+	// Monitors are given either as a list of IDs (or, occasionally, a
+	// dash-separated string of them), or as 0 (or "0") for all monitors
+	switch s := string(raw.Monitors); {
+	case s == "", s == "0", s == `"0"`, s == `""`, s == "null":
+	case strings.HasPrefix(s, `"`):
+		if err := json.Unmarshal(raw.Monitors, &s); err != nil {
+			return err
+		}
+		for _, f := range strings.Split(s, "-") {
+			ID, err := ParseMonitorID(f)
+			if err != nil {
+				return fmt.Errorf("invalid status page monitors %s: %v", raw.Monitors, err)
+			}
+			p.Monitors = append(p.Monitors, ID)
+		}
+	default:
+		if err := json.Unmarshal(raw.Monitors, &p.Monitors); err != nil {
+			return fmt.Errorf("invalid status page monitors %s: %v", raw.Monitors, err)
+		}
+	}
+	// The password is never returned, but a non-empty value indicates that
+	// one is set
+	switch v := raw.Password.(type) {
+	case string:
+		p.PasswordProtected = v != ""
+	case bool:
+		p.PasswordProtected = v
+	}
+	return nil
+}
+
+// AllPSPs returns all the public status pages associated with the account.
+func (c *Client) AllPSPs() ([]PSP, error) {
+	return c.AllPSPsContext(context.Background())
+}
+
+// AllPSPsContext is like AllPSPs, but uses the specified context for its API
+// requests.
+func (c *Client) AllPSPsContext(ctx context.Context) ([]PSP, error) {
+	psps := []PSP{}
+	offset := 0
+	total := 0
+	for offset <= total {
+		req := getPSPsRequest{
+			Offset: strconv.Itoa(offset),
+			Limit:  strconv.Itoa(c.pageSize()),
+		}
+		r := Response{}
+		if err := c.call(ctx, "getPSPs", req, &r); err != nil {
+			return nil, err
+		}
+		psps = append(psps, r.PSPs...)
+		total = r.Pagination.Total
+		offset = r.Pagination.Offset + c.pageSize()
+	}
+	return psps, nil
+}
+
+// GetPSP takes the ID of an existing status page, and returns the
+// corresponding PSP. If there is no such page, it returns a NotFoundError.
+func (c *Client) GetPSP(ID PSPID) (PSP, error) {
+	return c.GetPSPContext(context.Background(), ID)
+}
+
+// GetPSPContext is like GetPSP, but uses the specified context for its API
+// requests.
+func (c *Client) GetPSPContext(ctx context.Context, ID PSPID) (PSP, error) {
+	req := getPSPsRequest{
+		PSPs: ID.String(),
+	}
+	r := Response{}
+	if err := c.call(ctx, "getPSPs", req, &r); err != nil {
+		return PSP{}, err
+	}
+	if len(r.PSPs) == 0 {
+		return PSP{}, NotFoundError{
+			Resource: "status page",
+			ID:       ID.String(),
+		}
+	}
+	return r.PSPs[0], nil
+}
+
+// newPSPRequest returns the request parameters representing the status page.
+// The API expects the monitors as a dash-separated list of IDs, or 0 for all
+// monitors.
+func newPSPRequest(p PSP) pspRequest {
+	req := pspRequest{
+		Type:         1,
+		FriendlyName: p.FriendlyName,
+		Monitors:     "0",
+		CustomDomain: p.CustomDomain,
+		Password:     p.Password,
+		Sort:         p.Sort,
+	}
+	if len(p.Monitors) > 0 {
+		req.Monitors = joinIDs(p.Monitors)
+	}
+	return req
+}
+
+// CreatePSP takes a PSP and creates a new public status page with the
+// specified details. It returns the ID of the newly created page, or an error
+// if the operation failed.
+func (c *Client) CreatePSP(p PSP) (PSPID, error) {
+	return c.CreatePSPContext(context.Background(), p)
+}
+
+// CreatePSPContext is like CreatePSP, but uses the specified context for its
+// API requests.
+func (c *Client) CreatePSPContext(ctx context.Context, p PSP) (PSPID, error) {
+	r := Response{}
+	if err := c.call(ctx, "newPSP", newPSPRequest(p), &r); err != nil {
+		return 0, err
+	}
+	return r.PSP.ID, nil
+}
+
+// EditPSPParams represents the changes to be made to an existing status page
+// with EditPSP. The ID field is required. The remaining fields are optional,
+// and only those which are set will be changed. Use the helper functions
+// String and Int to set them. To show all monitors on the page, set Monitors
+// to a pointer to an empty slice.
+type EditPSPParams struct {
+	ID           PSPID
+	FriendlyName *string
+	Monitors     *[]MonitorID
+	Sort         *int
+	Status       *int
+	CustomDomain *string
+	Password     *string
+}
+
+// MarshalJSON converts the parameters to the JSON representation expected by
+// the API, omitting any unset fields.
+func (p EditPSPParams) MarshalJSON() ([]byte, error) {
+	params := map[string]interface{}{
+		"id": p.ID.String(),
+	}
+	if p.FriendlyName != nil {
+		params["friendly_name"] = *p.FriendlyName
+	}
+	if p.Monitors != nil {
+		params["monitors"] = "0"
+		if len(*p.Monitors) > 0 {
+			params["monitors"] = joinIDs(*p.Monitors)
+		}
+	}
+	if p.Sort != nil {
+		params["sort"] = *p.Sort
+	}
+	if p.Status != nil {
+		params["status"] = *p.Status
+	}
+	if p.CustomDomain != nil {
+		params["custom_domain"] = *p.CustomDomain
+	}
+	if p.Password != nil {
+		params["password"] = *p.Password
+	}
+	return json.Marshal(params)
+}
+
+// EditPSP makes the specified changes to an existing status page, leaving any
+// settings not set in p unchanged. It returns a PSP with the ID field set to
+// the ID of the page, or an error if the operation failed.
+func (c *Client) EditPSP(p EditPSPParams) (PSP, error) {
+	return c.EditPSPContext(context.Background(), p)
+}
+
+// EditPSPContext is like EditPSP, but uses the specified context for its API
+// requests.
+func (c *Client) EditPSPContext(ctx context.Context, p EditPSPParams) (PSP, error) {
+	r := Response{}
+	if err := c.call(ctx, "editPSP", p, &r); err != nil {
+		return PSP{}, err
+	}
+	return r.PSP, nil
+}
+
+// DeletePSP takes a status page ID and deletes the corresponding page. If
+// there is no such page, it returns a NotFoundError; otherwise, it returns an
+// error if the operation failed.
+func (c *Client) DeletePSP(ID PSPID) error {
+	return c.DeletePSPContext(context.Background(), ID)
+}
+
+// DeletePSPContext is like DeletePSP, but uses the specified context for its
+// API requests.
+func (c *Client) DeletePSPContext(ctx context.Context, ID PSPID) error {
+	req := deletePSPRequest{
+		ID: ID,
+	}
+	r := Response{}
+	if err := c.call(ctx, "deletePSP", req, &r); err != nil {
+		if r.Error["type"] == "not_found" {
+			return NotFoundError{
+				Resource: "status page",
+				ID:       ID.String(),
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// AddMonitorToPSP adds the specified monitor to an existing status page,
+// keeping any monitors already shown on it. If the monitor is already shown,
+// or the page shows all monitors, the page is not changed.
+func (c *Client) AddMonitorToPSP(pspID PSPID, monitorID MonitorID) error {
+	return c.AddMonitorToPSPContext(context.Background(), pspID, monitorID)
+}
+
+// AddMonitorToPSPContext is like AddMonitorToPSP, but uses the specified
+// context for its API requests.
+func (c *Client) AddMonitorToPSPContext(ctx context.Context, pspID PSPID, monitorID MonitorID) error {
+	p, err := c.GetPSPContext(ctx, pspID)
+	if err != nil {
+		return err
+	}
+	if len(p.Monitors) == 0 {
+		return nil
+	}
+	for _, ID := range p.Monitors {
+		if ID == monitorID {
+			return nil
+		}
+	}
+	monitors := append(p.Monitors, monitorID)
+	_, err = c.EditPSPContext(ctx, EditPSPParams{
+		ID:       pspID,
+		Monitors: &monitors,
+	})
+	return err
+}
+
+// ErrLastPSPMonitor is returned by RemoveMonitorFromPSP if the monitor is the
+// only one shown on the status page. The API treats a page with no monitors
+// as showing all of them, so the last monitor can't be removed; delete the
+// page instead.
+var ErrLastPSPMonitor = errors.New("can't remove the only monitor shown on a status page")
+
+// RemoveMonitorFromPSP removes the specified monitor from an existing status
+// page, keeping any other monitors shown on it. If the page shows all
+// monitors, it is changed to show all the account's monitors except this one.
+// If the monitor is not shown, the page is not changed. If it is the only
+// monitor shown, the page is not changed, and the error is ErrLastPSPMonitor.
+func (c *Client) RemoveMonitorFromPSP(pspID PSPID, monitorID MonitorID) error {
+	return c.RemoveMonitorFromPSPContext(context.Background(), pspID, monitorID)
+}
+
+// RemoveMonitorFromPSPContext is like RemoveMonitorFromPSP, but uses the
+// specified context for its API requests.
+func (c *Client) RemoveMonitorFromPSPContext(ctx context.Context, pspID PSPID, monitorID MonitorID) error {
+	p, err := c.GetPSPContext(ctx, pspID)
+	if err != nil {
+		return err
+	}
+	current := p.Monitors
+	if len(current) == 0 {
+		all, err := c.AllMonitorsContext(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range all {
+			current = append(current, m.ID)
+		}
+	}
+	monitors := []MonitorID{}
+	for _, ID := range current {
+		if ID != monitorID {
+			monitors = append(monitors, ID)
+		}
+	}
+	if len(monitors) == len(current) {
+		return nil
+	}
+	if len(monitors) == 0 {
+		return ErrLastPSPMonitor
+	}
+	_, err = c.EditPSPContext(ctx, EditPSPParams{
+		ID:       pspID,
+		Monitors: &monitors,
+	})
+	return err
+}
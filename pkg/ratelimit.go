@@ -0,0 +1,173 @@
+package uptimerobot
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRetryAfter is how long to wait before retrying a rate-limited
+// request, if the API doesn't say.
+const defaultRetryAfter = 10 * time.Second
+
+// RateLimitError is returned when a request is rejected because the account
+// has made too many requests. RetryAfter gives how long the API asked the
+// client to wait before trying again, or zero if it didn't say.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e RateLimitError) Error() string {
+	if e.RetryAfter == 0 {
+		return "API rate limit exceeded"
+	}
+	return fmt.Sprintf("API rate limit exceeded (retry after %s)", e.RetryAfter)
+}
+
+// FreePlanRequestsPerMinute is the API's rate limit for free accounts. Paid
+// plans have higher limits, which depend on the plan.
+const FreePlanRequestsPerMinute = 10
+
+// RateLimiter decides when a client may make each API request. Before every
+// request, including retries, the client calls Wait, which should block until
+// the request may be made. If the context is cancelled first, Wait should
+// return the context's error, and the request is not made.
+//
+// Set a client's RateLimiter field (or use WithRateLimiter) to apply your own
+// policy, for example to share an account's limit between several programs.
+// A RateLimiter must be safe for concurrent use.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a RateLimiter which allows requests at a steady rate, with
+// bursts of up to a certain number of requests after a quiet period. Create
+// one with NewTokenBucket or DefaultRateLimiter.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a TokenBucket which allows perMinute requests per
+// minute, with bursts of up to burst requests (at least 1). If perMinute is
+// zero or less, requests are not limited.
+func NewTokenBucket(perMinute, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   float64(perMinute) / 60,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// DefaultRateLimiter returns a TokenBucket tuned to the API's limit for free
+// accounts: FreePlanRequestsPerMinute requests per minute, all of which may
+// be made at once.
+func DefaultRateLimiter() *TokenBucket {
+	return NewTokenBucket(FreePlanRequestsPerMinute, FreePlanRequestsPerMinute)
+}
+
+// Wait blocks until a request may be made, taking a token from the bucket,
+// or until the context is cancelled.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	now := time.Now()
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	// Take the token now, even if it isn't there yet, so that concurrent
+	// callers queue up behind this one
+	b.tokens--
+	wait := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRateLimit reports whether the API error indicates that the rate limit was
+// exceeded.
+func (e Error) isRateLimit() bool {
+	for _, k := range []string{"type", "message"} {
+		v, _ := e[k].(string)
+		v = strings.ToLower(v)
+		if strings.Contains(v, "rate limit") || strings.Contains(v, "rate_limit") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRetryAfter returns the wait time given by a Retry-After header, which
+// may be either a number of seconds or an HTTP date, relative to now. It
+// returns zero if the header is empty or invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// pacer spaces out a client's requests, so that each starts at least a
+// certain interval after the previous one.
+type pacer struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the next request may be made, reserving the following
+// slot, or until the context is cancelled. A nil pacer, or a zero interval,
+// never waits.
+func (p *pacer) wait(ctx context.Context, interval time.Duration) error {
+	if p == nil || interval <= 0 {
+		return nil
+	}
+	p.mu.Lock()
+	now := time.Now()
+	start := now
+	if p.next.After(now) {
+		start = p.next
+	}
+	p.next = start.Add(interval)
+	p.mu.Unlock()
+	if start == now {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(start.Sub(now)):
+		return nil
+	}
+}
@@ -0,0 +1,167 @@
+package uptimerobot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// The API accepts most parameters as strings, and encodes lists of values as
+// dash-separated strings. The request types below represent the parameters of
+// each API call, so that request bodies can be marshaled safely.
+
+// getMonitorsRequest represents the parameters of a getMonitors call.
+type getMonitorsRequest struct {
+	Monitors      string `json:"monitors,omitempty"`
+	Types         string `json:"types,omitempty"`
+	Statuses      string `json:"statuses,omitempty"`
+	Search        string `json:"search,omitempty"`
+	Sort          string `json:"sort,omitempty"`
+	Logs          string `json:"logs,omitempty"`
+	LogsStartDate string `json:"logs_start_date,omitempty"`
+	LogsEndDate   string `json:"logs_end_date,omitempty"`
+	ResponseTimes string `json:"response_times,omitempty"`
+	RTStartDate   string `json:"response_times_start_date,omitempty"`
+	RTEndDate     string `json:"response_times_end_date,omitempty"`
+	UptimeRatios  string `json:"custom_uptime_ratios,omitempty"`
+	UptimeRanges  string `json:"custom_uptime_ranges,omitempty"`
+	AllTimeRatio  string `json:"all_time_uptime_ratio,omitempty"`
+	AlertContacts string `json:"alert_contacts,omitempty"`
+	Timezone      string `json:"timezone,omitempty"`
+	Offset        string `json:"offset,omitempty"`
+	Limit         string `json:"limit,omitempty"`
+}
+
+// getAlertContactsRequest represents the parameters of a getAlertContacts
+// call.
+type getAlertContactsRequest struct {
+	AlertContacts string `json:"alert_contacts,omitempty"`
+	Offset        string `json:"offset,omitempty"`
+	Limit         string `json:"limit,omitempty"`
+}
+
+// newAlertContactRequest represents the parameters of a newAlertContact call.
+type newAlertContactRequest struct {
+	Type         int    `json:"type"`
+	Value        string `json:"value"`
+	FriendlyName string `json:"friendly_name"`
+}
+
+// getMWindowsRequest represents the parameters of a getMWindows call.
+type getMWindowsRequest struct {
+	MWindows string `json:"mwindows,omitempty"`
+	Offset   string `json:"offset,omitempty"`
+	Limit    string `json:"limit,omitempty"`
+}
+
+// mwindowRequest represents the parameters of a newMWindow call.
+type mwindowRequest struct {
+	FriendlyName string `json:"friendly_name"`
+	Type         int    `json:"type"`
+	Value        string `json:"value,omitempty"`
+	StartTime    string `json:"start_time"`
+	Duration     int    `json:"duration"`
+}
+
+// editMWindowRequest represents the parameters of an editMWindow call. The
+// API does not allow the type of a window to be changed.
+type editMWindowRequest struct {
+	ID           MWindowID `json:"id,string"`
+	FriendlyName string    `json:"friendly_name"`
+	Value        string    `json:"value,omitempty"`
+	StartTime    string    `json:"start_time"`
+	Duration     int       `json:"duration"`
+}
+
+// getPSPsRequest represents the parameters of a getPSPs call.
+type getPSPsRequest struct {
+	PSPs   string `json:"psps,omitempty"`
+	Offset string `json:"offset,omitempty"`
+	Limit  string `json:"limit,omitempty"`
+}
+
+// pspRequest represents the parameters of a newPSP call. The only type of
+// status page currently supported by the API is 1.
+type pspRequest struct {
+	Type         int    `json:"type"`
+	FriendlyName string `json:"friendly_name"`
+	Monitors     string `json:"monitors"`
+	CustomDomain string `json:"custom_domain,omitempty"`
+	Password     string `json:"password,omitempty"`
+	Sort         int    `json:"sort,omitempty"`
+}
+
+// editMonitorStatusRequest represents the parameters of an editMonitor call
+// which pauses or resumes a monitor.
+type editMonitorStatusRequest struct {
+	ID     MonitorID `json:"id,string"`
+	Status int       `json:"status"`
+}
+
+// deleteMonitorRequest represents the parameters of a deleteMonitor call.
+type deleteMonitorRequest struct {
+	ID MonitorID `json:"id,string"`
+}
+
+// deleteMWindowRequest represents the parameters of a deleteMWindow call.
+type deleteMWindowRequest struct {
+	ID MWindowID `json:"id,string"`
+}
+
+// deletePSPRequest represents the parameters of a deletePSP call.
+type deletePSPRequest struct {
+	ID PSPID `json:"id,string"`
+}
+
+// call marshals the request parameters to JSON, and calls the API with the
+// specified verb and context, storing the returned data in the Response
+// struct.
+func (c *Client) call(ctx context.Context, verb string, params interface{}, r *Response) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("encoding %s request: %v", verb, err)
+	}
+	return c.MakeAPICallContext(ctx, verb, r, data)
+}
+
+// Do calls any API verb, including those the package doesn't otherwise
+// support, with the specified parameters, and decodes the response into out.
+// The parameters are encoded as JSON, so params is typically a struct with
+// JSON tags, or a map, and may be nil. out is typically a pointer to a struct
+// with fields for the parts of the response you need, and may be nil. The API
+// key, rate limiting, retries, dry-run and read-only modes, and error handling
+// all work just as for the package's own calls:
+//
+//	var out struct {
+//		Stat string `json:"stat"`
+//	}
+//	err := client.Do("getMonitors", map[string]string{"search": "example"}, &out)
+//
+// In dry-run mode, out is left unchanged for calls which would change the
+// account.
+func (c *Client) Do(verb string, params interface{}, out interface{}) error {
+	return c.DoContext(context.Background(), verb, params, out)
+}
+
+// DoContext is like Do, but uses the specified context for its API requests.
+func (c *Client) DoContext(ctx context.Context, verb string, params interface{}, out interface{}) error {
+	var data []byte
+	if params != nil {
+		var err error
+		data, err = json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("encoding %s request: %v", verb, err)
+		}
+	}
+	r := Response{keepRaw: out != nil}
+	if err := c.MakeAPICallContext(ctx, verb, &r, data); err != nil {
+		return err
+	}
+	if out == nil || r.raw == nil {
+		return nil
+	}
+	if err := json.Unmarshal(r.raw, out); err != nil {
+		return fmt.Errorf("decoding %s response: %v", verb, err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package uptimerobot
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// SLO is a service level objective for a monitor's availability: the
+// percentage of the window from Start to End during which the monitor should
+// be up. For example, an SLO of 99.9% over a 30-day month allows about 43
+// minutes of downtime.
+type SLO struct {
+	Target float64
+	Start  time.Time
+	End    time.Time
+}
+
+// MonthlySLO returns an SLO with the specified target percentage over the
+// calendar month containing t, in t's location.
+func MonthlySLO(target float64, t time.Time) SLO {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return SLO{
+		Target: target,
+		Start:  start,
+		End:    start.AddDate(0, 1, 0),
+	}
+}
+
+// ErrorBudget describes how much of an SLO's error budget, the downtime it
+// allows over its whole window, has been used up by the time given by Now.
+// Remaining is negative if the budget is overspent.
+//
+// BurnRate is the rate at which the budget is being spent, relative to the
+// rate which would use it up exactly at the end of the window: a burn rate of
+// 1 spends the budget just in time, and a burn rate of 2 spends it halfway
+// through. If the SLO allows no downtime at all, any downtime gives an
+// infinite burn rate.
+type ErrorBudget struct {
+	SLO       SLO
+	Now       time.Time
+	Elapsed   time.Duration
+	Budget    time.Duration
+	Downtime  time.Duration
+	Remaining time.Duration
+	BurnRate  float64
+}
+
+// String returns a one-line summary of the error budget.
+func (b ErrorBudget) String() string {
+	return fmt.Sprintf("target=%g%% budget=%s downtime=%s remaining=%s burn=%.2f", b.SLO.Target, b.Budget, b.Downtime, b.Remaining, b.BurnRate)
+}
+
+// Budget returns the SLO's error budget as of now, given the downtime so far
+// in the window. Times after the end of the window count as the end.
+func (s SLO) Budget(downtime time.Duration, now time.Time) ErrorBudget {
+	if now.After(s.End) {
+		now = s.End
+	}
+	elapsed := now.Sub(s.Start)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	allowed := 1 - s.Target/100
+	b := ErrorBudget{
+		SLO:      s,
+		Now:      now,
+		Elapsed:  elapsed,
+		Budget:   time.Duration(allowed * float64(s.End.Sub(s.Start))),
+		Downtime: downtime,
+	}
+	b.Remaining = b.Budget - downtime
+	switch {
+	case downtime == 0 || elapsed == 0:
+		b.BurnRate = 0
+	case allowed <= 0:
+		b.BurnRate = math.Inf(1)
+	default:
+		b.BurnRate = float64(downtime) / float64(elapsed) / allowed
+	}
+	return b
+}
+
+// BudgetFromUptime is like Budget, but takes the monitor's uptime percentage
+// over the window so far, such as one of the UptimeRanges requested with
+// WithUptimeRanges, instead of its downtime.
+func (s SLO) BudgetFromUptime(uptime float64, now time.Time) ErrorBudget {
+	b := s.Budget(0, now)
+	return s.Budget(time.Duration((1-uptime/100)*float64(b.Elapsed)), now)
+}
+
+// BudgetFromLogs is like Budget, but calculates the downtime from the
+// monitor's event log, counting the part of each down period which falls in
+// the window so far.
+func (s SLO) BudgetFromLogs(logs []MonitorLog, now time.Time) ErrorBudget {
+	end := now
+	if end.After(s.End) {
+		end = s.End
+	}
+	var downtime time.Duration
+	for _, l := range logs {
+		if l.Type != LogTypeDown {
+			continue
+		}
+		from, to := l.Datetime, l.Datetime.Add(l.Duration)
+		if from.Before(s.Start) {
+			from = s.Start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			downtime += to.Sub(from)
+		}
+	}
+	return s.Budget(downtime, now)
+}
+
+// ErrorBudget calculates the monitor's error budget for the specified SLO as
+// of now, from its event log, as BudgetFromLogs does. The monitor must have
+// been fetched with WithLogs, or with WithLogsSince a time early enough to
+// include any down period still going on at the start of the SLO's window.
+func (m Monitor) ErrorBudget(s SLO, now time.Time) ErrorBudget {
+	return s.BudgetFromLogs(m.Logs, now)
+}
+
+// GetErrorBudget fetches the event log of the monitor with the specified ID,
+// and returns its error budget for the SLO as of now. The whole log is
+// fetched, rather than only the entries since the start of the window, so
+// that a down period which began before the window and continued into it is
+// counted.
+func (c *Client) GetErrorBudget(monitorID MonitorID, s SLO) (ErrorBudget, error) {
+	return c.GetErrorBudgetContext(context.Background(), monitorID, s)
+}
+
+// GetErrorBudgetContext is like GetErrorBudget, but uses the specified context
+// for its API request.
+func (c *Client) GetErrorBudgetContext(ctx context.Context, monitorID MonitorID, s SLO) (ErrorBudget, error) {
+	m, err := c.GetMonitorContext(ctx, monitorID, WithLogs())
+	if err != nil {
+		return ErrorBudget{}, err
+	}
+	return m.ErrorBudget(s, time.Now()), nil
+}
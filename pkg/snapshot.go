@@ -0,0 +1,483 @@
+package uptimerobot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Snapshot holds the configuration of an Uptime Robot account: its monitors,
+// alert contacts, maintenance windows, and public status pages. Use Export to
+// take a snapshot of an account, and Restore to recreate it, either in the
+// same account (for disaster recovery) or in a different one (for migration).
+//
+// A Snapshot can be saved as JSON or YAML, using the encoding/json or
+// gopkg.in/yaml.v3 packages; both formats use the same snake_case field names
+// as the API, and give durations, such as a monitor's interval, in seconds.
+// A maintenance window's start time is written as 'HH:MM' if it recurs, or
+// as an RFC 3339 timestamp if not.
+type Snapshot struct {
+	Monitors      []Monitor
+	AlertContacts []AlertContact
+	MWindows      []MWindow
+	PSPs          []PSP
+}
+
+// snapshotFile is the serialized form of a Snapshot. Its fields, and those
+// of the types it contains, use the same snake_case names as the API, and
+// durations are given in seconds.
+type snapshotFile struct {
+	Monitors      []snapshotMonitor      `json:"monitors"`
+	AlertContacts []snapshotAlertContact `json:"alert_contacts"`
+	MWindows      []snapshotMWindow      `json:"mwindows"`
+	PSPs          []snapshotPSP          `json:"psps"`
+}
+
+// snapshotMonitor is the serialized form of a monitor's settings.
+type snapshotMonitor struct {
+	ID              MonitorID                     `json:"id"`
+	FriendlyName    string                        `json:"friendly_name"`
+	URL             string                        `json:"url"`
+	Type            int                           `json:"type"`
+	SubType         int                           `json:"sub_type,omitempty"`
+	Port            int                           `json:"port,omitempty"`
+	KeywordType     int                           `json:"keyword_type,omitempty"`
+	KeywordValue    string                        `json:"keyword_value,omitempty"`
+	Interval        int64                         `json:"interval,omitempty"`
+	Timeout         int64                         `json:"timeout,omitempty"`
+	IgnoreSSLErrors bool                          `json:"ignore_ssl_errors,omitempty"`
+	Status          Status                        `json:"status"`
+	AlertContacts   []ContactID                   `json:"alert_contacts"`
+	ContactSettings map[ContactID]ContactSettings `json:"contact_settings,omitempty"`
+}
+
+// snapshotAlertContact is the serialized form of an alert contact.
+type snapshotAlertContact struct {
+	ID           ContactID `json:"id"`
+	FriendlyName string    `json:"friendly_name"`
+	Type         int       `json:"type"`
+	Status       int       `json:"status"`
+	Value        string    `json:"value"`
+}
+
+// snapshotMWindow is the serialized form of a maintenance window. StartTime
+// is 'HH:MM' for a recurring window, or an RFC 3339 timestamp for a one-off
+// window.
+type snapshotMWindow struct {
+	ID           MWindowID `json:"id"`
+	FriendlyName string    `json:"friendly_name"`
+	Type         int       `json:"type"`
+	Value        string    `json:"value,omitempty"`
+	StartTime    string    `json:"start_time"`
+	Duration     int64     `json:"duration"`
+	Status       int       `json:"status"`
+}
+
+// snapshotPSP is the serialized form of a status page. An empty list of
+// monitors means all monitors, as for PSP.
+type snapshotPSP struct {
+	ID                PSPID       `json:"id"`
+	FriendlyName      string      `json:"friendly_name"`
+	Monitors          []MonitorID `json:"monitors"`
+	Sort              int         `json:"sort,omitempty"`
+	Status            int         `json:"status"`
+	StandardURL       string      `json:"standard_url,omitempty"`
+	CustomDomain      string      `json:"custom_domain,omitempty"`
+	PasswordProtected bool        `json:"password_protected,omitempty"`
+	Password          string      `json:"password,omitempty"`
+}
+
+// MarshalJSON encodes the snapshot as JSON. Only the monitors' settings are
+// saved, not their logs, response times, or uptime ratios.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	f := snapshotFile{
+		Monitors:      make([]snapshotMonitor, len(s.Monitors)),
+		AlertContacts: make([]snapshotAlertContact, len(s.AlertContacts)),
+		MWindows:      make([]snapshotMWindow, len(s.MWindows)),
+		PSPs:          make([]snapshotPSP, len(s.PSPs)),
+	}
+	for i, m := range s.Monitors {
+		f.Monitors[i] = snapshotMonitor{
+			ID:              m.ID,
+			FriendlyName:    m.FriendlyName,
+			URL:             m.URL,
+			Type:            m.Type,
+			SubType:         m.SubType,
+			Port:            m.Port,
+			KeywordType:     m.KeywordType,
+			KeywordValue:    m.KeywordValue,
+			Interval:        int64(m.Interval / time.Second),
+			Timeout:         int64(m.Timeout / time.Second),
+			IgnoreSSLErrors: m.IgnoreSSLErrors,
+			Status:          m.Status,
+			AlertContacts:   append([]ContactID{}, m.AlertContacts...),
+			ContactSettings: m.ContactSettings,
+		}
+	}
+	for i, a := range s.AlertContacts {
+		f.AlertContacts[i] = snapshotAlertContact(a)
+	}
+	for i, w := range s.MWindows {
+		start := w.StartTime.Format("15:04")
+		if w.Type == MWindowTypeOnce {
+			start = w.StartTime.Format(time.RFC3339)
+		}
+		f.MWindows[i] = snapshotMWindow{
+			ID:           w.ID,
+			FriendlyName: w.FriendlyName,
+			Type:         w.Type,
+			Value:        w.Value,
+			StartTime:    start,
+			Duration:     int64(w.Duration / time.Second),
+			Status:       w.Status,
+		}
+	}
+	for i, p := range s.PSPs {
+		f.PSPs[i] = snapshotPSP{
+			ID:                p.ID,
+			FriendlyName:      p.FriendlyName,
+			Monitors:          append([]MonitorID{}, p.Monitors...),
+			Sort:              p.Sort,
+			Status:            p.Status,
+			StandardURL:       p.StandardURL,
+			CustomDomain:      p.CustomDomain,
+			PasswordProtected: p.PasswordProtected,
+			Password:          p.Password,
+		}
+	}
+	return json.Marshal(f)
+}
+
+// UnmarshalJSON decodes a snapshot encoded by MarshalJSON.
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	f := snapshotFile{}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*s = Snapshot{
+		Monitors:      make([]Monitor, len(f.Monitors)),
+		AlertContacts: make([]AlertContact, len(f.AlertContacts)),
+		MWindows:      make([]MWindow, len(f.MWindows)),
+		PSPs:          make([]PSP, len(f.PSPs)),
+	}
+	for i, m := range f.Monitors {
+		s.Monitors[i] = Monitor{
+			ID:              m.ID,
+			FriendlyName:    m.FriendlyName,
+			URL:             m.URL,
+			Type:            m.Type,
+			SubType:         m.SubType,
+			Port:            m.Port,
+			KeywordType:     m.KeywordType,
+			KeywordValue:    m.KeywordValue,
+			Interval:        time.Duration(m.Interval) * time.Second,
+			Timeout:         time.Duration(m.Timeout) * time.Second,
+			IgnoreSSLErrors: m.IgnoreSSLErrors,
+			Status:          m.Status,
+			AlertContacts:   m.AlertContacts,
+			ContactSettings: m.ContactSettings,
+		}
+	}
+	for i, a := range f.AlertContacts {
+		s.AlertContacts[i] = AlertContact(a)
+	}
+	for i, w := range f.MWindows {
+		layout := "15:04"
+		if w.Type == MWindowTypeOnce {
+			layout = time.RFC3339
+		}
+		start, err := time.Parse(layout, w.StartTime)
+		if err != nil {
+			return fmt.Errorf("invalid start time %q for maintenance window %q: %v", w.StartTime, w.FriendlyName, err)
+		}
+		s.MWindows[i] = MWindow{
+			ID:           w.ID,
+			FriendlyName: w.FriendlyName,
+			Type:         w.Type,
+			Value:        w.Value,
+			StartTime:    start,
+			Duration:     time.Duration(w.Duration) * time.Second,
+			Status:       w.Status,
+		}
+	}
+	for i, p := range f.PSPs {
+		s.PSPs[i] = PSP{
+			ID:                p.ID,
+			FriendlyName:      p.FriendlyName,
+			Monitors:          p.Monitors,
+			Sort:              p.Sort,
+			Status:            p.Status,
+			StandardURL:       p.StandardURL,
+			CustomDomain:      p.CustomDomain,
+			PasswordProtected: p.PasswordProtected,
+			Password:          p.Password,
+		}
+	}
+	return nil
+}
+
+// MarshalYAML encodes the snapshot as YAML, with the same field names and
+// values as MarshalJSON.
+func (s Snapshot) MarshalYAML() (interface{}, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return yamlNumbers(v), nil
+}
+
+// yamlNumbers replaces the JSON numbers in a decoded JSON value with integers
+// or floats, so that they are encoded as YAML numbers, and integers aren't
+// written in exponent form.
+func yamlNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = yamlNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = yamlNumbers(e)
+		}
+	}
+	return v
+}
+
+// UnmarshalYAML decodes a snapshot encoded by MarshalYAML.
+func (s *Snapshot) UnmarshalYAML(value *yaml.Node) error {
+	var v interface{}
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, s)
+}
+
+// Export returns a Snapshot of the account's monitors (with their alert
+// contacts), alert contacts, maintenance windows, and public status pages.
+// The API never returns status page passwords, so they are not included.
+func (c *Client) Export() (Snapshot, error) {
+	return c.ExportContext(context.Background())
+}
+
+// ExportContext is like Export, but uses the specified context for its API
+// requests.
+func (c *Client) ExportContext(ctx context.Context) (Snapshot, error) {
+	monitors, err := c.AllMonitorsContext(ctx, WithAlertContacts())
+	if err != nil {
+		return Snapshot{}, err
+	}
+	contacts, err := c.AllAlertContactsContext(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	windows, err := c.AllMWindowsContext(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	psps, err := c.AllPSPsContext(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Snapshot{
+		Monitors:      monitors,
+		AlertContacts: contacts,
+		MWindows:      windows,
+		PSPs:          psps,
+	}, nil
+}
+
+// RestoreReport describes the changes made by Restore. AlertContacts,
+// MWindows, and PSPs hold the alert contacts, maintenance windows, and status
+// pages which were created, with their new IDs set, and Monitors describes
+// the changes made to monitors, as for SyncMonitors.
+type RestoreReport struct {
+	AlertContacts []AlertContact
+	Monitors      SyncReport
+	MWindows      []MWindow
+	PSPs          []PSP
+}
+
+// Restore recreates the configuration in the snapshot, so that it is safe to
+// run more than once, or against an account which already holds some of it.
+//
+// Alert contacts are matched by type and value, and any which don't exist are
+// created. Monitors are then made to match the snapshot as for SyncMonitors,
+// which is passed opts, with their alert contacts changed to the IDs of the
+// corresponding contacts in this account. Maintenance windows and status pages
+// are matched by name, and any which don't exist are created; a status page's
+// monitors are matched by URL. Existing alert contacts, maintenance windows,
+// and status pages are left as they are.
+//
+// If an operation fails, Restore stops and returns the error, together with
+// a report of the changes made so far.
+func (c *Client) Restore(s Snapshot, opts ...SyncOption) (RestoreReport, error) {
+	return c.RestoreContext(context.Background(), s, opts...)
+}
+
+// RestoreContext is like Restore, but uses the specified context for its API
+// requests.
+func (c *Client) RestoreContext(ctx context.Context, s Snapshot, opts ...SyncOption) (RestoreReport, error) {
+	report := RestoreReport{}
+	contactIDs, err := c.restoreAlertContacts(ctx, s.AlertContacts, &report)
+	if err != nil {
+		return report, err
+	}
+	desired := make([]Monitor, len(s.Monitors))
+	for i, m := range s.Monitors {
+		m.ID = 0
+		m.Status = 0
+		if m.AlertContacts != nil {
+			contacts := make([]ContactID, len(m.AlertContacts))
+			for j, ID := range m.AlertContacts {
+				contacts[j] = ID
+				if newID, ok := contactIDs[ID]; ok {
+					contacts[j] = newID
+				}
+			}
+			m.AlertContacts = contacts
+		}
+		desired[i] = m
+	}
+	report.Monitors, err = c.SyncMonitorsContext(ctx, desired, opts...)
+	if err != nil {
+		return report, err
+	}
+	monitorIDs := map[string]MonitorID{}
+	for _, m := range report.Monitors.Created {
+		monitorIDs[m.URL] = m.ID
+	}
+	for _, u := range report.Monitors.Updated {
+		monitorIDs[u.Monitor.URL] = u.Monitor.ID
+	}
+	for _, m := range report.Monitors.Unchanged {
+		monitorIDs[m.URL] = m.ID
+	}
+	if err := c.restoreMWindows(ctx, s.MWindows, &report); err != nil {
+		return report, err
+	}
+	urls := map[MonitorID]string{}
+	for _, m := range s.Monitors {
+		urls[m.ID] = m.URL
+	}
+	if err := c.restorePSPs(ctx, s.PSPs, urls, monitorIDs, &report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// restoreAlertContacts creates those of the specified alert contacts which
+// don't already exist, and returns a map from each contact's ID in the
+// snapshot to its ID in this account.
+func (c *Client) restoreAlertContacts(ctx context.Context, contacts []AlertContact, report *RestoreReport) (map[ContactID]ContactID, error) {
+	existing, err := c.AllAlertContactsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	type key struct {
+		Type  int
+		Value string
+	}
+	byKey := map[key]ContactID{}
+	for _, a := range existing {
+		byKey[key{a.Type, a.Value}] = a.ID
+	}
+	IDs := map[ContactID]ContactID{}
+	for _, a := range contacts {
+		k := key{a.Type, a.Value}
+		if ID, ok := byKey[k]; ok {
+			IDs[a.ID] = ID
+			continue
+		}
+		ID, err := c.CreateAlertContactContext(ctx, a)
+		if err != nil {
+			return nil, fmt.Errorf("restoring alert contact %q: %w", a.FriendlyName, err)
+		}
+		byKey[k] = ID
+		IDs[a.ID] = ID
+		a.ID = ID
+		report.AlertContacts = append(report.AlertContacts, a)
+	}
+	return IDs, nil
+}
+
+// restoreMWindows creates those of the specified maintenance windows whose
+// names don't match an existing window.
+func (c *Client) restoreMWindows(ctx context.Context, windows []MWindow, report *RestoreReport) error {
+	existing, err := c.AllMWindowsContext(ctx)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, w := range existing {
+		names[w.FriendlyName] = true
+	}
+	for _, w := range windows {
+		if names[w.FriendlyName] {
+			continue
+		}
+		ID, err := c.CreateMWindowContext(ctx, w)
+		if err != nil {
+			return fmt.Errorf("restoring maintenance window %q: %w", w.FriendlyName, err)
+		}
+		names[w.FriendlyName] = true
+		w.ID = ID
+		report.MWindows = append(report.MWindows, w)
+	}
+	return nil
+}
+
+// restorePSPs creates those of the specified status pages whose names don't
+// match an existing page. Each page's monitors are translated from their IDs
+// in the snapshot to their IDs in this account, using their URLs.
+func (c *Client) restorePSPs(ctx context.Context, psps []PSP, urls map[MonitorID]string, monitorIDs map[string]MonitorID, report *RestoreReport) error {
+	existing, err := c.AllPSPsContext(ctx)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, p := range existing {
+		names[p.FriendlyName] = true
+	}
+	for _, p := range psps {
+		if names[p.FriendlyName] {
+			continue
+		}
+		monitors := make([]MonitorID, 0, len(p.Monitors))
+		for _, ID := range p.Monitors {
+			newID, ok := monitorIDs[urls[ID]]
+			if !ok {
+				return fmt.Errorf("restoring status page %q: monitor %d is not in the snapshot", p.FriendlyName, ID)
+			}
+			monitors = append(monitors, newID)
+		}
+		p.Monitors = monitors
+		ID, err := c.CreatePSPContext(ctx, p)
+		if err != nil {
+			return fmt.Errorf("restoring status page %q: %w", p.FriendlyName, err)
+		}
+		names[p.FriendlyName] = true
+		p.ID = ID
+		report.PSPs = append(report.PSPs, p)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package uptimerobot
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ResponseTimeStats summarises a monitor's response times over a window, for
+// example to report against a latency objective. Percentiles are calculated
+// using the nearest-rank method, so each is one of the measured values. If
+// there are no measurements in the window, Count is zero and so are all the
+// durations.
+type ResponseTimeStats struct {
+	Start   time.Time
+	End     time.Time
+	Count   int
+	Average time.Duration
+	Min     time.Duration
+	Max     time.Duration
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+}
+
+// String returns a one-line summary of the statistics.
+func (s ResponseTimeStats) String() string {
+	return fmt.Sprintf("count=%d avg=%s min=%s max=%s p50=%s p95=%s p99=%s", s.Count, s.Average, s.Min, s.Max, s.P50, s.P95, s.P99)
+}
+
+// NewResponseTimeStats calculates statistics for those of the specified
+// response times which fall in the window from start to end (inclusive). A
+// zero start or end leaves that side of the window unbounded.
+func NewResponseTimeStats(times []ResponseTime, start, end time.Time) ResponseTimeStats {
+	s := ResponseTimeStats{
+		Start: start,
+		End:   end,
+	}
+	values := []time.Duration{}
+	for _, t := range times {
+		if !start.IsZero() && t.Datetime.Before(start) {
+			continue
+		}
+		if !end.IsZero() && t.Datetime.After(end) {
+			continue
+		}
+		values = append(values, t.Value)
+	}
+	if len(values) == 0 {
+		return s
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i] < values[j]
+	})
+	var total time.Duration
+	for _, v := range values {
+		total += v
+	}
+	s.Count = len(values)
+	s.Average = total / time.Duration(len(values))
+	s.Min = values[0]
+	s.Max = values[len(values)-1]
+	s.P50 = percentile(values, 50)
+	s.P95 = percentile(values, 95)
+	s.P99 = percentile(values, 99)
+	return s
+}
+
+// percentile returns the pth percentile of the sorted, non-empty values,
+// using the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// ResponseTimeStats calculates statistics for the monitor's response times in
+// the window from start to end, as NewResponseTimeStats does. The monitor must
+// have been fetched with WithResponseTimes or WithResponseTimesSince.
+func (m Monitor) ResponseTimeStats(start, end time.Time) ResponseTimeStats {
+	return NewResponseTimeStats(m.ResponseTimes, start, end)
+}
+
+// GetResponseTimeStats fetches the response times of the monitor with the
+// specified ID since the specified time, and returns their statistics.
+func (c *Client) GetResponseTimeStats(monitorID MonitorID, since time.Time) (ResponseTimeStats, error) {
+	return c.GetResponseTimeStatsContext(context.Background(), monitorID, since)
+}
+
+// GetResponseTimeStatsContext is like GetResponseTimeStats, but uses the
+// specified context for its API request.
+func (c *Client) GetResponseTimeStatsContext(ctx context.Context, monitorID MonitorID, since time.Time) (ResponseTimeStats, error) {
+	m, err := c.GetMonitorContext(ctx, monitorID, WithResponseTimesSince(since))
+	if err != nil {
+		return ResponseTimeStats{}, err
+	}
+	return m.ResponseTimeStats(since, time.Now()), nil
+}
@@ -0,0 +1,110 @@
+package uptimerobot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// decodeResponse decodes an API response from dec into r. A response may
+// contain hundreds of monitors, each with its logs and response times, so the
+// monitors are decoded one at a time as they arrive, rather than buffering the
+// whole body first. The other fields are small, and are decoded as usual.
+func decodeResponse(dec *json.Decoder, r *Response) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	rest := map[string]json.RawMessage{}
+	var monitors []Monitor
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if strings.EqualFold(key, "monitors") {
+			monitors, err = decodeMonitors(dec)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		rest[key] = v
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	data, err := json.Marshal(rest)
+	if err != nil {
+		return err
+	}
+	keepRaw := r.keepRaw
+	if err := json.Unmarshal(data, r); err != nil {
+		return err
+	}
+	r.Monitors = monitors
+	r.keepRaw = keepRaw
+	return nil
+}
+
+// decodeMonitors decodes a JSON array of monitors from dec, one monitor at a
+// time. Null decodes as no monitors.
+func decodeMonitors(dec *json.Decoder) ([]Monitor, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, nil
+	}
+	if tok != json.Delim('[') {
+		return nil, fmt.Errorf("decoding monitors: want array, got %v", tok)
+	}
+	monitors := []Monitor{}
+	for dec.More() {
+		var m Monitor
+		if err := dec.Decode(&m); err != nil {
+			return nil, err
+		}
+		monitors = append(monitors, m)
+	}
+	return monitors, expectDelim(dec, ']')
+}
+
+// expectDelim reads the next token from dec, and returns an error unless it
+// is the specified delimiter.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("invalid response: want %v, got %v", want, tok)
+	}
+	return nil
+}
+
+// headBuffer is an io.Writer which keeps only the first max bytes written to
+// it, such as the start of a response body for an error message.
+type headBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+// Write keeps as much of p as fits in the buffer, and discards the rest.
+// It always reports success, so that it can be used with io.TeeReader.
+func (h *headBuffer) Write(p []byte) (int, error) {
+	if room := h.max - h.Len(); room > 0 {
+		if len(p) > room {
+			h.Buffer.Write(p[:room])
+		} else {
+			h.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,181 @@
+package uptimerobot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SyncOption represents a setting which can be passed to SyncMonitors.
+type SyncOption func(*syncConfig)
+
+type syncConfig struct {
+	prune bool
+	owner string
+}
+
+// WithPrune makes SyncMonitors delete any existing monitors which are not in
+// the desired set.
+func WithPrune() SyncOption {
+	return func(cfg *syncConfig) {
+		cfg.prune = true
+	}
+}
+
+// WithManagedBy makes SyncMonitors mark the monitors it creates or updates as
+// managed by the specified owner (for example, the name of the tool or
+// repository which manages them), by adding a marker to their friendly names,
+// such as "Example [managed-by:infra]". It then only ever changes or deletes
+// monitors which have the owner's marker, leaving hand-made monitors alone,
+// even with WithPrune. The marker is added for you, so the desired monitors
+// should have their plain names.
+func WithManagedBy(owner string) SyncOption {
+	return func(cfg *syncConfig) {
+		cfg.owner = owner
+	}
+}
+
+// managedMarker returns the marker which WithManagedBy adds to the friendly
+// names of monitors managed by owner.
+func managedMarker(owner string) string {
+	return " [managed-by:" + owner + "]"
+}
+
+// IsManagedBy reports whether the monitor's friendly name has the marker
+// added by SyncMonitors with the WithManagedBy option for the specified owner.
+func IsManagedBy(m Monitor, owner string) bool {
+	return strings.HasSuffix(m.FriendlyName, managedMarker(owner))
+}
+
+// MonitorUpdate describes an existing monitor which SyncMonitors changed, and
+// the changes it made.
+type MonitorUpdate struct {
+	Monitor Monitor
+	Changes []Change
+}
+
+// SyncReport describes the changes made by SyncMonitors. Created holds the
+// new monitors, with their IDs set; Updated the monitors which were edited;
+// Unchanged the monitors which already matched; and Deleted the monitors
+// which were removed because of WithPrune. With WithManagedBy, Unmanaged
+// holds the existing monitors which have the URL of a desired monitor, but
+// were left alone because they are not managed by the owner.
+//
+// If several existing monitors have the URL of a desired monitor, only one of
+// them is synced. Without WithPrune, the others are left alone and listed in
+// Duplicates; with it, they are deleted.
+type SyncReport struct {
+	Created    []Monitor
+	Updated    []MonitorUpdate
+	Unchanged  []Monitor
+	Deleted    []Monitor
+	Unmanaged  []Monitor
+	Duplicates []Monitor
+}
+
+// SyncMonitors makes the monitors in the account match the desired set.
+// Monitors are matched by URL: desired monitors which don't exist are created,
+// and existing monitors whose settings have drifted are updated, as for
+// EnsureMonitor. If the WithPrune option is given, existing monitors whose
+// URLs are not in the desired set are deleted, as are any duplicates of the
+// monitors which were synced. With the WithManagedBy option,
+// only monitors marked as managed by the specified owner are changed or
+// deleted.
+//
+// SyncMonitors returns a SyncReport describing what it did. If an operation
+// fails, it stops and returns the error, together with a report of the
+// changes made so far.
+func (c *Client) SyncMonitors(desired []Monitor, opts ...SyncOption) (SyncReport, error) {
+	return c.SyncMonitorsContext(context.Background(), desired, opts...)
+}
+
+// SyncMonitorsContext is like SyncMonitors, but uses the specified context for
+// its API requests.
+func (c *Client) SyncMonitorsContext(ctx context.Context, desired []Monitor, opts ...SyncOption) (SyncReport, error) {
+	return SyncMonitorsIn(ctx, c, desired, opts...)
+}
+
+// SyncMonitorsIn is like SyncMonitors, but makes the monitors in the specified
+// MonitorStore, such as a client for the v3 API, match the desired set.
+func SyncMonitorsIn(ctx context.Context, s MonitorStore, desired []Monitor, opts ...SyncOption) (SyncReport, error) {
+	cfg := syncConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	report := SyncReport{}
+	wanted := map[string]bool{}
+	for _, m := range desired {
+		if wanted[m.URL] {
+			return report, fmt.Errorf("URL %q appears more than once in the desired monitors", m.URL)
+		}
+		wanted[m.URL] = true
+	}
+	managed := func(m Monitor) bool {
+		return cfg.owner == "" || IsManagedBy(m, cfg.owner)
+	}
+	if cfg.owner != "" {
+		marked := make([]Monitor, len(desired))
+		for i, m := range desired {
+			if !IsManagedBy(m, cfg.owner) {
+				m.FriendlyName += managedMarker(cfg.owner)
+			}
+			marked[i] = m
+		}
+		desired = marked
+	}
+	monitors, err := s.AllMonitorsContext(ctx, WithAlertContacts())
+	if err != nil {
+		return report, err
+	}
+	existing := map[string]Monitor{}
+	for _, m := range monitors {
+		// Prefer a managed monitor to a hand-made one with the same URL
+		if current, ok := existing[m.URL]; !ok || (!managed(current) && managed(m)) {
+			existing[m.URL] = m
+		}
+	}
+	for _, m := range desired {
+		current, ok := existing[m.URL]
+		if !ok {
+			ID, err := s.CreateMonitorContext(ctx, m)
+			if err != nil {
+				return report, err
+			}
+			m.ID = ID
+			report.Created = append(report.Created, m)
+			continue
+		}
+		if !managed(current) {
+			report.Unmanaged = append(report.Unmanaged, current)
+			continue
+		}
+		p, changes, err := reconcileParams(current, m)
+		if err != nil {
+			return report, err
+		}
+		if len(changes) == 0 {
+			report.Unchanged = append(report.Unchanged, current)
+			continue
+		}
+		if _, err := s.EditMonitorContext(ctx, p); err != nil {
+			return report, err
+		}
+		report.Updated = append(report.Updated, MonitorUpdate{Monitor: current, Changes: changes})
+	}
+	for _, m := range monitors {
+		if !managed(m) || (wanted[m.URL] && existing[m.URL].ID == m.ID) {
+			continue
+		}
+		if !cfg.prune {
+			if wanted[m.URL] {
+				report.Duplicates = append(report.Duplicates, m)
+			}
+			continue
+		}
+		if err := s.DeleteMonitorContext(ctx, m.ID); err != nil {
+			return report, err
+		}
+		report.Deleted = append(report.Deleted, m)
+	}
+	return report, nil
+}
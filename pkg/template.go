@@ -0,0 +1,100 @@
+package uptimerobot
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ANSI escape sequences used by colorStatus.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiGrey   = "\x1b[90m"
+)
+
+// TemplateFuncs returns the helper functions available to the package
+// templates, for use in your own templates:
+//
+//   - duration formats a time.Duration concisely, for example "5m" or "1h30m"
+//   - percent formats a number as a percentage to two decimal places
+//   - localtime formats a time.Time in the local timezone
+//   - colorStatus formats a monitor status value as its name, coloured for
+//     display in a terminal
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"duration":    formatDuration,
+		"percent":     formatPercent,
+		"localtime":   formatLocalTime,
+		"colorStatus": colorStatus,
+	}
+}
+
+// render returns the result of executing the specified template, using the
+// functions in TemplateFuncs, with value as its data.
+func render(text string, value interface{}) (string, error) {
+	tmpl, err := template.New("").Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing template: %w", err)
+	}
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, value); err != nil {
+		return "", fmt.Errorf("executing template: %w", err)
+	}
+	return output.String(), nil
+}
+
+// renderString is like render, but returns a description of the error, if
+// any, in place of the output, for use by String methods, which can't return
+// errors.
+func renderString(text string, value interface{}) string {
+	s, err := render(text, value)
+	if err != nil {
+		return fmt.Sprintf("%%!(%v)", err)
+	}
+	return s
+}
+
+// formatDuration returns the duration formatted without any trailing zero
+// units, so that 5 minutes is "5m" rather than "5m0s".
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// formatPercent returns the value formatted as a percentage to two decimal
+// places.
+func formatPercent(v float64) string {
+	return fmt.Sprintf("%.2f%%", v)
+}
+
+// formatLocalTime returns the time formatted in the local timezone.
+func formatLocalTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// colorStatus returns the name of the specified monitor status, wrapped in
+// ANSI escape sequences to colour it for display in a terminal.
+func colorStatus(status Status) string {
+	name := status.String()
+	switch status {
+	case StatusUp:
+		return ansiGreen + name + ansiReset
+	case StatusMaybeDown:
+		return ansiYellow + name + ansiReset
+	case StatusDown:
+		return ansiRed + name + ansiReset
+	default:
+		return ansiGrey + name + ansiReset
+	}
+}
@@ -0,0 +1,225 @@
+package uptimerobot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MonitorType is the type of a monitor, such as TypeHTTP or TypeKeyword. Its
+// String method returns the type's name.
+type MonitorType int
+
+var monitorTypeNames = map[MonitorType]string{
+	TypeHTTP:      "HTTP",
+	TypeKeyword:   "Keyword",
+	TypePing:      "Ping",
+	TypePort:      "Port",
+	TypeHeartbeat: "Heartbeat",
+}
+
+// String returns the name of the monitor type, such as 'Keyword', or the
+// number if the type is unknown.
+func (t MonitorType) String() string {
+	if name, ok := monitorTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", int(t))
+}
+
+// ParseMonitorType returns the monitor type with the specified name, such as
+// 'http' or 'keyword', ignoring case.
+func ParseMonitorType(name string) (MonitorType, error) {
+	for t, n := range monitorTypeNames {
+		if strings.EqualFold(name, n) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown monitor type %q (want http, keyword, ping, port, or heartbeat)", name)
+}
+
+// MonitorSubType is the subtype of a port monitor, such as SubTypeHTTPS or
+// SubTypeCustomPort. Its String method returns the subtype's name.
+type MonitorSubType int
+
+var monitorSubTypeNames = map[MonitorSubType]string{
+	SubTypeHTTP:       "HTTP",
+	SubTypeHTTPS:      "HTTPS",
+	SubTypeFTP:        "FTP",
+	SubTypeSMTP:       "SMTP",
+	SubTypePOP3:       "POP3",
+	SubTypeIMAP:       "IMAP",
+	SubTypeCustomPort: "Custom",
+}
+
+// String returns the name of the subtype, such as 'HTTPS', or the number if
+// the subtype is unknown.
+func (t MonitorSubType) String() string {
+	if name, ok := monitorSubTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", int(t))
+}
+
+// ParseSubType returns the port monitor subtype with the specified name, such
+// as 'https', 'smtp', or 'custom', ignoring case.
+func ParseSubType(name string) (MonitorSubType, error) {
+	for t, n := range monitorSubTypeNames {
+		if strings.EqualFold(name, n) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown monitor subtype %q (want http, https, ftp, smtp, pop3, imap, or custom)", name)
+}
+
+// Status is the status of a monitor, such as StatusUp or StatusPaused, as
+// reported by the API. Its String method returns the status's name.
+type Status int
+
+var statusNames = map[Status]string{
+	StatusPaused:    "Paused",
+	StatusUnknown:   "Unknown",
+	StatusUp:        "Up",
+	StatusMaybeDown: "MaybeDown",
+	StatusDown:      "Down",
+}
+
+// String returns the name of the status, such as 'Up', or the number if the
+// status is unknown.
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", int(s))
+}
+
+// ParseStatus returns the monitor status with the specified name, such as
+// 'up', 'down', or 'maybedown', ignoring case.
+func ParseStatus(name string) (Status, error) {
+	for s, n := range statusNames {
+		if strings.EqualFold(name, n) {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown monitor status %q (want paused, unknown, up, maybedown, or down)", name)
+}
+
+// AlertContactType is the type of an alert contact, such as
+// AlertContactTypeEmail or AlertContactTypeSlack. Its String method returns
+// the type's name.
+type AlertContactType int
+
+var alertContactTypeNames = map[AlertContactType]string{
+	AlertContactTypeSMS:        "SMS",
+	AlertContactTypeEmail:      "Email",
+	AlertContactTypeTwitter:    "Twitter",
+	AlertContactTypeWebhook:    "Webhook",
+	AlertContactTypePushbullet: "Pushbullet",
+	AlertContactTypeZapier:     "Zapier",
+	AlertContactTypePushover:   "Pushover",
+	AlertContactTypeSlack:      "Slack",
+}
+
+// String returns the name of the alert contact type, such as 'Slack', or the
+// number if the type is unknown.
+func (t AlertContactType) String() string {
+	if name, ok := alertContactTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", int(t))
+}
+
+// ParseAlertContactType returns the alert contact type with the specified
+// name, such as 'email' or 'slack', ignoring case.
+func ParseAlertContactType(name string) (AlertContactType, error) {
+	for t, n := range alertContactTypeNames {
+		if strings.EqualFold(name, n) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown alert contact type %q (want sms, email, twitter, webhook, pushbullet, zapier, pushover, or slack)", name)
+}
+
+// ratioList is a list of uptime percentages, which the API encodes as a
+// dash-separated string, such as "100.000-99.981". A single value may also be
+// given as a number, and an empty string or null decodes as an empty list.
+type ratioList []float64
+
+// UnmarshalJSON decodes a ratioList from a JSON string or number.
+func (r *ratioList) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*r = nil
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	if s == "" {
+		*r = nil
+		return nil
+	}
+	parts := strings.Split(s, "-")
+	ratios := make(ratioList, len(parts))
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return fmt.Errorf("invalid list of uptime ratios %s", data)
+		}
+		ratios[i] = v
+	}
+	*r = ratios
+	return nil
+}
+
+// FlexInt is an integer which can be decoded from JSON given either as a
+// number, or as a quoted number, such as "80". An empty string or null decodes
+// as zero. The API uses all of these encodings for integer fields such as
+// port and sub_type, and may use any of them for any integer field, so every
+// integer field in the API's responses is decoded this way.
+type FlexInt int
+
+// UnmarshalJSON decodes a FlexInt from a JSON number or string.
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	v, err := parseFlexInt(data)
+	if err != nil {
+		return err
+	}
+	*i = FlexInt(v)
+	return nil
+}
+
+// parseFlexInt decodes an integer given in any of the encodings accepted by
+// FlexInt.
+func parseFlexInt(data []byte) (int64, error) {
+	s := string(data)
+	if s == "null" {
+		return 0, nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return 0, err
+		}
+	}
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %s", data)
+	}
+	return v, nil
+}
+
+// UnmarshalJSON decodes a Status in any of the encodings accepted by FlexInt.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	v, err := parseFlexInt(data)
+	if err != nil {
+		return err
+	}
+	*s = Status(v)
+	return nil
+}
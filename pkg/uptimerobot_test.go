@@ -1,27 +1,211 @@
 package uptimerobot
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gopkg.in/yaml.v3"
 )
 
+func TestNewWithOptions(t *testing.T) {
+	t.Parallel()
+	hc := &http.Client{Timeout: time.Second}
+	debug := &strings.Builder{}
+	client := New("dummy",
+		WithHTTPClient(hc),
+		WithTimeout(time.Minute),
+		WithBaseURL("https://example.com"),
+		WithRetries(5),
+		WithRequestInterval(6*time.Second),
+		WithDebugWriter(debug),
+	)
+	if client.HTTPClient.Timeout != time.Minute {
+		t.Errorf("want timeout %s, got %s", time.Minute, client.HTTPClient.Timeout)
+	}
+	if hc.Timeout != time.Second {
+		t.Errorf("want original HTTP client unchanged, got timeout %s", hc.Timeout)
+	}
+	if client.URL != "https://example.com" {
+		t.Errorf("want URL %q, got %q", "https://example.com", client.URL)
+	}
+	if client.MaxRetries != 5 {
+		t.Errorf("want 5 retries, got %d", client.MaxRetries)
+	}
+	if client.RequestInterval != 6*time.Second {
+		t.Errorf("want request interval %s, got %s", 6*time.Second, client.RequestInterval)
+	}
+	if client.Debug != debug {
+		t.Error("want debug writer set")
+	}
+}
+
+func TestTransportOptions(t *testing.T) {
+	t.Parallel()
+	hc := &http.Client{Transport: &http.Transport{}}
+	client := New("dummy",
+		WithHTTPClient(hc),
+		WithMaxIdleConns(20),
+		WithKeepAlive(time.Minute),
+	)
+	tr, ok := client.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("want *http.Transport, got %T", client.HTTPClient.Transport)
+	}
+	if tr.MaxIdleConnsPerHost != 20 {
+		t.Errorf("want 20 idle connections per host, got %d", tr.MaxIdleConnsPerHost)
+	}
+	if tr.IdleConnTimeout != time.Minute {
+		t.Errorf("want idle timeout %s, got %s", time.Minute, tr.IdleConnTimeout)
+	}
+	if tr.DisableKeepAlives {
+		t.Error("want keep-alives enabled")
+	}
+	if orig := hc.Transport.(*http.Transport); orig.MaxIdleConnsPerHost != 0 {
+		t.Errorf("want original transport unchanged, got %d idle connections per host", orig.MaxIdleConnsPerHost)
+	}
+	client = New("dummy", WithKeepAlive(0))
+	if !client.HTTPClient.Transport.(*http.Transport).DisableKeepAlives {
+		t.Error("want keep-alives disabled")
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	t.Parallel()
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "api.example.com" {
+			t.Errorf("want proxied request for api.example.com, got %q", r.URL.Host)
+		}
+		fmt.Fprint(w, `{"stat": "ok", "monitors": []}`)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := New("dummy",
+		WithBaseURL("http://api.example.com"),
+		WithProxy(proxyURL),
+	)
+	if _, err := client.SearchMonitors("example"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "monitors": []}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithBaseURL(ts.URL), WithRetries(0))
+	if _, err := client.SearchMonitors("example"); err == nil {
+		t.Error("want error for untrusted certificate, got nil")
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	client = New("dummy",
+		WithBaseURL(ts.URL),
+		WithTLSConfig(&tls.Config{RootCAs: pool}),
+	)
+	if _, err := client.SearchMonitors("example"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithTransportKeepsTimeout(t *testing.T) {
+	t.Parallel()
+	rt := &http.Transport{}
+	client := New("dummy", WithTimeout(time.Minute), WithTransport(rt))
+	if client.HTTPClient.Transport != rt {
+		t.Error("want transport set")
+	}
+	if client.HTTPClient.Timeout != time.Minute {
+		t.Errorf("want timeout %s, got %s", time.Minute, client.HTTPClient.Timeout)
+	}
+}
+
+func TestClientNegotiatesGzip(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("want gzip accepted, got Accept-Encoding %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		fmt.Fprint(gz, `{"stat": "ok", "monitors": [{"id": 1, "friendly_name": "Compressed"}]}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	monitors, err := client.SearchMonitors("Compressed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 1 || monitors[0].FriendlyName != "Compressed" {
+		t.Errorf("want compressed monitor, got %v", monitors)
+	}
+}
+
+func TestClientReusesConnections(t *testing.T) {
+	t.Parallel()
+	var conns int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 0}, "monitors": []}`)
+	}))
+	ts.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.StartTLS()
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetMonitorsPage(i*50, 50); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("want 1 connection for 3 requests, got %d", n)
+	}
+}
+
 func TestMarshalMonitor(t *testing.T) {
 	t.Parallel()
 	m := Monitor{
-		ID:            777749809,
-		FriendlyName:  "Google",
-		URL:           "http://www.google.com",
-		Type:          TypeHTTP,
-		Port:          80,
-		AlertContacts: []string{"3", "5", "7"},
+		ID:              777749809,
+		FriendlyName:    "Google",
+		URL:             "http://www.google.com",
+		Type:            TypeHTTP,
+		Port:            80,
+		AlertContacts:   []ContactID{"3", "5", "7"},
+		Interval:        5 * time.Minute,
+		Timeout:         30 * time.Second,
+		IgnoreSSLErrors: true,
 	}
 	got, err := json.MarshalIndent(m, "", "  ")
 	if err != nil {
@@ -57,6 +241,14 @@ func TestUnmarshalMonitor(t *testing.T) {
 		Type:         TypeHTTP,
 		Port:         80,
 		Status:       StatusUnknown,
+		Interval:     900 * time.Second,
+		Logs: []MonitorLog{
+			{
+				Type:     LogTypeStarted,
+				Datetime: time.Unix(1463540297, 0).UTC(),
+				Duration: 1054134 * time.Second,
+			},
+		},
 	}
 	data, err := ioutil.ReadFile("testdata/unmarshal.json")
 	if err != nil {
@@ -73,6 +265,38 @@ func TestUnmarshalMonitor(t *testing.T) {
 
 }
 
+func TestUnmarshalMonitorAlertContacts(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{
+		"id": 777749809,
+		"alert_contacts": [
+			{"id": "0993765", "value": "johndoe@gmail.com", "type": 2, "threshold": 0, "recurrence": 0},
+			{"id": 2403924, "value": "sampleTwitterAccount", "type": 3, "threshold": 0, "recurrence": 0}
+		]
+	}`)
+	got := Monitor{}
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	want := []ContactID{"0993765", "2403924"}
+	if !cmp.Equal(want, got.AlertContacts) {
+		t.Error(cmp.Diff(want, got.AlertContacts))
+	}
+}
+
+func TestUnmarshalMonitorIgnoreSSLErrors(t *testing.T) {
+	t.Parallel()
+	for _, value := range []string{`1`, `"1"`} {
+		got := Monitor{}
+		if err := got.UnmarshalJSON([]byte(`{"ignore_ssl_errors": ` + value + `}`)); err != nil {
+			t.Fatal(err)
+		}
+		if !got.IgnoreSSLErrors {
+			t.Errorf("ignore_ssl_errors %s: want IgnoreSSLErrors true, got false", value)
+		}
+	}
+}
+
 func TestCreate(t *testing.T) {
 	t.Parallel()
 	client := New("dummy")
@@ -126,18 +350,136 @@ func TestCreate(t *testing.T) {
 		URL:           "http://example.com",
 		Type:          TypeHTTP,
 		Port:          80,
-		AlertContacts: []string{"3", "5", "7"},
+		AlertContacts: []ContactID{"3", "5", "7"},
 	}
 	got, err := client.CreateMonitor(create)
 	if err != nil {
 		t.Error(err)
 	}
-	var want int64 = 777810874
+	var want MonitorID = 777810874
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestCreateMonitorWithParams(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"api_key":        "dummy",
+			"format":         "json",
+			"friendly_name":  "My test monitor",
+			"url":            "http://example.com",
+			"type":           float64(TypeHTTP),
+			"interval":       float64(300),
+			"alert_contacts": "3_0_0",
+		}
+		if !cmp.Equal(want, bodyMap) {
+			t.Error(cmp.Diff(want, bodyMap))
+		}
+		data, err := os.Open("testdata/newMonitor.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.CreateMonitorWithParams(CreateMonitorParams{
+		FriendlyName:  "My test monitor",
+		URL:           "http://example.com",
+		Type:          TypeHTTP,
+		Interval:      Duration(5 * time.Minute),
+		AlertContacts: []ContactID{"3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want MonitorID = 777810874
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestEditMonitorSendsOnlySetFields(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"api_key":           "dummy",
+			"format":            "json",
+			"id":                "677810870",
+			"port":              float64(0),
+			"ignore_ssl_errors": float64(0),
+			"alert_contacts":    "",
+		}
+		if !cmp.Equal(want, bodyMap) {
+			t.Error(cmp.Diff(want, bodyMap))
+		}
+		data, err := os.Open("testdata/pauseMonitor.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.EditMonitor(EditMonitorParams{
+		ID:              677810870,
+		Port:            Int(0),
+		IgnoreSSLErrors: Bool(false),
+		AlertContacts:   &[]ContactID{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Monitor{ID: 677810870}
 	if !cmp.Equal(want, got) {
 		t.Error(cmp.Diff(want, got))
 	}
 }
 
+func TestUserAgent(t *testing.T) {
+	t.Parallel()
+	want := "uptimerobot-go/" + Version + " mytool/1.2"
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get("User-Agent")
+		if want != got {
+			t.Errorf("want User-Agent %q, got %q", want, got)
+		}
+		data, err := os.Open("testdata/getAccountDetails.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client := New("dummy",
+		WithHTTPClient(ts.Client()),
+		WithBaseURL(ts.URL),
+		WithUserAgent("mytool/1.2"),
+	)
+	if _, err := client.GetAccountDetails(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestGetAccountDetails(t *testing.T) {
 	t.Parallel()
 	client := New("dummy")
@@ -152,7 +494,7 @@ func TestGetAccountDetails(t *testing.T) {
 	want := Account{
 		Email:           "test@domain.com",
 		MonitorLimit:    50,
-		MonitorInterval: 1,
+		MonitorInterval: time.Minute,
 		UpMonitors:      1,
 		DownMonitors:    0,
 		PausedMonitors:  2,
@@ -194,399 +536,4859 @@ func TestAllAlertContacts(t *testing.T) {
 	}
 }
 
-func TestGetMonitorByID(t *testing.T) {
+func TestGetAlertContact(t *testing.T) {
 	t.Parallel()
 	client := New("dummy")
-	ts := cannedResponseServer(t, "testdata/getMonitorByID.json")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		if bodyMap["alert_contacts"] != "0993765" {
+			t.Errorf("want alert_contacts %q, got %q", "0993765", bodyMap["alert_contacts"])
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{
+			"stat": "ok",
+			"offset": 0,
+			"limit": 50,
+			"total": 1,
+			"alert_contacts": [{"id": "0993765", "friendly_name": "John Doe", "type": 2, "status": 1, "value": "johndoe@gmail.com"}]
+		}`)
+	}))
 	defer ts.Close()
 	client.HTTPClient = ts.Client()
 	client.URL = ts.URL
-	want := Monitor{
-		ID:           777749809,
-		FriendlyName: "Google",
-		URL:          "http://www.google.com",
-		Type:         TypeHTTP,
-		Port:         80,
-		Status:       StatusUnknown,
+	want := AlertContact{
+		ID:           "0993765",
+		FriendlyName: "John Doe",
+		Type:         2,
+		Status:       1,
+		Value:        "johndoe@gmail.com",
 	}
-	got, err := client.GetMonitor(want.ID)
+	got, err := client.GetAlertContact("0993765")
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 	if !cmp.Equal(want, got) {
 		t.Error(cmp.Diff(want, got))
 	}
 }
 
-func TestGetMonitorsPages(t *testing.T) {
+func TestGetAlertContactReturnsNotFoundError(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "offset": 0, "limit": 50, "total": 0, "alert_contacts": []}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	_, err := client.GetAlertContact("9999")
+	want := NotFoundError{Resource: "alert contact", ID: "9999"}
+	var got NotFoundError
+	if !errors.As(err, &got) {
+		t.Fatalf("want NotFoundError, got %v", err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestAllAlertContactsPages(t *testing.T) {
 	t.Parallel()
 	client := New("dummy")
+	const total = 60
 	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		bodyMap := map[string]interface{}{}
+		bodyMap := map[string]string{}
 		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
 			t.Fatal(err)
 		}
-		var datafile string
-		switch bodyMap["offset"] {
-		case "0":
-			datafile = "testdata/getMonitorsPage1.json"
-		case "50":
-			datafile = "testdata/getMonitorsPage2.json"
-		default:
-			t.Fatalf("unexpected offset %s", bodyMap["offset"])
+		offset, err := strconv.Atoi(bodyMap["offset"])
+		if err != nil {
+			t.Fatal(err)
 		}
-		data, err := os.Open(datafile)
+		limit, err := strconv.Atoi(bodyMap["limit"])
 		if err != nil {
 			t.Fatal(err)
 		}
+		contacts := []AlertContact{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			contacts = append(contacts, AlertContact{
+				ID: ContactID(strconv.Itoa(i + 1)),
+			})
+		}
+		resp := map[string]interface{}{
+			"stat":           "ok",
+			"offset":         offset,
+			"limit":          limit,
+			"total":          total,
+			"alert_contacts": contacts,
+		}
 		w.WriteHeader(http.StatusOK)
-		defer data.Close()
-		io.Copy(w, data)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatal(err)
+		}
 	}))
 	defer ts.Close()
 	client.HTTPClient = ts.Client()
 	client.URL = ts.URL
-	monitors, err := client.AllMonitors()
+	contacts, err := client.AllAlertContacts()
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if len(monitors) != 100 {
-		t.Fatalf("Wanted 100 monitors, but got %d", len(monitors))
+	if len(contacts) != total {
+		t.Fatalf("Wanted %d contacts, but got %d", total, len(contacts))
 	}
-	for i, m := range monitors {
-		want := fmt.Sprintf("monitor-%d", i+1)
-		got := m.FriendlyName
-		if !cmp.Equal(want, got) {
-			t.Error(cmp.Diff(want, got))
+	for i, c := range contacts {
+		want := ContactID(strconv.Itoa(i + 1))
+		if !cmp.Equal(want, c.ID) {
+			t.Error(cmp.Diff(want, c.ID))
 		}
 	}
+	count, err := client.AlertContactCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != total {
+		t.Errorf("want count %d, got %d", total, count)
+	}
 }
 
-func TestGetMonitors(t *testing.T) {
+func TestAlertContactByName(t *testing.T) {
 	t.Parallel()
 	client := New("dummy")
-	ts := cannedResponseServer(t, "testdata/getMonitors.json")
+	calls := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		data, err := os.Open("testdata/getAlertContacts.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
 	defer ts.Close()
 	client.HTTPClient = ts.Client()
 	client.URL = ts.URL
-	want := []Monitor{
-		{
-			ID:           777749809,
-			FriendlyName: "Google",
-			URL:          "http://www.google.com",
-			Type:         TypeHTTP,
-			Port:         80,
-			Status:       StatusUnknown,
-		},
-		{
-			ID:           777712827,
-			FriendlyName: "My Web Page",
-			URL:          "http://mywebpage.com/",
-			Type:         TypeHTTP,
-			Status:       StatusUp,
-		},
-		{
-			ID:           777559666,
-			FriendlyName: "My FTP Server",
-			URL:          "ftp.mywebpage.com",
-			Type:         TypePort,
-			SubType:      SubTypeFTP,
-			Port:         21,
-			Status:       StatusUp,
-		},
-		{
-			ID:           781397847,
-			FriendlyName: "PortTest",
+	for name, wantID := range map[string]ContactID{
+		"John Doe":   "0993765",
+		"My Twitter": "2403924",
+	} {
+		got, err := client.AlertContactByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(wantID, got.ID) {
+			t.Error(cmp.Diff(wantID, got.ID))
+		}
+	}
+	if _, err := client.AlertContactByName("Nobody"); err == nil {
+		t.Error("want error for unknown contact name, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("want contacts fetched once, got %d API calls", calls)
+	}
+}
+
+func TestCreateAlertContact(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantURL := "/v2/newAlertContact"
+		if r.URL.EscapedPath() != wantURL {
+			t.Errorf("want %q, got %q", wantURL, r.URL.EscapedPath())
+		}
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"api_key":       "dummy",
+			"format":        "json",
+			"type":          float64(AlertContactTypeEmail),
+			"value":         "ops@example.com",
+			"friendly_name": "Ops",
+		}
+		if !cmp.Equal(want, bodyMap) {
+			t.Error(cmp.Diff(want, bodyMap))
+		}
+		data, err := os.Open("testdata/newAlertContact.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.CreateAlertContact(AlertContact{
+		FriendlyName: "Ops",
+		Type:         AlertContactTypeEmail,
+		Value:        "ops@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ContactID("4561")
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestCreateAlertContactValidatesValue(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name    string
+		contact AlertContact
+		wantErr bool
+	}{
+		{
+			name:    "valid email",
+			contact: AlertContact{Type: AlertContactTypeEmail, Value: "ops@example.com"},
+		},
+		{
+			name:    "email without domain",
+			contact: AlertContact{Type: AlertContactTypeEmail, Value: "ops@"},
+			wantErr: true,
+		},
+		{
+			name:    "email with display name",
+			contact: AlertContact{Type: AlertContactTypeEmail, Value: "Ops <ops@example.com>"},
+			wantErr: true,
+		},
+		{
+			name:    "valid webhook",
+			contact: AlertContact{Type: AlertContactTypeWebhook, Value: "https://example.com/hook?"},
+		},
+		{
+			name:    "webhook without scheme",
+			contact: AlertContact{Type: AlertContactTypeWebhook, Value: "example.com/hook"},
+			wantErr: true,
+		},
+		{
+			name:    "Slack URL with bad scheme",
+			contact: AlertContact{Type: AlertContactTypeSlack, Value: "htps://hooks.slack.com/x"},
+			wantErr: true,
+		},
+		{
+			name:    "valid phone number",
+			contact: AlertContact{Type: AlertContactTypeSMS, Value: "+447700900123"},
+		},
+		{
+			name:    "phone number with letters",
+			contact: AlertContact{Type: AlertContactTypeSMS, Value: "+44770090O123"},
+			wantErr: true,
+		},
+		{
+			name:    "unchecked type",
+			contact: AlertContact{Type: AlertContactTypePushover, Value: "anything"},
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.contact.validate()
+			if tc.wantErr != (err != nil) {
+				t.Errorf("want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAddAndRemoveAlertContact(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name         string
+		remove       bool
+		contactID    ContactID
+		wantContacts interface{}
+	}{
+		{
+			name:         "Add new contact",
+			contactID:    "7",
+			wantContacts: "3_0_0-5_10_30-7_0_0",
+		},
+		{
+			name:         "Add existing contact",
+			contactID:    "5",
+			wantContacts: nil,
+		},
+		{
+			name:         "Remove existing contact",
+			remove:       true,
+			contactID:    "3",
+			wantContacts: "5_10_30",
+		},
+		{
+			name:         "Remove missing contact",
+			remove:       true,
+			contactID:    "7",
+			wantContacts: nil,
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var gotContacts interface{}
+			client := New("dummy")
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				bodyMap := map[string]interface{}{}
+				if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+					t.Fatal(err)
+				}
+				w.WriteHeader(http.StatusOK)
+				switch r.URL.Path {
+				case "/v2/getMonitors":
+					fmt.Fprint(w, `{
+						"stat": "ok",
+						"pagination": {"offset": 0, "limit": 50, "total": 1},
+						"monitors": [{"id": 777749809, "alert_contacts": [{"id": "3"}, {"id": "5", "threshold": 10, "recurrence": 30}]}]
+					}`)
+				case "/v2/editMonitor":
+					gotContacts = bodyMap["alert_contacts"]
+					fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 777749809}}`)
+				default:
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+			}))
+			defer ts.Close()
+			client.HTTPClient = ts.Client()
+			client.URL = ts.URL
+			var err error
+			if tc.remove {
+				err = client.RemoveAlertContactFromMonitor(777749809, tc.contactID)
+			} else {
+				err = client.AddAlertContactToMonitor(777749809, tc.contactID)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.wantContacts, gotContacts) {
+				t.Error(cmp.Diff(tc.wantContacts, gotContacts))
+			}
+		})
+	}
+}
+
+func TestAddAlertContactBySearch(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	edited := []interface{}{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Path {
+		case "/v2/getMonitors":
+			if bodyMap["search"] != "example.com" || bodyMap["alert_contacts"] != "1" {
+				t.Errorf("unexpected search request %v", bodyMap)
+			}
+			fmt.Fprint(w, `{
+				"stat": "ok",
+				"pagination": {"offset": 0, "limit": 50, "total": 2},
+				"monitors": [
+					{"id": 1, "alert_contacts": [{"id": "3"}]},
+					{"id": 2, "alert_contacts": [{"id": "3"}, {"id": "7"}]}
+				]
+			}`)
+		case "/v2/editMonitor":
+			edited = append(edited, bodyMap["id"])
+			fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 1}}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.AddAlertContactBySearch("example.com", "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MonitorID{1}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	wantEdited := []interface{}{"1"}
+	if !cmp.Equal(wantEdited, edited) {
+		t.Error(cmp.Diff(wantEdited, edited))
+	}
+}
+
+func TestContextCancelsRequest(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/getMonitors.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.AllMonitorsContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled error, got %v", err)
+	}
+}
+
+func TestAddAlertContactBySearchStopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	client.RequestInterval = time.Hour
+	ts := cannedResponseServer(t, "testdata/getMonitors.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.AddAlertContactBySearchContext(ctx, "example.com", "7")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want context.DeadlineExceeded error, got %v", err)
+	}
+}
+
+func TestRetriesRateLimitedRequest(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	calls := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 777810874}}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	if err := client.DeleteMonitor(777810874); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("want 2 requests, got %d", calls)
+	}
+}
+
+func TestRateLimitErrorWhenRetriesExhausted(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	client.MaxRetries = 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"stat": "fail", "error": {"type": "rate_limit", "message": "Rate limit exceeded"}}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	err := client.DeleteMonitor(777810874)
+	want := RateLimitError{RetryAfter: 30 * time.Second}
+	var got RateLimitError
+	if !errors.As(err, &got) {
+		t.Fatalf("want RateLimitError, got %v", err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestLoggerRecordsRequests(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Path == "/v2/deleteMonitor" {
+			fmt.Fprint(w, `{"stat": "fail", "error": {"type": "invalid_parameter", "message": "monitor not found"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 777810874}}`)
+	}))
+	defer ts.Close()
+	buf := &strings.Builder{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := New("dummy",
+		WithHTTPClient(ts.Client()),
+		WithBaseURL(ts.URL),
+		WithLogger(logger),
+	)
+	if _, err := client.PauseMonitor(Monitor{ID: 777810874}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteMonitor(777810874); err == nil {
+		t.Fatal("want error from failed API call, got nil")
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 log records, got %d: %q", len(lines), buf.String())
+	}
+	want := []map[string]interface{}{
+		{"level": "DEBUG", "msg": "uptimerobot API request", "verb": "editMonitor", "attempt": 0.0, "status": 200.0},
+		{"level": "DEBUG", "msg": "uptimerobot API request failed", "verb": "deleteMonitor", "attempt": 0.0, "status": 200.0, "error_type": "invalid_parameter"},
+	}
+	for i, line := range lines {
+		got := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := got["duration"]; !ok {
+			t.Errorf("record %d: want duration, got none", i)
+		}
+		for k, v := range want[i] {
+			if !cmp.Equal(v, got[k]) {
+				t.Errorf("record %d: want %s %v, got %v", i, k, v, got[k])
+			}
+		}
+	}
+}
+
+// recordingHook is a Hook which records the events of each call.
+type recordingHook struct {
+	events []string
+}
+
+func (h *recordingHook) StartCall(ctx context.Context, verb string) (context.Context, CallHook) {
+	h.events = append(h.events, "start "+verb)
+	return ctx, h
+}
+
+func (h *recordingHook) Attempt(a Attempt) {
+	h.events = append(h.events, fmt.Sprintf("attempt %s %d %d %s", a.Verb, a.Number, a.Status, a.ErrorType))
+}
+
+func (h *recordingHook) Retry(wait time.Duration) {
+	h.events = append(h.events, "retry "+wait.String())
+}
+
+func (h *recordingHook) End(err error) {
+	h.events = append(h.events, fmt.Sprintf("end %t", err != nil))
+}
+
+func TestHooksObserveEachAttempt(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"stat": "fail", "error": {"type": "not_found", "message": "monitor not found"}}`)
+	}))
+	defer ts.Close()
+	h := &recordingHook{}
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL), WithHook(h))
+	if err := client.DeleteMonitor(777810874); err == nil {
+		t.Fatal("want error from failed API call, got nil")
+	}
+	want := []string{
+		"start deleteMonitor",
+		"attempt deleteMonitor 0 429 rate_limit",
+		"retry 1s",
+		"attempt deleteMonitor 1 200 not_found",
+		"end true",
+	}
+	if !cmp.Equal(want, h.events) {
+		t.Error(cmp.Diff(want, h.events))
+	}
+}
+
+func TestDryRunRecordsMutatingRequests(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/getAccountDetails" {
+			t.Errorf("unexpected request to %s in dry-run mode", r.URL.Path)
+		}
+		data, err := os.Open("testdata/getAccountDetails.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL), WithDryRun())
+	if _, err := client.GetAccountDetails(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.PauseMonitor(Monitor{ID: 777810874}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteMonitor(777810874); err != nil {
+		t.Fatal(err)
+	}
+	want := []PlannedRequest{
+		{Verb: "editMonitor", Body: json.RawMessage(`{"id":"777810874","status":0}`)},
+		{Verb: "deleteMonitor", Body: json.RawMessage(`{"id":"777810874"}`)},
+	}
+	got := client.PlannedRequests()
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestReadOnlyRefusesMutatingRequests(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/getAccountDetails" {
+			t.Errorf("unexpected request to %s in read-only mode", r.URL.Path)
+		}
+		data, err := os.Open("testdata/getAccountDetails.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL), WithReadOnly())
+	if _, err := client.GetAccountDetails(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.PauseMonitor(Monitor{ID: 777810874})
+	want := ReadOnlyError{Verb: "editMonitor"}
+	var got ReadOnlyError
+	if !errors.As(err, &got) {
+		t.Fatalf("want ReadOnlyError, got %v", err)
+	}
+	if want != got {
+		t.Errorf("want %#v, got %#v", want, got)
+	}
+	if err := client.DeleteMWindow(582); !errors.As(err, &got) {
+		t.Errorf("want ReadOnlyError, got %v", err)
+	}
+}
+
+func TestBatchRunsAllOperations(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	deleted := map[string]bool{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		ID, _ := bodyMap["id"].(string)
+		if ID == "3" {
+			fmt.Fprint(w, `{"stat": "fail", "error": {"type": "not_found", "message": "monitor not found"}}`)
+			return
+		}
+		mu.Lock()
+		deleted[ID] = true
+		mu.Unlock()
+		fmt.Fprintf(w, `{"stat": "ok", "monitor": {"id": %s}}`, ID)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	ops := []BatchOp{}
+	for ID := MonitorID(1); ID <= 5; ID++ {
+		ID := ID
+		ops = append(ops, func(ctx context.Context, c *Client) error {
+			return c.DeleteMonitorContext(ctx, ID)
+		})
+	}
+	errs := client.Batch(2, ops...)
+	if len(errs) != 5 {
+		t.Fatalf("want 5 results, got %d", len(errs))
+	}
+	for i, err := range errs {
+		if i == 2 {
+			if err == nil {
+				t.Error("want error for monitor 3, got nil")
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("monitor %d: %v", i+1, err)
+		}
+	}
+	want := map[string]bool{"1": true, "2": true, "4": true, "5": true}
+	if !cmp.Equal(want, deleted) {
+		t.Error(cmp.Diff(want, deleted))
+	}
+}
+
+func TestBatchLimitsConcurrency(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	ops := []BatchOp{}
+	for i := 0; i < 10; i++ {
+		ops = append(ops, func(ctx context.Context, c *Client) error {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		})
+	}
+	client.Batch(3, ops...)
+	if maxRunning > 3 {
+		t.Errorf("want at most 3 concurrent operations, got %d", maxRunning)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	existing := Monitor{
+		ID:            777749809,
+		FriendlyName:  "Example",
+		URL:           "https://example.com/",
+		Type:          TypeKeyword,
+		KeywordType:   KeywordExists,
+		KeywordValue:  "Welcome",
+		AlertContacts: []ContactID{"2", "1"},
+		Status:        StatusUp,
+		Interval:      5 * time.Minute,
+	}
+	desired := existing
+	desired.ID = 0
+	desired.Status = 0
+	desired.AlertContacts = []ContactID{"1", "2"}
+	if got := Diff(existing, desired); len(got) != 0 {
+		t.Errorf("want no changes, got %v", got)
+	}
+	desired.FriendlyName = "Example.com website"
+	desired.KeywordValue = "Hello"
+	desired.AlertContacts = []ContactID{"1", "3"}
+	desired.Interval = time.Minute
+	want := []Change{
+		{Field: "FriendlyName", Old: "Example", New: "Example.com website"},
+		{Field: "KeywordValue", Old: "Welcome", New: "Hello"},
+		{Field: "AlertContacts", Old: []ContactID{"2", "1"}, New: []ContactID{"1", "3"}},
+		{Field: "Interval", Old: 5 * time.Minute, New: time.Minute},
+	}
+	got := Diff(existing, desired)
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	wantString := `FriendlyName: "Example" -> "Example.com website"`
+	if got[0].String() != wantString {
+		t.Errorf("want %q, got %q", wantString, got[0].String())
+	}
+}
+
+func TestParseMonitorType(t *testing.T) {
+	t.Parallel()
+	tcs := map[string]MonitorType{
+		"http":      TypeHTTP,
+		"Keyword":   TypeKeyword,
+		"PING":      TypePing,
+		"port":      TypePort,
+		"heartbeat": TypeHeartbeat,
+	}
+	for name, want := range tcs {
+		got, err := ParseMonitorType(name)
+		if err != nil {
+			t.Errorf("%q: %v", name, err)
+			continue
+		}
+		if want != got {
+			t.Errorf("%q: want %d, got %d", name, want, got)
+		}
+		if !strings.EqualFold(name, got.String()) {
+			t.Errorf("%q: want String %q, got %q", name, name, got.String())
+		}
+	}
+	if _, err := ParseMonitorType("bogus"); err == nil {
+		t.Error("want error for unknown type, got nil")
+	}
+	if got := MonitorType(42).String(); got != "42" {
+		t.Errorf("want unknown type to stringify as %q, got %q", "42", got)
+	}
+}
+
+func TestParseSubType(t *testing.T) {
+	t.Parallel()
+	tcs := map[string]MonitorSubType{
+		"http":   SubTypeHTTP,
+		"HTTPS":  SubTypeHTTPS,
+		"ftp":    SubTypeFTP,
+		"smtp":   SubTypeSMTP,
+		"pop3":   SubTypePOP3,
+		"Imap":   SubTypeIMAP,
+		"custom": SubTypeCustomPort,
+	}
+	for name, want := range tcs {
+		got, err := ParseSubType(name)
+		if err != nil {
+			t.Errorf("%q: %v", name, err)
+			continue
+		}
+		if want != got {
+			t.Errorf("%q: want %d, got %d", name, want, got)
+		}
+		if !strings.EqualFold(name, got.String()) {
+			t.Errorf("%q: want String %q, got %q", name, name, got.String())
+		}
+	}
+	if _, err := ParseSubType("gopher"); err == nil {
+		t.Error("want error for unknown subtype, got nil")
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	t.Parallel()
+	tcs := map[string]Status{
+		"paused":    StatusPaused,
+		"Unknown":   StatusUnknown,
+		"UP":        StatusUp,
+		"maybedown": StatusMaybeDown,
+		"down":      StatusDown,
+	}
+	for name, want := range tcs {
+		got, err := ParseStatus(name)
+		if err != nil {
+			t.Errorf("%q: %v", name, err)
+			continue
+		}
+		if want != got {
+			t.Errorf("%q: want %d, got %d", name, want, got)
+		}
+		if !strings.EqualFold(name, got.String()) {
+			t.Errorf("%q: want String %q, got %q", name, name, got.String())
+		}
+	}
+	if _, err := ParseStatus("sideways"); err == nil {
+		t.Error("want error for unknown status, got nil")
+	}
+}
+
+func TestFlexInt(t *testing.T) {
+	t.Parallel()
+	tcs := map[string]FlexInt{
+		`80`:   80,
+		`"80"`: 80,
+		`""`:   0,
+		`null`: 0,
+		`0`:    0,
+		`"-1"`: -1,
+	}
+	for input, want := range tcs {
+		var got FlexInt
+		if err := json.Unmarshal([]byte(input), &got); err != nil {
+			t.Errorf("%s: %v", input, err)
+			continue
+		}
+		if want != got {
+			t.Errorf("%s: want %d, got %d", input, want, got)
+		}
+	}
+	for _, input := range []string{`"eighty"`, `80.5`, `true`} {
+		var got FlexInt
+		if err := json.Unmarshal([]byte(input), &got); err == nil {
+			t.Errorf("%s: want error, got %d", input, got)
+		}
+	}
+}
+
+func TestParseAlertContactType(t *testing.T) {
+	t.Parallel()
+	tcs := map[string]AlertContactType{
+		"sms":        AlertContactTypeSMS,
+		"Email":      AlertContactTypeEmail,
+		"twitter":    AlertContactTypeTwitter,
+		"WEBHOOK":    AlertContactTypeWebhook,
+		"pushbullet": AlertContactTypePushbullet,
+		"zapier":     AlertContactTypeZapier,
+		"pushover":   AlertContactTypePushover,
+		"slack":      AlertContactTypeSlack,
+	}
+	for name, want := range tcs {
+		got, err := ParseAlertContactType(name)
+		if err != nil {
+			t.Errorf("%q: %v", name, err)
+			continue
+		}
+		if want != got {
+			t.Errorf("%q: want %d, got %d", name, want, got)
+		}
+		if !strings.EqualFold(name, got.String()) {
+			t.Errorf("%q: want String %q, got %q", name, name, got.String())
+		}
+	}
+	if _, err := ParseAlertContactType("carrier pigeon"); err == nil {
+		t.Error("want error for unknown type, got nil")
+	}
+	a := AlertContact{Type: AlertContactTypeSlack}
+	if a.FriendlyType() != "Slack" {
+		t.Errorf("want FriendlyType %q, got %q", "Slack", a.FriendlyType())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	tcs := []struct {
+		header string
+		want   time.Duration
+	}{
+		{header: "", want: 0},
+		{header: "120", want: 2 * time.Minute},
+		{header: "Wed, 01 Jan 2020 12:00:30 GMT", want: 30 * time.Second},
+		{header: "Wed, 01 Jan 2020 11:00:00 GMT", want: 0},
+		{header: "soon", want: 0},
+	}
+	for _, tc := range tcs {
+		got := parseRetryAfter(tc.header, now)
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("%q: %s", tc.header, cmp.Diff(tc.want, got))
+		}
+	}
+}
+
+func TestPacerSpacesRequests(t *testing.T) {
+	t.Parallel()
+	p := &pacer{}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := p.wait(context.Background(), 50*time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("want at least 100ms for three paced requests, got %s", elapsed)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	t.Parallel()
+	// 1200 requests per minute is one every 50ms
+	b := NewTokenBucket(1200, 2)
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+		t.Errorf("want burst of two requests without waiting, took %s", elapsed)
+	}
+	for i := 0; i < 2; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("want at least 100ms for two requests after the burst, got %s", elapsed)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+	if err := NewTokenBucket(0, 1).Wait(ctx); err != nil {
+		t.Errorf("want no limit for zero rate, got %v", err)
+	}
+}
+
+// countingLimiter is a RateLimiter which counts its calls, and returns err.
+type countingLimiter struct {
+	calls int32
+	err   error
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.calls, 1)
+	return l.err
+}
+
+func TestClientConsultsRateLimiter(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprint(w, `{"stat": "ok", "monitors": []}`)
+	}))
+	defer ts.Close()
+	l := &countingLimiter{}
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL), WithRateLimiter(l))
+	if _, err := client.AllMonitors(); err != nil {
+		t.Fatal(err)
+	}
+	if l.calls != 1 || requests != 1 {
+		t.Errorf("want one limiter call and one request, got %d and %d", l.calls, requests)
+	}
+	l.err = errors.New("over budget")
+	if _, err := client.AllMonitors(); !errors.Is(err, l.err) {
+		t.Errorf("want limiter's error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("want no request when the limiter refuses, got %d requests", requests)
+	}
+}
+
+func TestGetMonitorByID(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/getMonitorByID.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	want := Monitor{
+		ID:           777749809,
+		FriendlyName: "Google",
+		URL:          "http://www.google.com",
+		Type:         TypeHTTP,
+		Port:         80,
+		Status:       StatusUnknown,
+		Interval:     900 * time.Second,
+		Logs: []MonitorLog{
+			{
+				Type:     LogTypeStarted,
+				Datetime: time.Unix(1463540297, 0).UTC(),
+				Duration: 1054134 * time.Second,
+			},
+		},
+	}
+	got, err := client.GetMonitor(want.ID)
+	if err != nil {
+		t.Error(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestGetMonitorRoundTripsAlertContacts(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		if bodyMap["alert_contacts"] != "1" {
+			t.Errorf("want alert_contacts %q, got %q", "1", bodyMap["alert_contacts"])
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{
+			"stat": "ok",
+			"pagination": {"offset": 0, "limit": 50, "total": 1},
+			"monitors": [{
+				"id": 777749809,
+				"alert_contacts": [
+					{"id": "0993765", "value": "johndoe@gmail.com", "type": 2, "threshold": 0, "recurrence": 0},
+					{"id": "2403924", "value": "sampleTwitterAccount", "type": 3, "threshold": 0, "recurrence": 0}
+				]
+			}]
+		}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	m, err := client.GetMonitor(777749809)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotMap := map[string]interface{}{}
+	if err := json.Unmarshal(data, &gotMap); err != nil {
+		t.Fatal(err)
+	}
+	want := "0993765_0_0-2403924_0_0"
+	if !cmp.Equal(want, gotMap["alert_contacts"]) {
+		t.Error(cmp.Diff(want, gotMap["alert_contacts"]))
+	}
+}
+
+func TestGetMonitorsByIDs(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	requests := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := "777749809-777712827"
+		if !cmp.Equal(want, bodyMap["monitors"]) {
+			t.Error(cmp.Diff(want, bodyMap["monitors"]))
+		}
+		data, err := os.Open("testdata/getMonitors.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	monitors, err := client.GetMonitorsByIDs([]MonitorID{777749809, 777712827})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("want 1 request, got %d", requests)
+	}
+	if len(monitors) != 4 {
+		t.Errorf("want 4 monitors, got %d", len(monitors))
+	}
+}
+
+func TestGetMonitorsPages(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		var datafile string
+		switch bodyMap["offset"] {
+		case "0":
+			datafile = "testdata/getMonitorsPage1.json"
+		case "50":
+			datafile = "testdata/getMonitorsPage2.json"
+		default:
+			t.Fatalf("unexpected offset %s", bodyMap["offset"])
+		}
+		data, err := os.Open(datafile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	monitors, err := client.AllMonitors()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(monitors) != 100 {
+		t.Fatalf("Wanted 100 monitors, but got %d", len(monitors))
+	}
+	for i, m := range monitors {
+		want := fmt.Sprintf("monitor-%d", i+1)
+		got := m.FriendlyName
+		if !cmp.Equal(want, got) {
+			t.Error(cmp.Diff(want, got))
+		}
+	}
+}
+
+// myWebPageLogs are the logs for the 'My Web Page' monitor in the test data.
+var myWebPageLogs = []MonitorLog{
+	{Type: LogTypeStarted, Datetime: time.Unix(1462465202, 0).UTC(), Duration: 32 * time.Second},
+	{Type: LogTypeDown, Datetime: time.Unix(1462465234, 0).UTC(), Duration: 490140 * time.Second},
+	{Type: LogTypeUp, Datetime: time.Unix(1462955374, 0).UTC(), Duration: 85 * time.Second},
+	{Type: LogTypePaused, Datetime: time.Unix(1462955588, 0).UTC(), Duration: 12 * time.Second},
+	{Type: LogTypeStarted, Datetime: time.Unix(1462955600, 0).UTC(), Duration: 22 * time.Second},
+}
+
+func TestGetMonitorsPage(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		if bodyMap["offset"] != "0" || bodyMap["limit"] != "50" {
+			t.Errorf("want offset 0 and limit 50, got %v and %v", bodyMap["offset"], bodyMap["limit"])
+		}
+		data, err := os.Open("testdata/getMonitorsPage1.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	page, err := client.GetMonitorsPage(0, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Pagination{Offset: 0, Limit: 50, Total: 100}
+	if !cmp.Equal(want, page.Pagination) {
+		t.Error(cmp.Diff(want, page.Pagination))
+	}
+	if len(page.Monitors) != 50 {
+		t.Errorf("want 50 monitors, got %d", len(page.Monitors))
+	}
+}
+
+func TestPageSizeSetsRequestLimit(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		pageSize int
+		want     string
+	}{
+		{pageSize: 0, want: "50"},
+		{pageSize: 10, want: "10"},
+		{pageSize: 100, want: "50"},
+	}
+	for _, tc := range tcs {
+		var got interface{}
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bodyMap := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+				t.Fatal(err)
+			}
+			got = bodyMap["limit"]
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 0}, "monitors": []}`)
+		}))
+		client := New("dummy",
+			WithHTTPClient(ts.Client()),
+			WithBaseURL(ts.URL),
+			WithPageSize(tc.pageSize),
+		)
+		_, err := client.AllMonitors()
+		ts.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.want != got {
+			t.Errorf("page size %d: want limit %q, got %v", tc.pageSize, tc.want, got)
+		}
+	}
+}
+
+func TestMonitorsStopsEarly(t *testing.T) {
+	t.Parallel()
+	requests := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		data, err := os.Open("testdata/getMonitorsPage1.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	var names []string
+	err := client.Monitors(func(m Monitor) bool {
+		names = append(names, m.FriendlyName)
+		return len(names) < 3
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"monitor-1", "monitor-2", "monitor-3"}
+	if !cmp.Equal(want, names) {
+		t.Error(cmp.Diff(want, names))
+	}
+	if requests != 1 {
+		t.Errorf("want 1 request, got %d", requests)
+	}
+}
+
+func TestGetMonitors(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/getMonitors.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	want := []Monitor{
+		{
+			ID:           777749809,
+			FriendlyName: "Google",
+			URL:          "http://www.google.com",
+			Type:         TypeHTTP,
+			Port:         80,
+			Status:       StatusUnknown,
+			Interval:     900 * time.Second,
+			Logs: []MonitorLog{
+				{
+					Type:     LogTypeStarted,
+					Datetime: time.Unix(1463540297, 0).UTC(),
+					Duration: 1054134 * time.Second,
+				},
+			},
+		},
+		{
+			ID:           777712827,
+			FriendlyName: "My Web Page",
+			URL:          "http://mywebpage.com/",
+			Type:         TypeHTTP,
+			Status:       StatusUp,
+			Interval:     60 * time.Second,
+			Logs:         myWebPageLogs,
+		},
+		{
+			ID:           777559666,
+			FriendlyName: "My FTP Server",
+			URL:          "ftp.mywebpage.com",
+			Type:         TypePort,
+			SubType:      SubTypeFTP,
+			Port:         21,
+			Status:       StatusUp,
+			Interval:     60 * time.Second,
+		},
+		{
+			ID:           781397847,
+			FriendlyName: "PortTest",
 			URL:          "mywebpage.com",
 			Type:         TypePort,
 			SubType:      SubTypeCustomPort,
 			Port:         8000,
 			Status:       StatusUnknown,
+			Interval:     300 * time.Second,
+		},
+	}
+	got, err := client.AllMonitors()
+	if err != nil {
+		t.Error(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestAllMonitorsWithStatuses(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := "9-8"
+		if !cmp.Equal(want, bodyMap["statuses"]) {
+			t.Error(cmp.Diff(want, bodyMap["statuses"]))
+		}
+		data, err := os.Open("testdata/getMonitorsBySearch.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	monitors, err := client.AllMonitors(WithStatuses(StatusDown, StatusMaybeDown))
+	if err != nil {
+		t.Error(err)
+	}
+	if len(monitors) != 1 {
+		t.Fatalf("Wanted 1 monitor, but got %d", len(monitors))
+	}
+}
+
+func TestAllMonitorsWithTypes(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := "2-4"
+		if !cmp.Equal(want, bodyMap["types"]) {
+			t.Error(cmp.Diff(want, bodyMap["types"]))
+		}
+		data, err := os.Open("testdata/getMonitorsBySearch.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	if _, err := client.AllMonitors(WithTypes(TypeKeyword, TypePort)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestActivity(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/getMonitors.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	want := []ActivityEvent{
+		{
+			MonitorID:    777712827,
+			FriendlyName: "My Web Page",
+			Type:         LogTypePaused,
+			Time:         time.Unix(1462955588, 0).UTC(),
+		},
+		{
+			MonitorID:    777712827,
+			FriendlyName: "My Web Page",
+			Type:         LogTypeStarted,
+			Time:         time.Unix(1462955600, 0).UTC(),
+		},
+		{
+			MonitorID:    777749809,
+			FriendlyName: "Google",
+			Type:         LogTypeStarted,
+			Time:         time.Unix(1463540297, 0).UTC(),
+		},
+	}
+	got, err := client.Activity(time.Unix(1462465234, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestActivityRequestsLogsSinceTime(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			Logs          string `json:"logs"`
+			LogsStartDate string `json:"logs_start_date"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		if req.Logs != "1" {
+			t.Errorf("want logs 1, got %q", req.Logs)
+		}
+		if req.LogsStartDate != "1462465234" {
+			t.Errorf("want logs_start_date 1462465234, got %q", req.LogsStartDate)
+		}
+		io.WriteString(w, `{"stat": "ok", "monitors": []}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	if _, err := client.Activity(time.Unix(1462465234, 0)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestActivityIncludesOtherLogTypes(t *testing.T) {
+	t.Parallel()
+	monitors := []Monitor{{
+		ID:           777712827,
+		FriendlyName: "My Web Page",
+		Logs: []MonitorLog{
+			{Type: LogTypeDown, Datetime: time.Unix(1462955500, 0).UTC()},
+			{Type: 100, Datetime: time.Unix(1462955550, 0).UTC()},
+			{Type: LogTypeUp, Datetime: time.Unix(1462955560, 0).UTC()},
+		},
+	}}
+	want := []ActivityEvent{{
+		MonitorID:    777712827,
+		FriendlyName: "My Web Page",
+		Type:         100,
+		Time:         time.Unix(1462955550, 0).UTC(),
+	}}
+	got := activity(monitors, time.Unix(1462465234, 0))
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	if action := got[0].FriendlyAction(); action != "100" {
+		t.Errorf("want action %q, got %q", "100", action)
+	}
+}
+
+func TestAllMonitorsWithTimezone(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		if bodyMap["timezone"] != "1" {
+			t.Errorf("want timezone %q, got %q", "1", bodyMap["timezone"])
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{
+			"stat": "ok",
+			"timezone": 120,
+			"pagination": {"offset": 0, "limit": 50, "total": 1},
+			"monitors": [{"id": 777749809, "logs": [{"type": 98, "datetime": 1463540297, "duration": 1}]}]
+		}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	monitors, err := client.AllMonitors(WithTimezone())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := monitors[0].Logs[0].Datetime
+	if !got.Equal(time.Unix(1463540297, 0)) {
+		t.Errorf("want log time %v, got %v", time.Unix(1463540297, 0), got)
+	}
+	if _, offset := got.Zone(); offset != 7200 {
+		t.Errorf("want timezone offset 7200, got %d", offset)
+	}
+}
+
+func TestAccountLocation(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		if bodyMap["timezone"] != "1" {
+			t.Errorf("want timezone %q, got %q", "1", bodyMap["timezone"])
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"stat": "ok", "timezone": -300, "pagination": {"offset": 0, "limit": 1, "total": 0}, "monitors": []}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	loc, err := client.AccountLocation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := time.Date(2020, 1, 1, 0, 0, 0, 0, loc).Zone(); offset != -18000 {
+		t.Errorf("want timezone offset -18000, got %d", offset)
+	}
+}
+
+func TestGetMonitorsBySearch(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/getMonitorsBySearch.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	want := []Monitor{
+		{
+			ID:           777712827,
+			FriendlyName: "My Web Page",
+			URL:          "http://mywebpage.com/",
+			Type:         TypeHTTP,
+			Status:       StatusUp,
+			Interval:     60 * time.Second,
+			Logs:         myWebPageLogs,
+		},
+	}
+	got, err := client.SearchMonitors("My Web Page")
+	if err != nil {
+		t.Error(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestSearchMonitorsEncodesSpecialCharacters(t *testing.T) {
+	t.Parallel()
+	search := `say "hello" \ {world}`
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Errorf("request body is not valid JSON: %v", err)
+		}
+		if bodyMap["search"] != search {
+			t.Errorf("want search %q, got %q", search, bodyMap["search"])
+		}
+		fmt.Fprint(w, `{"stat": "ok", "monitors": []}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	if _, err := client.SearchMonitors(search); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchMonitorsWithSort(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"api_key": "dummy",
+			"format":  "json",
+			"limit":   "50",
+			"offset":  "0",
+			"search":  "My Web Page",
+			"sort":    "friendly_name",
+		}
+		if !cmp.Equal(want, bodyMap) {
+			t.Error(cmp.Diff(want, bodyMap))
+		}
+		data, err := os.Open("testdata/getMonitorsBySearch.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	if _, err := client.SearchMonitors("My Web Page", WithSort("friendly_name")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPauseMonitor(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/pauseMonitor.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	want := Monitor{
+		ID: 677810870,
+	}
+	got, err := client.PauseMonitor(want)
+	if err != nil {
+		t.Error(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestStartMonitor(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/startMonitor.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	want := Monitor{
+		ID: 677810870,
+	}
+	got, err := client.StartMonitor(want)
+	if err != nil {
+		t.Error(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestEnsure(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/ensure.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	mon := Monitor{
+		ID:           777712827,
+		FriendlyName: "My Web Page",
+		URL:          "http://mywebpage.com/",
+		Type:         TypeHTTP,
+	}
+	// The client will do a SearchMonitors and get a canned response from
+	// the test server containing no matches. It will now try to create the
+	// monitor, and the test server will just respond with an empty body and
+	// OK. The resulting monitor will have an ID of 0.
+	want := MonitorID(0)
+	got, changed, err := client.EnsureMonitor(mon)
+	if err != nil {
+		t.Error(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	if !changed {
+		t.Error("want changed true for new monitor, got false")
+	}
+}
+
+func TestGetMonitorsByURL(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 3}, "monitors": [
+			{"id": 1, "friendly_name": "Other page", "url": "http://mywebpage.com/other", "type": 1},
+			{"id": 2, "friendly_name": "Check http://mywebpage.com/", "url": "http://example.com/", "type": 1},
+			{"id": 3, "friendly_name": "My Web Page", "url": "http://mywebpage.com/", "type": 1}
+		]}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	monitors, err := client.GetMonitorsByURL("http://mywebpage.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 1 || monitors[0].ID != 3 {
+		t.Errorf("want only monitor 3, got %v", monitors)
+	}
+}
+
+func TestDeleteMonitorsBySearch(t *testing.T) {
+	t.Parallel()
+	var deleted []string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		switch r.URL.Path {
+		case "/v2/getMonitors":
+			if bodyMap["search"] != "pr-123" {
+				t.Errorf("want search %q, got %v", "pr-123", bodyMap["search"])
+			}
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 2}, "monitors": [
+				{"id": 1, "friendly_name": "pr-123 web", "url": "https://pr-123.example.com/", "type": 1},
+				{"id": 2, "friendly_name": "pr-123 api", "url": "https://api.pr-123.example.com/", "type": 1}
+			]}`)
+		case "/v2/deleteMonitor":
+			deleted = append(deleted, bodyMap["id"].(string))
+			fmt.Fprintf(w, `{"stat": "ok", "monitor": {"id": %s}}`, bodyMap["id"])
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	IDs, err := client.DeleteMonitorsBySearch("pr-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIDs := []MonitorID{1, 2}
+	if !cmp.Equal(wantIDs, IDs) {
+		t.Error(cmp.Diff(wantIDs, IDs))
+	}
+	wantDeleted := []string{"1", "2"}
+	if !cmp.Equal(wantDeleted, deleted) {
+		t.Error(cmp.Diff(wantDeleted, deleted))
+	}
+	if _, err := client.DeleteMonitorsBySearch(""); err == nil {
+		t.Error("want error for empty search string, got nil")
+	}
+}
+
+func TestEnsureReconcilesDrift(t *testing.T) {
+	t.Parallel()
+	existing := `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 2}, "monitors": [
+		{"id": 777712826, "friendly_name": "Other page", "url": "http://mywebpage.com/other", "type": 1},
+		{"id": 777712827, "friendly_name": "My Web Page", "url": "http://mywebpage.com/", "type": 1, "interval": 300,
+		 "alert_contacts": [{"id": "0102759", "type": 2, "threshold": 5, "recurrence": 60}]}
+	]}`
+	tcs := []struct {
+		name        string
+		desired     Monitor
+		wantChanged bool
+		wantEdit    map[string]interface{}
+	}{
+		{
+			name: "no drift",
+			desired: Monitor{
+				FriendlyName: "My Web Page",
+				URL:          "http://mywebpage.com/",
+				Type:         TypeHTTP,
+				Port:         80,
+			},
+			wantChanged: false,
+		},
+		{
+			name: "drifted name and contacts",
+			desired: Monitor{
+				FriendlyName:  "My Website",
+				URL:           "http://mywebpage.com/",
+				Type:          TypeHTTP,
+				AlertContacts: []ContactID{"0102759", "2053888"},
+			},
+			wantChanged: true,
+			wantEdit: map[string]interface{}{
+				"id":             "777712827",
+				"friendly_name":  "My Website",
+				"alert_contacts": "0102759_5_60-2053888_0_0",
+			},
+		},
+	}
+	for _, tc := range tcs {
+		var gotEdit map[string]interface{}
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bodyMap := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+				t.Fatal(err)
+			}
+			switch r.URL.Path {
+			case "/v2/getMonitors":
+				fmt.Fprint(w, existing)
+			case "/v2/editMonitor":
+				delete(bodyMap, "api_key")
+				delete(bodyMap, "format")
+				gotEdit = bodyMap
+				fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 777712827}}`)
+			default:
+				t.Errorf("%s: unexpected request to %s", tc.name, r.URL.Path)
+			}
+		}))
+		client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+		ID, changed, err := client.EnsureMonitor(tc.desired)
+		ts.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ID != 777712827 {
+			t.Errorf("%s: want ID 777712827, got %d", tc.name, ID)
+		}
+		if tc.wantChanged != changed {
+			t.Errorf("%s: want changed %t, got %t", tc.name, tc.wantChanged, changed)
+		}
+		if !cmp.Equal(tc.wantEdit, gotEdit) {
+			t.Errorf("%s: %s", tc.name, cmp.Diff(tc.wantEdit, gotEdit))
+		}
+	}
+}
+
+func TestEnsureRefusesTypeChange(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/getMonitors" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 1}, "monitors": [
+			{"id": 777712827, "friendly_name": "My Web Page", "url": "http://mywebpage.com/", "type": 1}
+		]}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	_, _, err := client.EnsureMonitor(Monitor{
+		FriendlyName: "My Web Page",
+		URL:          "http://mywebpage.com/",
+		Type:         TypeKeyword,
+		KeywordType:  KeywordExists,
+		KeywordValue: "Welcome",
+	})
+	if err == nil {
+		t.Error("want error changing monitor type, got nil")
+	}
+}
+
+func TestSyncMonitors(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		verb := strings.TrimPrefix(r.URL.Path, "/v2/")
+		switch verb {
+		case "getMonitors":
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 3}, "monitors": [
+				{"id": 1, "friendly_name": "A", "url": "https://a.example.com/", "type": 1},
+				{"id": 2, "friendly_name": "B", "url": "https://b.example.com/", "type": 1},
+				{"id": 3, "friendly_name": "C", "url": "https://c.example.com/", "type": 1}
+			]}`)
+			return
+		case "newMonitor":
+			fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 4}}`)
+		default:
+			fmt.Fprintf(w, `{"stat": "ok", "monitor": {"id": %s}}`, bodyMap["id"])
+		}
+		requests = append(requests, fmt.Sprintf("%s %v", verb, bodyMap["id"]))
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	desired := []Monitor{
+		{FriendlyName: "A", URL: "https://a.example.com/", Type: TypeHTTP},
+		{FriendlyName: "B renamed", URL: "https://b.example.com/", Type: TypeHTTP},
+		{FriendlyName: "D", URL: "https://d.example.com/", Type: TypeHTTP},
+	}
+	report, err := client.SyncMonitors(desired, WithPrune())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRequests := []string{"editMonitor 2", "newMonitor <nil>", "deleteMonitor 3"}
+	if !cmp.Equal(wantRequests, requests) {
+		t.Error(cmp.Diff(wantRequests, requests))
+	}
+	want := SyncReport{
+		Created: []Monitor{
+			{ID: 4, FriendlyName: "D", URL: "https://d.example.com/", Type: TypeHTTP},
+		},
+		Updated: []MonitorUpdate{{
+			Monitor: Monitor{ID: 2, FriendlyName: "B", URL: "https://b.example.com/", Type: TypeHTTP},
+			Changes: []Change{{Field: "FriendlyName", Old: "B", New: "B renamed"}},
+		}},
+		Unchanged: []Monitor{
+			{ID: 1, FriendlyName: "A", URL: "https://a.example.com/", Type: TypeHTTP},
+		},
+		Deleted: []Monitor{
+			{ID: 3, FriendlyName: "C", URL: "https://c.example.com/", Type: TypeHTTP},
+		},
+	}
+	if !cmp.Equal(want, report) {
+		t.Error(cmp.Diff(want, report))
+	}
+}
+
+func TestSyncMonitorsHandlesDuplicateExistingMonitors(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name         string
+		opts         []SyncOption
+		wantRequests []string
+		wantReport   SyncReport
+	}{
+		{
+			name:         "reports duplicates without prune",
+			wantRequests: []string{},
+			wantReport: SyncReport{
+				Unchanged:  []Monitor{{ID: 1, FriendlyName: "A", URL: "https://a.example.com/", Type: TypeHTTP}},
+				Duplicates: []Monitor{{ID: 2, FriendlyName: "A copy", URL: "https://a.example.com/", Type: TypeHTTP}},
+			},
+		},
+		{
+			name:         "deletes duplicates with prune",
+			opts:         []SyncOption{WithPrune()},
+			wantRequests: []string{"deleteMonitor 2"},
+			wantReport: SyncReport{
+				Unchanged: []Monitor{{ID: 1, FriendlyName: "A", URL: "https://a.example.com/", Type: TypeHTTP}},
+				Deleted:   []Monitor{{ID: 2, FriendlyName: "A copy", URL: "https://a.example.com/", Type: TypeHTTP}},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var mu sync.Mutex
+			requests := []string{}
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				bodyMap := map[string]interface{}{}
+				if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+					t.Error(err)
+				}
+				verb := strings.TrimPrefix(r.URL.Path, "/v2/")
+				if verb == "getMonitors" {
+					fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 2}, "monitors": [
+						{"id": 1, "friendly_name": "A", "url": "https://a.example.com/", "type": 1},
+						{"id": 2, "friendly_name": "A copy", "url": "https://a.example.com/", "type": 1}
+					]}`)
+					return
+				}
+				fmt.Fprintf(w, `{"stat": "ok", "monitor": {"id": %s}}`, bodyMap["id"])
+				mu.Lock()
+				requests = append(requests, fmt.Sprintf("%s %v", verb, bodyMap["id"]))
+				mu.Unlock()
+			}))
+			defer ts.Close()
+			client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+			desired := []Monitor{
+				{FriendlyName: "A", URL: "https://a.example.com/", Type: TypeHTTP},
+			}
+			report, err := client.SyncMonitors(desired, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.wantRequests, requests) {
+				t.Error(cmp.Diff(tc.wantRequests, requests))
+			}
+			if !cmp.Equal(tc.wantReport, report) {
+				t.Error(cmp.Diff(tc.wantReport, report))
+			}
+		})
+	}
+}
+
+func TestSyncMonitorsRejectsDuplicateURLs(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	desired := []Monitor{
+		{FriendlyName: "A", URL: "https://a.example.com/", Type: TypeHTTP},
+		{FriendlyName: "A again", URL: "https://a.example.com/", Type: TypeHTTP},
+	}
+	if _, err := client.SyncMonitors(desired); err == nil {
+		t.Error("want error for duplicate URLs, got nil")
+	}
+}
+
+func TestAllMWindows(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/getMWindows.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.AllMWindows()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MWindow{
+		{
+			ID:           581,
+			FriendlyName: "Release 2.4",
+			Type:         MWindowTypeOnce,
+			StartTime:    time.Date(2020, 1, 2, 3, 4, 0, 0, time.UTC),
+			Duration:     90 * time.Minute,
+			Status:       1,
+		},
+		{
+			ID:           582,
+			FriendlyName: "Weekend backups",
+			Type:         MWindowTypeWeekly,
+			Value:        "6-7",
+			StartTime:    time.Date(0, 1, 1, 2, 0, 0, 0, time.UTC),
+			Duration:     2 * time.Hour,
+			Status:       1,
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestGetMWindowNotFound(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 0}, "mwindows": []}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	_, err := client.GetMWindow(581)
+	var nf NotFoundError
+	if !errors.As(err, &nf) {
+		t.Errorf("want NotFoundError, got %v", err)
+	}
+}
+
+func TestAllPSPs(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/getPSPs.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.AllPSPs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PSP{
+		{
+			ID:           2345678,
+			FriendlyName: "Everything",
+			Monitors:     []MonitorID{},
+			Sort:         PSPSortFriendlyNameAZ,
+			Status:       1,
+			StandardURL:  "https://stats.uptimerobot.com/xyz01",
+		},
+		{
+			ID:                2345679,
+			FriendlyName:      "Customer API",
+			Monitors:          []MonitorID{777749809, 777712827},
+			Sort:              PSPSortStatusDownUp,
+			Status:            1,
+			StandardURL:       "https://stats.uptimerobot.com/xyz02",
+			CustomDomain:      "status.example.com",
+			PasswordProtected: true,
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestCreatePSP(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantURL := "/v2/newPSP"
+		if r.URL.EscapedPath() != wantURL {
+			t.Errorf("want %q, got %q", wantURL, r.URL.EscapedPath())
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"stat": "ok", "psp": {"id": 2345680, "status": 1}}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.CreatePSP(PSP{
+		FriendlyName: "Staging",
+		Monitors:     []MonitorID{777749809},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want PSPID = 2345680
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestEditPSPSendsOnlySetFields(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantURL := "/v2/editPSP"
+		if r.URL.EscapedPath() != wantURL {
+			t.Errorf("want %q, got %q", wantURL, r.URL.EscapedPath())
+		}
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"api_key":       "dummy",
+			"format":        "json",
+			"id":            "2345679",
+			"monitors":      "777749809-777712827",
+			"sort":          float64(PSPSortStatusDownUp),
+			"custom_domain": "",
+		}
+		if !cmp.Equal(want, bodyMap) {
+			t.Error(cmp.Diff(want, bodyMap))
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"stat": "ok", "psp": {"id": 2345679}}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.EditPSP(EditPSPParams{
+		ID:           2345679,
+		Monitors:     &[]MonitorID{777749809, 777712827},
+		Sort:         Int(PSPSortStatusDownUp),
+		CustomDomain: String(""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want PSPID = 2345679
+	if !cmp.Equal(want, got.ID) {
+		t.Error(cmp.Diff(want, got.ID))
+	}
+}
+
+func TestDeletePSP(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		if bodyMap["id"] != "2345679" {
+			fmt.Fprint(w, `{"stat": "fail", "error": {"type": "not_found", "parameter_name": "id", "passed_value": "9999"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"stat": "ok", "psp": {"id": 2345679}}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	if err := client.DeletePSP(2345679); err != nil {
+		t.Fatal(err)
+	}
+	err := client.DeletePSP(9999)
+	want := NotFoundError{Resource: "status page", ID: "9999"}
+	var got NotFoundError
+	if !errors.As(err, &got) {
+		t.Fatalf("want NotFoundError, got %v", err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestAddAndRemovePSPMonitor(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name         string
+		remove       bool
+		pspMonitors  string
+		monitorID    MonitorID
+		wantMonitors interface{}
+	}{
+		{
+			name:         "Add new monitor",
+			pspMonitors:  "[1, 2]",
+			monitorID:    3,
+			wantMonitors: "1-2-3",
+		},
+		{
+			name:         "Add existing monitor",
+			pspMonitors:  "[1, 2]",
+			monitorID:    2,
+			wantMonitors: nil,
+		},
+		{
+			name:         "Add monitor to page showing all monitors",
+			pspMonitors:  "0",
+			monitorID:    3,
+			wantMonitors: nil,
+		},
+		{
+			name:         "Remove existing monitor",
+			remove:       true,
+			pspMonitors:  "[1, 2]",
+			monitorID:    1,
+			wantMonitors: "2",
+		},
+		{
+			name:         "Remove missing monitor",
+			remove:       true,
+			pspMonitors:  "[1, 2]",
+			monitorID:    3,
+			wantMonitors: nil,
+		},
+		{
+			name:         "Remove monitor from page showing all monitors",
+			remove:       true,
+			pspMonitors:  "0",
+			monitorID:    2,
+			wantMonitors: "1-3",
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var gotMonitors interface{}
+			client := New("dummy")
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				bodyMap := map[string]interface{}{}
+				if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+					t.Fatal(err)
+				}
+				w.WriteHeader(http.StatusOK)
+				switch r.URL.Path {
+				case "/v2/getPSPs":
+					fmt.Fprintf(w, `{
+						"stat": "ok",
+						"pagination": {"offset": 0, "limit": 50, "total": 1},
+						"psps": [{"id": 2345679, "monitors": %s}]
+					}`, tc.pspMonitors)
+				case "/v2/getMonitors":
+					fmt.Fprint(w, `{
+						"stat": "ok",
+						"pagination": {"offset": 0, "limit": 50, "total": 3},
+						"monitors": [{"id": 1}, {"id": 2}, {"id": 3}]
+					}`)
+				case "/v2/editPSP":
+					gotMonitors = bodyMap["monitors"]
+					fmt.Fprint(w, `{"stat": "ok", "psp": {"id": 2345679}}`)
+				default:
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+			}))
+			defer ts.Close()
+			client.HTTPClient = ts.Client()
+			client.URL = ts.URL
+			var err error
+			if tc.remove {
+				err = client.RemoveMonitorFromPSP(2345679, tc.monitorID)
+			} else {
+				err = client.AddMonitorToPSP(2345679, tc.monitorID)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.wantMonitors, gotMonitors) {
+				t.Error(cmp.Diff(tc.wantMonitors, gotMonitors))
+			}
+		})
+	}
+}
+
+func TestRemoveLastMonitorFromPSPReturnsError(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/getPSPs" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{
+			"stat": "ok",
+			"pagination": {"offset": 0, "limit": 50, "total": 1},
+			"psps": [{"id": 2345679, "monitors": [1]}]
+		}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	err := client.RemoveMonitorFromPSP(2345679, 1)
+	if !errors.Is(err, ErrLastPSPMonitor) {
+		t.Errorf("want ErrLastPSPMonitor, got %v", err)
+	}
+}
+
+func TestCreateMWindow(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/newMWindow.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.CreateMWindow(MWindow{
+		FriendlyName: "Deploy",
+		Type:         MWindowTypeDaily,
+		StartTime:    time.Date(0, 1, 1, 2, 0, 0, 0, time.UTC),
+		Duration:     30 * time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want MWindowID = 1234
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestEditMWindow(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantURL := "/v2/editMWindow"
+		if r.URL.EscapedPath() != wantURL {
+			t.Errorf("want %q, got %q", wantURL, r.URL.EscapedPath())
+		}
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"api_key":       "dummy",
+			"format":        "json",
+			"id":            "1234",
+			"friendly_name": "Deploy",
+			"start_time":    "1577937840",
+			"duration":      float64(120),
+		}
+		if !cmp.Equal(want, bodyMap) {
+			t.Error(cmp.Diff(want, bodyMap))
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"stat": "ok", "mwindow": {"id": 1234}}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	got, err := client.EditMWindow(MWindow{
+		ID:           1234,
+		FriendlyName: "Deploy",
+		Type:         MWindowTypeOnce,
+		StartTime:    time.Date(2020, 1, 2, 4, 4, 0, 0, time.UTC),
+		Duration:     2 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want MWindowID = 1234
+	if !cmp.Equal(want, got.ID) {
+		t.Error(cmp.Diff(want, got.ID))
+	}
+}
+
+func TestDeleteMWindow(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusOK)
+		if bodyMap["id"] != "1234" {
+			fmt.Fprint(w, `{"stat": "fail", "error": {"type": "not_found", "parameter_name": "id", "passed_value": "9999"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"stat": "ok", "mwindow": {"id": 1234}}`)
+	}))
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	if err := client.DeleteMWindow(1234); err != nil {
+		t.Fatal(err)
+	}
+	err := client.DeleteMWindow(9999)
+	want := NotFoundError{Resource: "maintenance window", ID: "9999"}
+	var got NotFoundError
+	if !errors.As(err, &got) {
+		t.Fatalf("want NotFoundError, got %v", err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestWeeklyWindow(t *testing.T) {
+	t.Parallel()
+	start := time.Date(0, 1, 1, 23, 30, 0, 0, time.UTC)
+	days := []time.Weekday{time.Monday, time.Wednesday, time.Sunday}
+	w := WeeklyWindow(days, start, time.Hour)
+	want := MWindow{
+		Type:      MWindowTypeWeekly,
+		Value:     "1-3-7",
+		StartTime: start,
+		Duration:  time.Hour,
+	}
+	if !cmp.Equal(want, w) {
+		t.Error(cmp.Diff(want, w))
+	}
+	got, err := w.Weekdays()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(days, got) {
+		t.Error(cmp.Diff(days, got))
+	}
+	if _, err := (MWindow{Type: MWindowTypeWeekly, Value: "1-8"}).Weekdays(); err == nil {
+		t.Error("want error for invalid day of the week, got nil")
+	}
+}
+
+func TestMonthlyWindow(t *testing.T) {
+	t.Parallel()
+	start := time.Date(0, 1, 1, 2, 0, 0, 0, time.UTC)
+	days := []int{1, 15}
+	w := MonthlyWindow(days, start, 30*time.Minute)
+	want := MWindow{
+		Type:      MWindowTypeMonthly,
+		Value:     "1-15",
+		StartTime: start,
+		Duration:  30 * time.Minute,
+	}
+	if !cmp.Equal(want, w) {
+		t.Error(cmp.Diff(want, w))
+	}
+	got, err := w.DaysOfMonth()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(days, got) {
+		t.Error(cmp.Diff(days, got))
+	}
+	if _, err := w.Weekdays(); err == nil {
+		t.Error("want error decoding weekdays of monthly window, got nil")
+	}
+}
+
+func TestUnmarshalMWindow(t *testing.T) {
+	t.Parallel()
+	data := []byte(`[
+		{"id": 1, "friendly_name": "Deploy", "type": 1, "value": "", "start_time": 1577934240, "duration": 90, "status": 1},
+		{"id": 2, "friendly_name": "Backups", "type": 3, "value": "2-4", "start_time": "23:30", "duration": 60, "status": 0}
+	]`)
+	got := []MWindow{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []MWindow{
+		{
+			ID:           1,
+			FriendlyName: "Deploy",
+			Type:         MWindowTypeOnce,
+			StartTime:    time.Date(2020, 1, 2, 3, 4, 0, 0, time.UTC),
+			Duration:     90 * time.Minute,
+			Status:       1,
+		},
+		{
+			ID:           2,
+			FriendlyName: "Backups",
+			Type:         MWindowTypeWeekly,
+			Value:        "2-4",
+			StartTime:    time.Date(0, 1, 1, 23, 30, 0, 0, time.UTC),
+			Duration:     time.Hour,
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestDeleteMonitor(t *testing.T) {
+	t.Parallel()
+	client := New("dummy")
+	ts := cannedResponseServer(t, "testdata/deleteMonitor.json")
+	defer ts.Close()
+	client.HTTPClient = ts.Client()
+	client.URL = ts.URL
+	var want MonitorID = 777810874
+	if err := client.DeleteMonitor(want); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMarshalRequests(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{
+			name: "getMonitors",
+			input: getMonitorsRequest{
+				Monitors: "777749809-777712827",
+				Statuses: "8-9",
+				Offset:   "0",
+				Limit:    "50",
+			},
+			want: `{"monitors":"777749809-777712827","statuses":"8-9","offset":"0","limit":"50"}`,
+		},
+		{
+			name: "getAlertContacts",
+			input: getAlertContactsRequest{
+				Offset: "50",
+				Limit:  "50",
+			},
+			want: `{"offset":"50","limit":"50"}`,
+		},
+		{
+			name: "editMonitor status",
+			input: editMonitorStatusRequest{
+				ID:     677810870,
+				Status: EditStatusPause,
+			},
+			want: `{"id":"677810870","status":0}`,
+		},
+		{
+			name: "deleteMonitor",
+			input: deleteMonitorRequest{
+				ID: 777810874,
+			},
+			want: `{"id":"777810874"}`,
+		},
+		{
+			name: "newMWindow once",
+			input: newMWindowRequest(MWindow{
+				FriendlyName: "Deploy",
+				Type:         MWindowTypeOnce,
+				StartTime:    time.Date(2020, 1, 2, 3, 4, 0, 0, time.UTC),
+				Duration:     90 * time.Minute,
+			}),
+			want: `{"friendly_name":"Deploy","type":1,"start_time":"1577934240","duration":90}`,
+		},
+		{
+			name: "newMWindow weekly",
+			input: newMWindowRequest(MWindow{
+				FriendlyName: "Backups",
+				Type:         MWindowTypeWeekly,
+				Value:        "2-4",
+				StartTime:    time.Date(0, 1, 1, 23, 30, 0, 0, time.UTC),
+				Duration:     time.Hour,
+			}),
+			want: `{"friendly_name":"Backups","type":3,"value":"2-4","start_time":"23:30","duration":60}`,
+		},
+		{
+			name: "newPSP all monitors",
+			input: newPSPRequest(PSP{
+				FriendlyName: "Everything",
+			}),
+			want: `{"type":1,"friendly_name":"Everything","monitors":"0"}`,
+		},
+		{
+			name: "newPSP",
+			input: newPSPRequest(PSP{
+				FriendlyName: "Customer API",
+				Monitors:     []MonitorID{777749809, 777712827},
+				CustomDomain: "status.example.com",
+				Password:     "secret",
+				Sort:         PSPSortStatusDownUp,
+			}),
+			want: `{"type":1,"friendly_name":"Customer API","monitors":"777749809-777712827","custom_domain":"status.example.com","password":"secret","sort":4}`,
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := json.Marshal(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := string(data)
+			if !cmp.Equal(tc.want, got) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestRenderMonitor(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name     string
+		input    Monitor
+		wantFile string
+	}{
+		{
+			name: "Simple HTTP",
+			input: Monitor{
+				ID:            777749809,
+				FriendlyName:  "Google",
+				URL:           "http://www.google.com",
+				Type:          TypeHTTP,
+				Port:          0,
+				AlertContacts: []ContactID{"3", "5", "7"},
+				Status:        StatusUp,
+			},
+			wantFile: "testdata/monitor_http.txt",
+		},
+		{
+			name: "Keyword exists",
+			input: Monitor{
+				ID:           777749810,
+				FriendlyName: "Google",
+				URL:          "http://www.google.com",
+				Type:         TypeKeyword,
+				KeywordType:  KeywordExists,
+				KeywordValue: "bogus",
+				Port:         80,
+				Status:       StatusMaybeDown,
+			},
+			wantFile: "testdata/monitor_keyword.txt",
+		},
+		{
+			name: "Keyword not exists",
+			input: Monitor{
+				ID:           777749811,
+				FriendlyName: "Google",
+				URL:          "http://www.google.com",
+				Type:         TypeKeyword,
+				KeywordType:  KeywordNotExists,
+				KeywordValue: "bogus",
+				Port:         80,
+				Status:       StatusUnknown,
+			},
+			wantFile: "testdata/monitor_keyword_notexists.txt",
+		},
+		{
+			name: "Subtype",
+			input: Monitor{
+				ID:           777749812,
+				FriendlyName: "Google",
+				URL:          "http://www.google.com",
+				Type:         TypePort,
+				SubType:      SubTypeFTP,
+				Port:         80,
+				Status:       StatusPaused,
+			},
+			wantFile: "testdata/monitor_subtype.txt",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			wantBytes, err := ioutil.ReadFile(tc.wantFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := string(wantBytes)
+			got, err := tc.input.Render(MonitorTemplate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(want, got) {
+				t.Error(cmp.Diff(want, got))
+			}
+
+		})
+	}
+}
+
+func TestRenderAccount(t *testing.T) {
+	t.Parallel()
+	input := Account{
+		Email:           "j.random@example.com",
+		MonitorLimit:    300,
+		MonitorInterval: time.Minute,
+		UpMonitors:      208,
+		DownMonitors:    2,
+		PausedMonitors:  0,
+	}
+	wantBytes, err := ioutil.ReadFile("testdata/account_template.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := string(wantBytes)
+	got, err := input.Render(AccountTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestRenderMWindow(t *testing.T) {
+	t.Parallel()
+	input := MWindow{
+		ID:           582,
+		FriendlyName: "Weekend backups",
+		Type:         MWindowTypeWeekly,
+		Value:        "6-7",
+		StartTime:    time.Date(0, 1, 1, 2, 0, 0, 0, time.UTC),
+		Duration:     2 * time.Hour,
+	}
+	wantBytes, err := ioutil.ReadFile("testdata/mwindow_template.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := string(wantBytes)
+	got := input.String()
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestRenderPSP(t *testing.T) {
+	t.Parallel()
+	input := PSP{
+		ID:                2345679,
+		FriendlyName:      "Customer API",
+		Monitors:          []MonitorID{777749809, 777712827},
+		StandardURL:       "https://stats.uptimerobot.com/xyz02",
+		CustomDomain:      "status.example.com",
+		PasswordProtected: true,
+	}
+	wantBytes, err := ioutil.ReadFile("testdata/psp_template.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := string(wantBytes)
+	got := input.String()
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestFriendlyType(t *testing.T) {
+	t.Parallel()
+	m := Monitor{
+		Type: TypeHTTP,
+	}
+	want := "HTTP"
+	got := m.FriendlyType()
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestFriendlySubType(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name string
+		mon  Monitor
+		want string
+	}{
+		{
+			name: "HTTPS",
+			mon: Monitor{
+				Type:    TypePort,
+				SubType: SubTypeHTTPS,
+			},
+			want: "HTTPS (443)",
+		},
+		{
+			name: "Custom port",
+			mon: Monitor{
+				Type:    TypePort,
+				SubType: SubTypeCustomPort,
+				Port:    8080,
+			},
+			want: "Custom port (8080)",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := tc.mon.FriendlySubType()
+			if !cmp.Equal(tc.want, got) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestFriendlyKeywordType(t *testing.T) {
+	t.Parallel()
+	m := Monitor{
+		Type:        TypeKeyword,
+		KeywordType: KeywordExists,
+	}
+	want := "Exists"
+	got := m.FriendlyKeywordType()
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+	monitors := []Monitor{
+		{
+			ID:            1,
+			FriendlyName:  "Healthy",
+			URL:           "https://example.com/login",
+			Type:          TypeHTTP,
+			AlertContacts: []ContactID{"3"},
+			Interval:      60 * time.Second,
+			Status:        StatusUp,
+		},
+		{
+			ID:           2,
+			FriendlyName: "prod-checkout",
+			URL:          "http://example.com/signin",
+			Type:         TypeKeyword,
+			Interval:     300 * time.Second,
+			Status:       StatusPaused,
+			Logs: []MonitorLog{
+				{Type: LogTypePaused, Datetime: time.Date(2016, 5, 11, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+	cfg := LintConfig{
+		Critical: func(m Monitor) bool {
+			return strings.HasPrefix(m.FriendlyName, "prod-")
+		},
+		MaxPausedAge: 30 * 24 * time.Hour,
+	}
+	want := []string{
+		"2 (prod-checkout): no alert contacts",
+		"2 (prod-checkout): critical monitor checked only every 5m0s",
+		"2 (prod-checkout): keyword monitor has no keyword",
+		"2 (prod-checkout): login page monitored over plain HTTP",
+		"2 (prod-checkout): paused since 2016-05-11",
+	}
+	got := []string{}
+	for _, p := range Lint(monitors, cfg) {
+		got = append(got, p.String())
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name  string
+		tmpl  string
+		input interface{}
+		want  string
+	}{
+		{
+			name:  "Duration in minutes",
+			tmpl:  "{{ duration . }}",
+			input: 5 * time.Minute,
+			want:  "5m",
+		},
+		{
+			name:  "Duration in hours and minutes",
+			tmpl:  "{{ duration . }}",
+			input: 90 * time.Minute,
+			want:  "1h30m",
+		},
+		{
+			name:  "Duration in seconds",
+			tmpl:  "{{ duration . }}",
+			input: 90 * time.Second,
+			want:  "1m30s",
+		},
+		{
+			name:  "Percent",
+			tmpl:  "{{ percent . }}",
+			input: 99.981,
+			want:  "99.98%",
+		},
+		{
+			name:  "Status colour",
+			tmpl:  "{{ colorStatus . }}",
+			input: StatusDown,
+			want:  "\x1b[31mDown\x1b[0m",
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := render(tc.tmpl, tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.want, got) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+// cannedResponseServer returns a test TLS server which responds to any request
+// with a specified file of canned JSON data.
+func cannedResponseServer(t *testing.T, path string) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		data, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer data.Close()
+		io.Copy(w, data)
+	}))
+}
+
+func TestPauseAll(t *testing.T) {
+	t.Parallel()
+	var edited []string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		switch r.URL.Path {
+		case "/v2/getMonitors":
+			if bodyMap["search"] != "example.com" {
+				t.Errorf("want search %q, got %v", "example.com", bodyMap["search"])
+			}
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 3}, "monitors": [
+				{"id": 1, "friendly_name": "web", "url": "https://example.com/", "type": 1, "status": 2},
+				{"id": 2, "friendly_name": "api", "url": "https://api.example.com/", "type": 1, "status": 0},
+				{"id": 3, "friendly_name": "docs", "url": "https://docs.example.com/", "type": 1, "status": 9}
+			]}`)
+		case "/v2/editMonitor":
+			if bodyMap["status"] != float64(EditStatusPause) {
+				t.Errorf("want status %d, got %v", EditStatusPause, bodyMap["status"])
+			}
+			edited = append(edited, bodyMap["id"].(string))
+			if bodyMap["id"] == "3" {
+				fmt.Fprint(w, `{"stat": "fail", "error": {"type": "internal", "message": "something went wrong"}}`)
+				return
+			}
+			fmt.Fprintf(w, `{"stat": "ok", "monitor": {"id": %s}}`, bodyMap["id"])
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	results, err := client.PauseAll("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEdited := []string{"1", "3"}
+	if !cmp.Equal(wantEdited, edited) {
+		t.Error(cmp.Diff(wantEdited, edited))
+	}
+	if len(results) != 2 {
+		t.Fatalf("want 2 results, got %d", len(results))
+	}
+	if results[0].Monitor.ID != 1 || results[0].Err != nil {
+		t.Errorf("want monitor 1 paused without error, got %+v", results[0])
+	}
+	if results[1].Monitor.ID != 3 || results[1].Err == nil {
+		t.Errorf("want error pausing monitor 3, got %+v", results[1])
+	}
+}
+
+func TestStartAllResumesOnlyPausedMonitors(t *testing.T) {
+	t.Parallel()
+	var edited []string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		switch r.URL.Path {
+		case "/v2/getMonitors":
+			if _, ok := bodyMap["search"]; ok {
+				t.Errorf("want no search for empty search string, got %v", bodyMap["search"])
+			}
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 2}, "monitors": [
+				{"id": 1, "friendly_name": "web", "url": "https://example.com/", "type": 1, "status": 0},
+				{"id": 2, "friendly_name": "api", "url": "https://api.example.com/", "type": 1, "status": 2}
+			]}`)
+		case "/v2/editMonitor":
+			if bodyMap["status"] != float64(EditStatusResume) {
+				t.Errorf("want status %d, got %v", EditStatusResume, bodyMap["status"])
+			}
+			edited = append(edited, bodyMap["id"].(string))
+			fmt.Fprintf(w, `{"stat": "ok", "monitor": {"id": %s}}`, bodyMap["id"])
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	results, err := client.StartAll("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEdited := []string{"1"}
+	if !cmp.Equal(wantEdited, edited) {
+		t.Error(cmp.Diff(wantEdited, edited))
+	}
+	if len(results) != 1 || results[0].Err != nil {
+		t.Errorf("want one successful result, got %+v", results)
+	}
+}
+
+func BenchmarkUnmarshalMonitors(b *testing.B) {
+	data, err := ioutil.ReadFile("testdata/getMonitors.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		r := Response{}
+		if err := json.Unmarshal(data, &r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAPIErrorDescribesFailure(t *testing.T) {
+	t.Parallel()
+	long := `{"stat": "ok", "monitors": [` + strings.Repeat(`{"id": 1},`, 100)
+	tcs := []struct {
+		name       string
+		status     int
+		body       string
+		want       APIError
+		errMsg     string
+		wantDecode bool
+	}{
+		{
+			name:   "API error",
+			status: http.StatusOK,
+			body:   `{"stat": "fail", "error": {"type": "invalid_parameter", "message": "api_key not found.", "parameter_name": "api_key"}}`,
+			want: APIError{
+				Verb:       "getMonitors",
+				StatusCode: http.StatusOK,
+				Type:       "invalid_parameter",
+				Message:    "api_key not found.",
+				Details: Error{
+					"type":           "invalid_parameter",
+					"message":        "api_key not found.",
+					"parameter_name": "api_key",
+				},
+				Body: `{"stat": "fail", "error": {"type": "invalid_parameter", "message": "api_key not found.", "parameter_name": "api_key"}}`,
+			},
+			errMsg: "getMonitors failed (HTTP 200): API error invalid_parameter: api_key not found. (parameter_name: api_key)",
+		},
+		{
+			name:   "unexpected status",
+			status: http.StatusBadGateway,
+			body:   "Bad Gateway",
+			want: APIError{
+				Verb:       "getMonitors",
+				StatusCode: http.StatusBadGateway,
+				Body:       "Bad Gateway",
+			},
+			errMsg: `getMonitors failed (HTTP 502): unexpected response: "Bad Gateway"`,
+		},
+		{
+			name:   "truncated invalid response",
+			status: http.StatusOK,
+			body:   long,
+			want: APIError{
+				Verb:       "getMonitors",
+				StatusCode: http.StatusOK,
+				Body:       long[:maxErrorBodyLength] + "...",
+			},
+			wantDecode: true,
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer ts.Close()
+			client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+			_, err := client.SearchMonitors("example")
+			var got APIError
+			if !errors.As(err, &got) {
+				t.Fatalf("want APIError, got %v", err)
+			}
+			if tc.errMsg != "" && err.Error() != tc.errMsg {
+				t.Errorf("want error %q, got %q", tc.errMsg, err.Error())
+			}
+			if tc.wantDecode != (got.Err != nil) {
+				t.Errorf("want decoding error %t, got %v", tc.wantDecode, got.Err)
+			}
+			got.Err = nil
+			if !cmp.Equal(tc.want, got) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestMonitorCount(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		if bodyMap["limit"] != "1" {
+			t.Errorf("want limit 1, got %v", bodyMap["limit"])
+		}
+		if bodyMap["statuses"] != "9" {
+			t.Errorf("want statuses 9, got %v", bodyMap["statuses"])
+		}
+		fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 1, "total": 17}, "monitors": [{"id": 1}]}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	count, err := client.MonitorCount(WithStatuses(StatusDown))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 17 {
+		t.Errorf("want count 17, got %d", count)
+	}
+}
+
+func TestDoCallsArbitraryVerb(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/getWidgets" {
+			t.Errorf("want request to /v2/getWidgets, got %s", r.URL.Path)
+		}
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"api_key": "dummy",
+			"format":  "json",
+			"colour":  "blue",
+		}
+		if !cmp.Equal(want, bodyMap) {
+			t.Error(cmp.Diff(want, bodyMap))
+		}
+		fmt.Fprint(w, `{"stat": "ok", "widgets": [{"name": "sprocket"}]}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	var out struct {
+		Widgets []struct {
+			Name string `json:"name"`
+		} `json:"widgets"`
+	}
+	err := client.Do("getWidgets", map[string]string{"colour": "blue"}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Widgets) != 1 || out.Widgets[0].Name != "sprocket" {
+		t.Errorf("want sprocket widget, got %+v", out.Widgets)
+	}
+}
+
+func TestDoRespectsReadOnly(t *testing.T) {
+	t.Parallel()
+	client := New("dummy", WithReadOnly())
+	err := client.Do("deleteWidget", nil, nil)
+	var got ReadOnlyError
+	if !errors.As(err, &got) {
+		t.Errorf("want ReadOnlyError, got %v", err)
+	}
+}
+
+func TestWithKeySharesHTTPClient(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	keys := []string{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		keys = append(keys, bodyMap["api_key"].(string))
+		mu.Unlock()
+		fmt.Fprint(w, `{"stat": "ok", "monitors": []}`)
+	}))
+	defer ts.Close()
+	client := New("main-key", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	customer := client.WithKey("customer-key")
+	if customer.HTTPClient != client.HTTPClient {
+		t.Error("want HTTP client shared")
+	}
+	if _, err := customer.SearchMonitors("example"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SearchMonitors("example"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"customer-key", "main-key"}
+	if !cmp.Equal(want, keys) {
+		t.Error(cmp.Diff(want, keys))
+	}
+}
+
+func TestClientConcurrentUse(t *testing.T) {
+	t.Parallel()
+	var contactFetches int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/getAlertContacts":
+			atomic.AddInt32(&contactFetches, 1)
+			fmt.Fprint(w, `{"stat": "ok", "offset": 0, "limit": 50, "total": 1, "alert_contacts": [{"id": "1", "friendly_name": "On call"}]}`)
+		default:
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 0}, "monitors": []}`)
+		}
+	}))
+	defer ts.Close()
+	client := New("dummy",
+		WithHTTPClient(ts.Client()),
+		WithBaseURL(ts.URL),
+		WithRequestInterval(time.Millisecond),
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := client.AlertContactByName("On call"); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := client.AllMonitors(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&contactFetches); n != 1 {
+		t.Errorf("want alert contacts fetched once, got %d fetches", n)
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("UPTIMEROBOT_API_KEY", "env-key")
+	t.Setenv("UPTIMEROBOT_URL", "https://example.com")
+	client, err := NewFromEnv(WithRetries(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.apiKey != "env-key" {
+		t.Errorf("want API key %q, got %q", "env-key", client.apiKey)
+	}
+	if client.URL != "https://example.com" {
+		t.Errorf("want URL %q, got %q", "https://example.com", client.URL)
+	}
+	if client.MaxRetries != 7 {
+		t.Errorf("want 7 retries, got %d", client.MaxRetries)
+	}
+}
+
+func TestConfigAPIKey(t *testing.T) {
+	t.Parallel()
+	empty := t.TempDir()
+	yamlDir := t.TempDir()
+	if err := os.WriteFile(yamlDir+"/.uptimerobot.yaml", []byte("apiKey: yaml-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	jsonDir := t.TempDir()
+	if err := os.WriteFile(jsonDir+"/.uptimerobot.json", []byte(`{"apikey": "json-key"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tcs := []struct {
+		dirs []string
+		want string
+	}{
+		{dirs: []string{empty}, want: ""},
+		{dirs: []string{empty, yamlDir}, want: "yaml-key"},
+		{dirs: []string{jsonDir, yamlDir}, want: "json-key"},
+	}
+	for _, tc := range tcs {
+		got, err := configAPIKey(tc.dirs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.want != got {
+			t.Errorf("%v: want %q, got %q", tc.dirs, tc.want, got)
+		}
+	}
+	badDir := t.TempDir()
+	if err := os.WriteFile(badDir+"/.uptimerobot.json", []byte(`{`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := configAPIKey(badDir); err == nil {
+		t.Error("want error for invalid config file, got nil")
+	}
+}
+
+func TestGetLogsSince(t *testing.T) {
+	t.Parallel()
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		if bodyMap["logs"] != "1" {
+			t.Errorf("want logs 1, got %v", bodyMap["logs"])
+		}
+		wantStart := strconv.FormatInt(since.Unix(), 10)
+		if bodyMap["logs_start_date"] != wantStart {
+			t.Errorf("want logs_start_date %s, got %v", wantStart, bodyMap["logs_start_date"])
+		}
+		end, err := strconv.ParseInt(fmt.Sprint(bodyMap["logs_end_date"]), 10, 64)
+		if err != nil || end < since.Unix() {
+			t.Errorf("want logs_end_date after start, got %v", bodyMap["logs_end_date"])
+		}
+		// Include an entry from before the start date, as the API does
+		// for plans which don't support date filters
+		fmt.Fprintf(w, `{"stat": "ok", "monitors": [{"id": 1, "logs": [
+			{"type": 2, "datetime": %d, "duration": 60},
+			{"type": 1, "datetime": %d, "duration": 60},
+			{"type": 1, "datetime": %d, "duration": 60}
+		]}]}`, since.Add(2*time.Hour).Unix(), since.Add(time.Hour).Unix(), since.Add(-time.Hour).Unix())
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	logs, err := client.GetLogsSince(1, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MonitorLog{
+		{Type: LogTypeDown, Datetime: since.Add(time.Hour), Duration: time.Minute},
+		{Type: LogTypeUp, Datetime: since.Add(2 * time.Hour), Duration: time.Minute},
+	}
+	if !cmp.Equal(want, logs) {
+		t.Error(cmp.Diff(want, logs))
+	}
+}
+
+func TestNewResponseTimeStats(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	times := []ResponseTime{
+		// Outside the window
+		{Datetime: start.Add(-time.Minute), Value: time.Hour},
+	}
+	// 100 measurements of 1ms to 100ms, in reverse order
+	for i := 100; i >= 1; i-- {
+		times = append(times, ResponseTime{
+			Datetime: start.Add(time.Duration(i) * time.Minute),
+			Value:    time.Duration(i) * time.Millisecond,
+		})
+	}
+	end := start.Add(100 * time.Minute)
+	want := ResponseTimeStats{
+		Start:   start,
+		End:     end,
+		Count:   100,
+		Average: 50500 * time.Microsecond,
+		Min:     time.Millisecond,
+		Max:     100 * time.Millisecond,
+		P50:     50 * time.Millisecond,
+		P95:     95 * time.Millisecond,
+		P99:     99 * time.Millisecond,
+	}
+	got := NewResponseTimeStats(times, start, end)
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	empty := NewResponseTimeStats(times, end.Add(time.Minute), time.Time{})
+	if empty.Count != 0 || empty.Max != 0 {
+		t.Errorf("want zero stats for an empty window, got %v", empty)
+	}
+	unbounded := NewResponseTimeStats(times, time.Time{}, time.Time{})
+	if unbounded.Count != 101 || unbounded.Max != time.Hour {
+		t.Errorf("want all 101 measurements with zero start and end, got %v", unbounded)
+	}
+}
+
+func TestGetResponseTimeStats(t *testing.T) {
+	t.Parallel()
+	since := time.Now().Add(-time.Hour).Truncate(time.Second)
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		if bodyMap["response_times"] != "1" {
+			t.Errorf("want response_times 1, got %v", bodyMap["response_times"])
+		}
+		wantStart := strconv.FormatInt(since.Unix(), 10)
+		if bodyMap["response_times_start_date"] != wantStart {
+			t.Errorf("want response_times_start_date %s, got %v", wantStart, bodyMap["response_times_start_date"])
+		}
+		if _, ok := bodyMap["response_times_end_date"]; !ok {
+			t.Error("want response_times_end_date to be set")
+		}
+		fmt.Fprintf(w, `{"stat": "ok", "monitors": [{"id": 1, "response_times": [
+			{"datetime": %d, "value": 300},
+			{"datetime": %d, "value": "100"}
+		]}]}`, since.Add(time.Minute).Unix(), since.Add(2*time.Minute).Unix())
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	stats, err := client.GetResponseTimeStats(1, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != 2 || stats.Average != 200*time.Millisecond || stats.Min != 100*time.Millisecond || stats.P99 != 300*time.Millisecond {
+		t.Errorf("unexpected stats: %v", stats)
+	}
+}
+
+func TestUnmarshalUptimeRatios(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name        string
+		data        string
+		wantRatios  []float64
+		wantRanges  []float64
+		wantAllTime float64
+	}{
+		{
+			name:       "dash-separated lists",
+			data:       `{"custom_uptime_ratio": "100.000-99.981-98.5", "custom_uptime_ranges": "99.000-100.000"}`,
+			wantRatios: []float64{100, 99.981, 98.5},
+			wantRanges: []float64{99, 100},
+		},
+		{
+			name:        "single values",
+			data:        `{"custom_uptime_ratio": "99.900", "all_time_uptime_ratio": "99.95"}`,
+			wantRatios:  []float64{99.9},
+			wantAllTime: 99.95,
+		},
+		{
+			name:        "numbers",
+			data:        `{"custom_uptime_ratio": 100, "all_time_uptime_ratio": 99.5}`,
+			wantRatios:  []float64{100},
+			wantAllTime: 99.5,
+		},
+		{
+			name: "empty or missing",
+			data: `{"custom_uptime_ratio": "", "custom_uptime_ranges": null}`,
+		},
+	}
+	for _, tc := range tcs {
+		var m Monitor
+		if err := json.Unmarshal([]byte(tc.data), &m); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !cmp.Equal(tc.wantRatios, m.UptimeRatios) {
+			t.Errorf("%s: UptimeRatios: %s", tc.name, cmp.Diff(tc.wantRatios, m.UptimeRatios))
+		}
+		if !cmp.Equal(tc.wantRanges, m.UptimeRanges) {
+			t.Errorf("%s: UptimeRanges: %s", tc.name, cmp.Diff(tc.wantRanges, m.UptimeRanges))
+		}
+		if tc.wantAllTime != m.AllTimeUptimeRatio {
+			t.Errorf("%s: want AllTimeUptimeRatio %v, got %v", tc.name, tc.wantAllTime, m.AllTimeUptimeRatio)
+		}
+	}
+	var m Monitor
+	if err := json.Unmarshal([]byte(`{"custom_uptime_ratio": "100.000-bogus"}`), &m); err == nil {
+		t.Error("want error for invalid uptime ratio, got nil")
+	}
+}
+
+func TestUptimeRatioOptions(t *testing.T) {
+	t.Parallel()
+	start := time.Unix(1465440758, 0)
+	end := time.Unix(1466304758, 0)
+	req := getMonitorsRequest{}
+	newOptions([]Option{
+		WithUptimeRatios(7, 30),
+		WithUptimeRanges(TimeRange{Start: start, End: end}, TimeRange{Start: end, End: end.Add(time.Hour)}),
+		WithAllTimeUptimeRatio(),
+	}).apply(&req)
+	if req.UptimeRatios != "7-30" {
+		t.Errorf("want custom_uptime_ratios 7-30, got %q", req.UptimeRatios)
+	}
+	wantRanges := "1465440758_1466304758-1466304758_1466308358"
+	if req.UptimeRanges != wantRanges {
+		t.Errorf("want custom_uptime_ranges %q, got %q", wantRanges, req.UptimeRanges)
+	}
+	if req.AllTimeRatio != "1" {
+		t.Errorf("want all_time_uptime_ratio 1, got %q", req.AllTimeRatio)
+	}
+	data, err := json.Marshal(Monitor{FriendlyName: "x", UptimeRatios: []float64{100}, AllTimeUptimeRatio: 99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "uptime") {
+		t.Errorf("want uptime ratios omitted when marshaling, got %s", data)
+	}
+}
+
+func TestGetMonitorsWithUptimeBelow(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		if bodyMap["custom_uptime_ratios"] != "30" {
+			t.Errorf("want custom_uptime_ratios 30, got %v", bodyMap["custom_uptime_ratios"])
+		}
+		fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 4}, "monitors": [
+			{"id": 1, "custom_uptime_ratio": "100.000"},
+			{"id": 2, "custom_uptime_ratio": "99.899"},
+			{"id": 3, "custom_uptime_ratio": "99.900"},
+			{"id": 4, "custom_uptime_ratio": ""}
+		]}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	monitors, err := client.GetMonitorsWithUptimeBelow(30, 99.9, WithUptimeRatios(7, 365))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 1 || monitors[0].ID != 2 {
+		t.Fatalf("want only monitor 2, got %v", monitors)
+	}
+	if !cmp.Equal([]float64{99.899}, monitors[0].UptimeRatios) {
+		t.Errorf("want UptimeRatios [99.899], got %v", monitors[0].UptimeRatios)
+	}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	t.Parallel()
+	var calls int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := StatusDown
+		if atomic.AddInt32(&calls, 1) >= 3 {
+			status = StatusUp
+		}
+		fmt.Fprintf(w, `{"stat": "ok", "monitors": [{"id": 1, "status": %d}]}`, status)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	m, err := client.WaitForStatus(context.Background(), 1, StatusUp, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Status != StatusUp {
+		t.Errorf("want status Up, got %s", m.Status)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("want 3 polls, got %d", got)
+	}
+}
+
+func TestWaitForStatusTimesOut(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"stat": "ok", "monitors": [{"id": 1, "status": %d}]}`, StatusDown)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	m, err := client.WaitForStatus(ctx, 1, StatusUp, 5*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want DeadlineExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Down") {
+		t.Errorf("want error to mention last status Down, got %q", err)
+	}
+	if m.Status != StatusDown {
+		t.Errorf("want last seen status Down, got %s", m.Status)
+	}
+}
+
+func TestWaitForStatusRejectsNonPositiveInterval(t *testing.T) {
+	t.Parallel()
+	var calls int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		fmt.Fprintf(w, `{"stat": "ok", "monitors": [{"id": 1, "status": %d}]}`, StatusDown)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	for _, interval := range []time.Duration{0, -time.Second} {
+		_, err := client.WaitForStatus(context.Background(), 1, StatusUp, interval)
+		if err == nil {
+			t.Errorf("want error for poll interval %s, got nil", interval)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("want no requests, got %d", got)
+	}
+}
+
+func TestPauseMonitorFor(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name    string
+		d       time.Duration
+		cancel  bool
+		wantErr error
+	}{
+		{name: "resumes after duration", d: 10 * time.Millisecond},
+		{name: "resumes early when cancelled", d: time.Hour, cancel: true, wantErr: context.Canceled},
+	}
+	for _, tc := range tcs {
+		var mu sync.Mutex
+		statuses := []string{}
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bodyMap := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+				t.Fatal(err)
+			}
+			mu.Lock()
+			statuses = append(statuses, fmt.Sprint(bodyMap["status"]))
+			mu.Unlock()
+			fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 1}}`)
+		}))
+		client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+		ctx, cancel := context.WithCancel(context.Background())
+		if tc.cancel {
+			time.AfterFunc(10*time.Millisecond, cancel)
+		}
+		start := time.Now()
+		err := client.PauseMonitorFor(ctx, 1, tc.d)
+		cancel()
+		ts.Close()
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: want error %v, got %v", tc.name, tc.wantErr, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Minute {
+			t.Errorf("%s: took too long (%s)", tc.name, elapsed)
+		}
+		want := []string{"0", "1"}
+		if !cmp.Equal(want, statuses) {
+			t.Errorf("%s: want pause then resume, got %v", tc.name, statuses)
+		}
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	t.Parallel()
+	want := Snapshot{
+		Monitors: []Monitor{{
+			ID:            1,
+			FriendlyName:  "Example",
+			URL:           "https://example.com/",
+			Type:          TypeHTTP,
+			AlertContacts: []ContactID{"10"},
+			Interval:      5 * time.Minute,
+		}},
+		AlertContacts: []AlertContact{{
+			ID:           "10",
+			FriendlyName: "Ops",
+			Type:         AlertContactTypeEmail,
+			Value:        "ops@example.com",
+		}},
+		MWindows: []MWindow{
+			WeeklyWindow([]time.Weekday{time.Saturday}, time.Date(0, 1, 1, 2, 0, 0, 0, time.UTC), time.Hour),
+		},
+		PSPs: []PSP{{
+			ID:           20,
+			FriendlyName: "Status",
+			Monitors:     []MonitorID{1},
+		}},
+	}
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Snapshot
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("JSON: %s", cmp.Diff(want, got))
+	}
+	data, err = yaml.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "friendly_name: Example") {
+		t.Errorf("want YAML to use the same field names as JSON, got:\n%s", data)
+	}
+	got = Snapshot{}
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("YAML: %s", cmp.Diff(want, got))
+	}
+}
+
+func TestSnapshotFormat(t *testing.T) {
+	t.Parallel()
+	s := Snapshot{
+		Monitors: []Monitor{{
+			ID:              777749809,
+			FriendlyName:    "Example",
+			URL:             "https://example.com/",
+			Type:            TypeHTTP,
+			Interval:        5 * time.Minute,
+			Timeout:         30 * time.Second,
+			Status:          StatusUp,
+			AlertContacts:   []ContactID{"0993765"},
+			ContactSettings: map[ContactID]ContactSettings{"0993765": {Threshold: 10, Recurrence: 30}},
+		}},
+		AlertContacts: []AlertContact{{
+			ID:           "0993765",
+			FriendlyName: "Ops",
+			Type:         AlertContactTypeEmail,
+			Status:       2,
+			Value:        "ops@example.com",
+		}},
+		MWindows: []MWindow{
+			{
+				ID:           581,
+				FriendlyName: "Nightly backups",
+				Type:         MWindowTypeDaily,
+				StartTime:    time.Date(0, 1, 1, 2, 0, 0, 0, time.UTC),
+				Duration:     time.Hour,
+				Status:       1,
+			},
+			{
+				ID:           582,
+				FriendlyName: "Migration",
+				Type:         MWindowTypeOnce,
+				StartTime:    time.Date(2024, 6, 1, 22, 30, 0, 0, time.UTC),
+				Duration:     90 * time.Minute,
+				Status:       1,
+			},
+		},
+		PSPs: []PSP{{
+			ID:           2345679,
+			FriendlyName: "Status",
+			Monitors:     []MonitorID{777749809},
+			Status:       1,
+			StandardURL:  "https://stats.uptimerobot.com/xyz02",
+		}},
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("testdata/snapshot.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(string(want), string(data)+"\n") {
+		t.Errorf("JSON: %s", cmp.Diff(string(want), string(data)+"\n"))
+	}
+	data, err = yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err = os.ReadFile("testdata/snapshot.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(string(want), string(data)) {
+		t.Errorf("YAML: %s", cmp.Diff(string(want), string(data)))
+	}
+	data, err = json.Marshal(Snapshot{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEmpty := `{"monitors":[],"alert_contacts":[],"mwindows":[],"psps":[]}`
+	if wantEmpty != string(data) {
+		t.Errorf("want empty snapshot %s, got %s", wantEmpty, data)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		verb := strings.TrimPrefix(r.URL.Path, "/v2/")
+		switch verb {
+		case "getAlertContacts":
+			fmt.Fprint(w, `{"stat": "ok", "offset": 0, "limit": 50, "total": 1, "alert_contacts": [
+				{"id": "100", "friendly_name": "Ops", "type": 2, "value": "ops@example.com"}
+			]}`)
+			return
+		case "getMonitors":
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 0}, "monitors": []}`)
+			return
+		case "getMWindows":
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 1}, "mwindows": [
+				{"id": 300, "friendly_name": "Existing", "type": 2, "start_time": "02:00", "duration": 60}
+			]}`)
+			return
+		case "getPSPs":
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 0}, "psps": []}`)
+			return
+		case "newAlertContact":
+			fmt.Fprint(w, `{"stat": "ok", "alertcontact": {"id": "101"}}`)
+			requests = append(requests, fmt.Sprintf("%s %v", verb, bodyMap["value"]))
+		case "newMonitor":
+			fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 200}}`)
+			requests = append(requests, fmt.Sprintf("%s %v %v", verb, bodyMap["url"], bodyMap["alert_contacts"]))
+		case "newMWindow":
+			fmt.Fprint(w, `{"stat": "ok", "mwindow": {"id": 301}}`)
+			requests = append(requests, fmt.Sprintf("%s %v", verb, bodyMap["friendly_name"]))
+		case "newPSP":
+			fmt.Fprint(w, `{"stat": "ok", "psp": {"id": 400}}`)
+			requests = append(requests, fmt.Sprintf("%s %v", verb, bodyMap["monitors"]))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	s := Snapshot{
+		Monitors: []Monitor{{
+			ID:            1,
+			FriendlyName:  "Example",
+			URL:           "https://example.com/",
+			Type:          TypeHTTP,
+			AlertContacts: []ContactID{"10", "11"},
+		}},
+		AlertContacts: []AlertContact{
+			{ID: "10", FriendlyName: "Ops", Type: AlertContactTypeEmail, Value: "ops@example.com"},
+			{ID: "11", FriendlyName: "Hook", Type: AlertContactTypeWebhook, Value: "https://hooks.example.com/"},
+		},
+		MWindows: []MWindow{
+			{FriendlyName: "Existing", Type: MWindowTypeDaily, Duration: time.Hour},
+			{FriendlyName: "New", Type: MWindowTypeDaily, Duration: time.Hour},
+		},
+		PSPs: []PSP{{FriendlyName: "Status", Monitors: []MonitorID{1}}},
+	}
+	report, err := client.Restore(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRequests := []string{
+		"newAlertContact https://hooks.example.com/",
+		"newMonitor https://example.com/ 100_0_0-101_0_0",
+		"newMWindow New",
+		"newPSP 200",
+	}
+	if !cmp.Equal(wantRequests, requests) {
+		t.Error(cmp.Diff(wantRequests, requests))
+	}
+	if len(report.AlertContacts) != 1 || report.AlertContacts[0].ID != "101" {
+		t.Errorf("want one new alert contact with ID 101, got %v", report.AlertContacts)
+	}
+	if len(report.Monitors.Created) != 1 || report.Monitors.Created[0].ID != 200 {
+		t.Errorf("want one new monitor with ID 200, got %v", report.Monitors.Created)
+	}
+	if len(report.MWindows) != 1 || report.MWindows[0].ID != 301 {
+		t.Errorf("want one new maintenance window with ID 301, got %v", report.MWindows)
+	}
+	if len(report.PSPs) != 1 || report.PSPs[0].ID != 400 {
+		t.Errorf("want one new status page with ID 400, got %v", report.PSPs)
+	}
+}
+
+func TestInferMonitorFromURL(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		url  string
+		want Monitor
+	}{
+		{
+			url:  "https://example.com/health",
+			want: Monitor{FriendlyName: "example.com", URL: "https://example.com/health", Type: TypeHTTP, Port: 443},
+		},
+		{
+			url:  "http://example.com:8080/",
+			want: Monitor{FriendlyName: "example.com", URL: "http://example.com:8080/", Type: TypeHTTP, Port: 8080},
+		},
+		{
+			url:  "https://example.com:8443/health",
+			want: Monitor{FriendlyName: "example.com", URL: "https://example.com:8443/health", Type: TypeHTTP, Port: 8443},
+		},
+		{
+			url:  "http://example.com/",
+			want: Monitor{FriendlyName: "example.com", URL: "http://example.com/", Type: TypeHTTP, Port: 80},
+		},
+		{
+			url:  "ftp://files.example.com",
+			want: Monitor{FriendlyName: "files.example.com", URL: "files.example.com", Type: TypePort, SubType: SubTypeFTP},
+		},
+		{
+			url:  "SMTP://mail.example.com:2525",
+			want: Monitor{FriendlyName: "mail.example.com", URL: "mail.example.com", Type: TypePort, SubType: SubTypeCustomPort, Port: 2525},
+		},
+		{
+			url:  "imap://mail.example.com:143",
+			want: Monitor{FriendlyName: "mail.example.com", URL: "mail.example.com", Type: TypePort, SubType: SubTypeIMAP},
+		},
+		{
+			url:  "example.com:8443",
+			want: Monitor{FriendlyName: "example.com", URL: "example.com", Type: TypePort, SubType: SubTypeCustomPort, Port: 8443},
+		},
+		{
+			url:  "example.com:443",
+			want: Monitor{FriendlyName: "example.com", URL: "example.com", Type: TypePort, SubType: SubTypeHTTPS},
+		},
+		{
+			url:  "[2001:db8::1]:110",
+			want: Monitor{FriendlyName: "2001:db8::1", URL: "2001:db8::1", Type: TypePort, SubType: SubTypePOP3},
+		},
+		{
+			url:  "192.0.2.1",
+			want: Monitor{FriendlyName: "192.0.2.1", URL: "192.0.2.1", Type: TypePing},
 		},
 	}
-	got, err := client.AllMonitors()
+	for _, tc := range tcs {
+		got, err := InferMonitorFromURL(tc.url)
+		if err != nil {
+			t.Errorf("%s: %v", tc.url, err)
+			continue
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("%s: %s", tc.url, cmp.Diff(tc.want, got))
+		}
+	}
+	for _, bad := range []string{"", "gopher://example.com", "example.com/path", "example.com:99999", "https://"} {
+		if _, err := InferMonitorFromURL(bad); err == nil {
+			t.Errorf("%q: want error, got nil", bad)
+		}
+	}
+}
+
+func TestEnsureMonitors(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		switch strings.TrimPrefix(r.URL.Path, "/v2/") {
+		case "getMonitors":
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 4}, "monitors": [
+				{"id": 1, "friendly_name": "A", "url": "https://a.example.com/", "type": 1},
+				{"id": 2, "friendly_name": "B", "url": "https://b.example.com/", "type": 1},
+				{"id": 3, "friendly_name": "C", "url": "https://c.example.com/", "type": 1},
+				{"id": 5, "friendly_name": "F", "url": "https://f.example.com/", "type": 1}
+			]}`)
+		case "newMonitor":
+			if bodyMap["url"] == "https://e.example.com/" {
+				fmt.Fprint(w, `{"stat": "fail", "error": {"type": "invalid_parameter", "message": "bad monitor"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 4}}`)
+		case "editMonitor":
+			if bodyMap["id"] == "5" {
+				fmt.Fprint(w, `{"stat": "fail", "error": {"type": "invalid_parameter", "message": "bad monitor"}}`)
+				return
+			}
+			fmt.Fprintf(w, `{"stat": "ok", "monitor": {"id": %s}}`, bodyMap["id"])
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	monitors := []Monitor{
+		{FriendlyName: "A", URL: "https://a.example.com/", Type: TypeHTTP},
+		{FriendlyName: "B renamed", URL: "https://b.example.com/", Type: TypeHTTP},
+		{FriendlyName: "D", URL: "https://d.example.com/", Type: TypeHTTP},
+		{FriendlyName: "E", URL: "https://e.example.com/", Type: TypeHTTP},
+		{FriendlyName: "F renamed", URL: "https://f.example.com/", Type: TypeHTTP},
+	}
+	results, err := client.EnsureMonitors(monitors)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	if len(results) != len(monitors) {
+		t.Fatalf("want %d results, got %d", len(monitors), len(results))
+	}
+	want := []struct {
+		ID     MonitorID
+		Action EnsureAction
+	}{
+		{1, EnsureExisting},
+		{2, EnsureUpdated},
+		{4, EnsureCreated},
+		{0, EnsureFailed},
+		{5, EnsureFailed},
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Monitor.URL != monitors[i].URL || r.ID != w.ID || r.Action != w.Action {
+			t.Errorf("result %d: want ID %d %s, got %d %s (%v)", i, w.ID, w.Action, r.ID, r.Action, r.Err)
+		}
+		if (r.Err != nil) != (w.Action == EnsureFailed) {
+			t.Errorf("result %d: unexpected error %v", i, r.Err)
+		}
+	}
+}
+
+func TestSyncMonitorsWithManagedBy(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyMap := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&bodyMap); err != nil {
+			t.Fatal(err)
+		}
+		verb := strings.TrimPrefix(r.URL.Path, "/v2/")
+		switch verb {
+		case "getMonitors":
+			fmt.Fprint(w, `{"stat": "ok", "pagination": {"offset": 0, "limit": 50, "total": 5}, "monitors": [
+				{"id": 1, "friendly_name": "A [managed-by:infra]", "url": "https://a.example.com/", "type": 1},
+				{"id": 2, "friendly_name": "B", "url": "https://b.example.com/", "type": 1},
+				{"id": 3, "friendly_name": "C", "url": "https://c.example.com/", "type": 1},
+				{"id": 4, "friendly_name": "E [managed-by:infra]", "url": "https://e.example.com/", "type": 1},
+				{"id": 5, "friendly_name": "F", "url": "https://f.example.com/", "type": 1}
+			]}`)
+			return
+		case "newMonitor":
+			fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 6}}`)
+			requests = append(requests, fmt.Sprintf("%s %v", verb, bodyMap["friendly_name"]))
+			return
+		default:
+			fmt.Fprintf(w, `{"stat": "ok", "monitor": {"id": %s}}`, bodyMap["id"])
+		}
+		requests = append(requests, fmt.Sprintf("%s %v", verb, bodyMap["id"]))
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	desired := []Monitor{
+		{FriendlyName: "A", URL: "https://a.example.com/", Type: TypeHTTP},
+		{FriendlyName: "B", URL: "https://b.example.com/", Type: TypeHTTP},
+		{FriendlyName: "D", URL: "https://d.example.com/", Type: TypeHTTP},
+	}
+	report, err := client.SyncMonitors(desired, WithPrune(), WithManagedBy("infra"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A is unchanged, hand-made B is left alone, D is created with the
+	// marker, managed E is pruned, and hand-made C and F are never deleted
+	wantRequests := []string{"newMonitor D [managed-by:infra]", "deleteMonitor 4"}
+	if !cmp.Equal(wantRequests, requests) {
+		t.Error(cmp.Diff(wantRequests, requests))
+	}
+	if len(report.Unchanged) != 1 || report.Unchanged[0].ID != 1 {
+		t.Errorf("want monitor 1 unchanged, got %v", report.Unchanged)
+	}
+	if len(report.Unmanaged) != 1 || report.Unmanaged[0].ID != 2 {
+		t.Errorf("want monitor 2 unmanaged, got %v", report.Unmanaged)
+	}
+	if desired[2].FriendlyName != "D" {
+		t.Errorf("want desired monitors unchanged, got name %q", desired[2].FriendlyName)
+	}
+	if !IsManagedBy(report.Created[0], "infra") || IsManagedBy(report.Created[0], "other") {
+		t.Errorf("want created monitor managed by infra only, got %q", report.Created[0].FriendlyName)
+	}
+}
+
+func TestWithPlanChecks(t *testing.T) {
+	t.Parallel()
+	var accountCalls, created int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/v2/") {
+		case "getAccountDetails":
+			atomic.AddInt32(&accountCalls, 1)
+			fmt.Fprint(w, `{"stat": "ok", "account": {"monitor_limit": 3, "monitor_interval": 5, "up_monitors": 1, "down_monitors": 0, "paused_monitors": 0}}`)
+		case "newMonitor":
+			atomic.AddInt32(&created, 1)
+			fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 1}}`)
+		case "deleteMonitor":
+			fmt.Fprint(w, `{"stat": "ok", "monitor": {"id": 1}}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL), WithPlanChecks())
+	m := Monitor{FriendlyName: "A", URL: "https://a.example.com/", Type: TypeHTTP, Interval: time.Minute}
+	_, err := client.CreateMonitor(m)
+	var ple PlanLimitError
+	if !errors.As(err, &ple) {
+		t.Fatalf("want PlanLimitError, got %v", err)
+	}
+	if err.Error() != "interval 60s below plan minimum 300s" {
+		t.Errorf("unexpected message %q", err)
+	}
+	m.Interval = 5 * time.Minute
+	if _, err := client.CreateMonitor(m); err != nil {
+		t.Fatal(err)
+	}
+	interval := 10 * time.Minute
+	if _, err := client.CreateMonitorWithParams(CreateMonitorParams{FriendlyName: "B", URL: "https://b.example.com/", Type: TypeHTTP, Interval: &interval}); err != nil {
+		t.Fatal(err)
+	}
+	// The account now has 3 monitors, which is the limit
+	if _, err := client.CreateMonitor(m); !errors.As(err, &ple) {
+		t.Fatalf("want PlanLimitError for monitor limit, got %v", err)
+	}
+	if err := client.DeleteMonitor(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateMonitor(m); err != nil {
+		t.Errorf("want monitor created after deleting one, got %v", err)
+	}
+	if got := atomic.LoadInt32(&accountCalls); got != 1 {
+		t.Errorf("want account details fetched once, got %d", got)
+	}
+	if got := atomic.LoadInt32(&created); got != 3 {
+		t.Errorf("want 3 monitors created, got %d", got)
+	}
+}
+
+func TestGetMonitorReturnsErrMonitorNotFound(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stat": "ok", "monitors": []}`)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	_, err := client.GetMonitor(42)
+	if !errors.Is(err, ErrMonitorNotFound) {
+		t.Fatalf("want ErrMonitorNotFound, got %v", err)
+	}
+	var nf NotFoundError
+	if !errors.As(err, &nf) || nf.ID != "42" {
+		t.Errorf("want NotFoundError with ID 42, got %#v", err)
+	}
+	if err.Error() != "monitor 42 not found" {
+		t.Errorf("unexpected message %q", err)
+	}
+	if errors.Is(NotFoundError{Resource: "status page", ID: "42"}, ErrMonitorNotFound) {
+		t.Error("want a missing status page not to match ErrMonitorNotFound")
+	}
+}
+
+func TestParseIDs(t *testing.T) {
+	t.Parallel()
+	m, err := ParseMonitorID("780689017")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != 780689017 || m.String() != "780689017" {
+		t.Errorf("want monitor ID 780689017, got %v", m)
+	}
+	w, err := ParseMWindowID("1234")
+	if err != nil || w != 1234 {
+		t.Errorf("want maintenance window ID 1234, got %v (%v)", w, err)
+	}
+	p, err := ParsePSPID("2345678")
+	if err != nil || p != 2345678 {
+		t.Errorf("want status page ID 2345678, got %v (%v)", p, err)
+	}
+	c, err := ParseContactID("0993765")
+	if err != nil || c != "0993765" {
+		t.Errorf("want alert contact ID 0993765 with its leading zero, got %v (%v)", c, err)
+	}
+	for _, s := range []string{"", "0", "-1", "abc", "12x"} {
+		if _, err := ParseMonitorID(s); err == nil {
+			t.Errorf("want error parsing monitor ID %q", s)
+		}
+	}
+	for _, s := range []string{"", "-1", "abc", "12 "} {
+		if _, err := ParseContactID(s); err == nil {
+			t.Errorf("want error parsing alert contact ID %q", s)
+		}
+	}
+	if got := joinIDs([]MonitorID{1, 22, 333}); got != "1-22-333" {
+		t.Errorf("want 1-22-333, got %q", got)
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	t.Parallel()
+	want := Webhook{
+		MonitorID:             777749809,
+		MonitorURL:            "https://example.com/",
+		MonitorFriendlyName:   "Example",
+		AlertType:             AlertTypeDown,
+		AlertTypeFriendlyName: "Down",
+		AlertDetails:          "Connection Timeout",
+		AlertDuration:         90 * time.Second,
+		AlertDateTime:         time.Unix(1700000000, 0),
+	}
+	form := url.Values{
+		"monitorID":             {"777749809"},
+		"monitorURL":            {"https://example.com/"},
+		"monitorFriendlyName":   {"Example"},
+		"alertType":             {"1"},
+		"alertTypeFriendlyName": {"Down"},
+		"alertDetails":          {"Connection Timeout"},
+		"alertDuration":         {"90"},
+		"alertDateTime":         {"1700000000"},
+	}
+	query := httptest.NewRequest(http.MethodGet, "/alert?"+form.Encode(), nil)
+	post := httptest.NewRequest(http.MethodPost, "/alert", strings.NewReader(form.Encode()))
+	post.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body := `{"monitorID": 777749809, "monitorURL": "https://example.com/", "monitorFriendlyName": "Example", "alertType": "1", "alertTypeFriendlyName": "Down", "alertDetails": "Connection Timeout", "alertDuration": "90", "alertDateTime": 1700000000}`
+	jsonPost := httptest.NewRequest(http.MethodPost, "/alert", strings.NewReader(body))
+	jsonPost.Header.Set("Content-Type", "application/json; charset=utf-8")
+	for name, r := range map[string]*http.Request{"query": query, "form": post, "JSON": jsonPost} {
+		got, err := ParseWebhook(r)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !cmp.Equal(want, got) {
+			t.Errorf("%s: %s", name, cmp.Diff(want, got))
+		}
+	}
+	for name, target := range map[string]string{
+		"no monitor ID":     "/alert?alertType=1",
+		"invalid alertType": "/alert?monitorID=1&alertType=down",
+	} {
+		if _, err := ParseWebhook(httptest.NewRequest(http.MethodGet, target, nil)); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+}
+
+func TestGroupByDomain(t *testing.T) {
+	t.Parallel()
+	monitors := []Monitor{
+		{ID: 1, URL: "https://www.example.com/"},
+		{ID: 2, URL: "https://shop.example.co.uk/basket"},
+		{ID: 3, URL: "api.example.com:8443"},
+		{ID: 4, URL: "example.co.uk"},
+		{ID: 5, URL: "192.0.2.1"},
+		{ID: 6, URL: "http://EXAMPLE.com."},
+		{ID: 7, URL: "localhost"},
+		{ID: 8},
+	}
+	want := map[string][]MonitorID{
+		"example.com":   {1, 3, 6},
+		"example.co.uk": {2, 4},
+		"192.0.2.1":     {5},
+		"localhost":     {7},
+		"":              {8},
+	}
+	got := map[string][]MonitorID{}
+	for domain, ms := range GroupByDomain(monitors) {
+		for _, m := range ms {
+			got[domain] = append(got[domain], m.ID)
+		}
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestRenderCustomTemplate(t *testing.T) {
+	t.Parallel()
+	m := Monitor{
+		ID:           777749809,
+		FriendlyName: "Example",
+		URL:          "https://example.com/",
+		Status:       StatusDown,
+		Interval:     5 * time.Minute,
+	}
+	got, err := m.Render("{{ .FriendlyName }} is {{ .FriendlyStatus }} (every {{ duration .Interval }})")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Example is Down (every 5m)"
+	if got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if _, err := m.Render("{{ .FriendlyName "); err == nil {
+		t.Error("want error for invalid template")
+	}
+	if _, err := m.Render("{{ .NoSuchField }}"); err == nil {
+		t.Error("want error for template which fails")
+	}
+	if m.String() != renderString(MonitorTemplate, m) {
+		t.Error("want String to render MonitorTemplate")
+	}
+	if got := renderString("{{ .NoSuchField }}", m); !strings.HasPrefix(got, "%!(executing template") {
+		t.Errorf("want error description from renderString, got %q", got)
+	}
+}
+
+func TestTolerantDecoding(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{
+		"stat": "ok",
+		"pagination": {"offset": "0", "limit": "50", "total": ""},
+		"timezone": "",
+		"account": {"email": "j.random@example.com", "monitor_limit": "50", "monitor_interval": "5", "up_monitors": "", "down_monitors": 1, "paused_monitors": null},
+		"monitors": [{
+			"id": "777749809",
+			"type": "1",
+			"status": "",
+			"interval": "",
+			"logs": [{"type": "", "datetime": "1463540297", "duration": ""}],
+			"response_times": [{"datetime": "1463540297", "value": ""}],
+			"alert_contacts": [{"id": 993765}, {"id": null}]
+		}],
+		"alert_contacts": [{"id": 2403924, "type": "", "status": "2"}],
+		"mwindows": [{"id": "1234", "type": "", "duration": "", "status": "1"}],
+		"psps": [{"id": "", "sort": "", "status": "1", "monitors": "777749809-777712827"}]
+	}`)
+	r := Response{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Pagination.Limit != 50 || r.Pagination.Total != 0 || r.Timezone != 0 {
+		t.Errorf("unexpected pagination %+v, timezone %d", r.Pagination, r.Timezone)
+	}
+	if r.Account.MonitorLimit != 50 || r.Account.MonitorInterval != 5*time.Minute || r.Account.DownMonitors != 1 {
+		t.Errorf("unexpected account %+v", r.Account)
+	}
+	m := r.Monitors[0]
+	if m.ID != 777749809 || m.Type != TypeHTTP || m.Status != 0 || m.Interval != 0 {
+		t.Errorf("unexpected monitor %+v", m)
+	}
+	if m.Logs[0].Type != 0 || m.Logs[0].Datetime != time.Unix(1463540297, 0).UTC() || m.ResponseTimes[0].Value != 0 {
+		t.Errorf("unexpected logs %+v or response times %+v", m.Logs, m.ResponseTimes)
+	}
+	if !cmp.Equal([]ContactID{"993765"}, m.AlertContacts) {
+		t.Error(cmp.Diff([]ContactID{"993765"}, m.AlertContacts))
+	}
+	if a := r.AlertContacts[0]; a.ID != "2403924" || a.Type != 0 || a.Status != 2 {
+		t.Errorf("unexpected alert contact %+v", a)
+	}
+	if w := r.MWindows[0]; w.ID != 1234 || w.Type != 0 || w.Duration != 0 || w.Status != 1 {
+		t.Errorf("unexpected maintenance window %+v", w)
+	}
+	if p := r.PSPs[0]; p.ID != 0 || p.Status != 1 || !cmp.Equal([]MonitorID{777749809, 777712827}, p.Monitors) {
+		t.Errorf("unexpected status page %+v", p)
+	}
+}
+
+// FuzzUnmarshalResponse checks that decoding arbitrary API responses never
+// panics, and that anything which decodes can be encoded again.
+func FuzzUnmarshalResponse(f *testing.F) {
+	files, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte(`{"stat": "ok", "monitors": [{"id": "", "port": "", "sub_type": "", "interval": "", "status": ""}]}`))
+	f.Add([]byte(`{"stat": "ok", "psps": [{"monitors": "1-2"}], "mwindows": [{"start_time": "02:00"}]}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := Response{}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return
+		}
+		for _, m := range append(r.Monitors, r.Monitor) {
+			if _, err := json.Marshal(m); err != nil {
+				t.Errorf("decoded monitor %+v doesn't encode: %v", m, err)
+			}
+			_ = m.String()
+		}
+		for _, a := range r.AlertContacts {
+			_ = a.String()
+		}
+		for _, w := range r.MWindows {
+			_ = w.String()
+		}
+		for _, p := range r.PSPs {
+			_ = p.String()
+		}
+		_ = r.Account.String()
+	})
+}
+
+// FuzzFlexInt checks that FlexInt decodes any integer in each of the API's
+// encodings, and never panics on other input.
+func FuzzFlexInt(f *testing.F) {
+	for _, s := range []string{`80`, `"80"`, `""`, `null`, `"-1"`, `80.5`, `true`, `"eighty"`} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var i FlexInt
+		if err := json.Unmarshal(data, &i); err != nil {
+			return
+		}
+		quoted := []byte(strconv.Quote(strconv.Itoa(int(i))))
+		var j FlexInt
+		if err := json.Unmarshal(quoted, &j); err != nil || i != j {
+			t.Errorf("%s decodes as %d, but %s decodes as %d (%v)", data, i, quoted, j, err)
+		}
+	})
+}
+
+func TestAccountIsCached(t *testing.T) {
+	t.Parallel()
+	var calls int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		fmt.Fprintf(w, `{"stat": "ok", "account": {"email": "j.random@example.com", "monitor_limit": 50, "up_monitors": %d}}`, n)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	for i := 0; i < 2; i++ {
+		a, err := client.Account()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.UpMonitors != 1 {
+			t.Errorf("want cached details from the first request, got %+v", a)
+		}
+	}
+	a, err := client.RefreshAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.UpMonitors != 2 {
+		t.Errorf("want refreshed details, got %+v", a)
+	}
+	if a, _ := client.Account(); a.UpMonitors != 2 {
+		t.Errorf("want refreshed details to be cached, got %+v", a)
+	}
+	other := client.WithKey("other")
+	if a, _ := other.Account(); a.UpMonitors != 3 {
+		t.Errorf("want a client with a different key to fetch its own details, got %+v", a)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("want 3 requests, got %d", got)
+	}
+}
+
+func TestDecodeResponseStreamsSameAsUnmarshal(t *testing.T) {
+	t.Parallel()
+	files, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Response{}
+		if err := json.Unmarshal(data, &want); err != nil {
+			continue
+		}
+		got := Response{}
+		if err := decodeResponse(json.NewDecoder(bytes.NewReader(data)), &got); err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if !cmp.Equal(want, got, cmp.AllowUnexported(Response{})) {
+			t.Errorf("%s: %s", path, cmp.Diff(want, got, cmp.AllowUnexported(Response{})))
+		}
+	}
+}
+
+func TestAPIErrorIncludesStartOfInvalidResponse(t *testing.T) {
+	t.Parallel()
+	body := `{"stat": "ok", "monitors": [` + strings.Repeat(" ", 2*maxErrorBodyLength) + `}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	_, err := client.AllMonitors()
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("want APIError, got %v", err)
+	}
+	want := body[:maxErrorBodyLength] + "..."
+	if apiErr.Body != want {
+		t.Errorf("want body %q, got %q", want, apiErr.Body)
+	}
+}
+
+func TestSearchMonitorsAllDeduplicatesResults(t *testing.T) {
+	t.Parallel()
+	results := map[string]string{
+		"api": `[{"id": 1, "url": "https://api.example.com"}, {"id": 2, "url": "https://api.example.org"}]`,
+		"org": `[{"id": 2, "url": "https://api.example.org"}, {"id": 3, "url": "https://www.example.org"}]`,
+		"xyz": `[]`,
+	}
+	var inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		req := struct {
+			Search string `json:"search"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		fmt.Fprintf(w, `{"stat": "ok", "monitors": %s}`, results[req.Search])
+	}))
+	defer ts.Close()
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	monitors, err := client.SearchMonitorsAll([]string{"api", "org", "xyz", "api", "org", "xyz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MonitorID{1, 2, 3}
+	got := []MonitorID{}
+	for _, m := range monitors {
+		got = append(got, m.ID)
 	}
 	if !cmp.Equal(want, got) {
 		t.Error(cmp.Diff(want, got))
 	}
+	if maxInFlight > maxSearchWorkers {
+		t.Errorf("want at most %d concurrent searches, got %d", maxSearchWorkers, maxInFlight)
+	}
+}
+
+func TestSearchMonitorsAllReturnsErrorForFailedTerm(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	_, err := client.SearchMonitorsAll([]string{"api"})
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("want APIError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"api"`) {
+		t.Errorf("want error to mention the search term, got %v", err)
+	}
 }
 
-func TestGetMonitorsBySearch(t *testing.T) {
+func TestMonitorsFromResumesAfterError(t *testing.T) {
 	t.Parallel()
-	client := New("dummy")
-	ts := cannedResponseServer(t, "testdata/getMonitorsBySearch.json")
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		req := struct {
+			Offset string `json:"offset"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		offset, _ := strconv.Atoi(req.Offset)
+		monitors := []string{}
+		for i := offset; i < offset+2 && i < 5; i++ {
+			monitors = append(monitors, fmt.Sprintf(`{"id": %d}`, i+1))
+		}
+		fmt.Fprintf(w, `{"stat": "ok", "pagination": {"offset": %d, "limit": 2, "total": 5}, "monitors": [%s]}`,
+			offset, strings.Join(monitors, ","))
+	}))
 	defer ts.Close()
-	client.HTTPClient = ts.Client()
-	client.URL = ts.URL
-	want := []Monitor{
-		{
-			ID:           777712827,
-			FriendlyName: "My Web Page",
-			URL:          "http://mywebpage.com/",
-			Type:         TypeHTTP,
-			Status:       StatusUp,
-		},
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()), WithPageSize(2))
+	got := []MonitorID{}
+	visit := func(m Monitor) bool {
+		got = append(got, m.ID)
+		return true
 	}
-	got, err := client.SearchMonitors("My Web Page")
+	offset, err := client.MonitorsFrom(0, visit)
+	if err == nil {
+		t.Fatal("want error from failed page, got nil")
+	}
+	if offset != 2 {
+		t.Fatalf("want resume offset 2, got %d", offset)
+	}
+	offset, err = client.MonitorsFrom(offset, visit)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	if offset != 5 {
+		t.Errorf("want final offset 5, got %d", offset)
 	}
+	want := []MonitorID{1, 2, 3, 4, 5}
 	if !cmp.Equal(want, got) {
 		t.Error(cmp.Diff(want, got))
 	}
 }
 
-func TestPauseMonitor(t *testing.T) {
+func TestMonitorsFromReturnsOffsetAfterLastVisitedWhenStopped(t *testing.T) {
 	t.Parallel()
-	client := New("dummy")
-	ts := cannedResponseServer(t, "testdata/pauseMonitor.json")
+	ts := cannedResponseServer(t, "testdata/getMonitors.json")
 	defer ts.Close()
-	client.HTTPClient = ts.Client()
-	client.URL = ts.URL
-	want := Monitor{
-		ID: 677810870,
-	}
-	got, err := client.PauseMonitor(want)
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	offset, err := client.MonitorsFrom(0, func(Monitor) bool {
+		return false
+	})
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if !cmp.Equal(want, got) {
-		t.Error(cmp.Diff(want, got))
+	if offset != 1 {
+		t.Errorf("want offset 1, got %d", offset)
 	}
 }
 
-func TestStartMonitor(t *testing.T) {
+func TestCallOptionsOverrideClientSettings(t *testing.T) {
 	t.Parallel()
-	client := New("dummy")
-	ts := cannedResponseServer(t, "testdata/startMonitor.json")
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
 	defer ts.Close()
-	client.HTTPClient = ts.Client()
-	client.URL = ts.URL
-	want := Monitor{
-		ID: 677810870,
+	clientDebug := &bytes.Buffer{}
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()), WithRetries(3), WithDebugWriter(clientDebug))
+	callDebug := &bytes.Buffer{}
+	_, err := client.AllMonitors(WithCallRetries(0), WithCallDebug(callDebug))
+	var rl RateLimitError
+	if !errors.As(err, &rl) {
+		t.Fatalf("want RateLimitError, got %v", err)
 	}
-	got, err := client.StartMonitor(want)
-	if err != nil {
-		t.Error(err)
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("want 1 request with no retries, got %d", n)
 	}
-	if !cmp.Equal(want, got) {
-		t.Error(cmp.Diff(want, got))
+	if clientDebug.Len() != 0 {
+		t.Errorf("want no output to client's debug writer, got %q", clientDebug)
+	}
+	if !strings.Contains(callDebug.String(), "getMonitors") {
+		t.Errorf("want call's debug output to include request, got %q", callDebug)
 	}
 }
 
-func TestEnsure(t *testing.T) {
+func TestWithCallTimeoutOverridesClientTimeout(t *testing.T) {
 	t.Parallel()
-	client := New("dummy")
-	ts := cannedResponseServer(t, "testdata/ensure.json")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		io.WriteString(w, `{"stat": "ok", "monitors": []}`)
+	}))
 	defer ts.Close()
-	client.HTTPClient = ts.Client()
-	client.URL = ts.URL
-	mon := Monitor{
-		ID:           777712827,
-		FriendlyName: "My Web Page",
-		URL:          "http://mywebpage.com/",
-		Type:         TypeHTTP,
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()), WithTimeout(10*time.Millisecond))
+	if _, err := client.AllMonitors(); err == nil {
+		t.Fatal("want timeout error with client's timeout, got nil")
 	}
-	// The client will do a SearchMonitors and get a canned response from
-	// the test server containing no matches. It will now try to create the
-	// monitor, and the test server will just respond with an empty body and
-	// OK. The resulting monitor will have an ID of 0.
-	want := int64(0)
-	got, err := client.EnsureMonitor(mon)
-	if err != nil {
-		t.Error(err)
+	if _, err := client.AllMonitors(WithCallTimeout(5 * time.Second)); err != nil {
+		t.Errorf("want no error with longer call timeout, got %v", err)
 	}
-	if !cmp.Equal(want, got) {
-		t.Error(cmp.Diff(want, got))
+}
+
+func TestErrorBudget(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	slo := MonthlySLO(99.9, start.Add(10*24*time.Hour))
+	if !slo.Start.Equal(start) || !slo.End.Equal(start.AddDate(0, 1, 0)) {
+		t.Fatalf("unexpected monthly window %s to %s", slo.Start, slo.End)
+	}
+	// 30 days at 99.9% allows 43m12s of downtime
+	now := start.Add(15 * 24 * time.Hour)
+	logs := []MonitorLog{
+		{Type: LogTypeDown, Datetime: start.Add(-time.Hour), Duration: 90 * time.Minute},
+		{Type: LogTypeUp, Datetime: start.Add(30 * time.Minute)},
+		{Type: LogTypeDown, Datetime: start.Add(24 * time.Hour), Duration: 13 * time.Minute},
+		{Type: LogTypePaused, Datetime: start.Add(48 * time.Hour), Duration: time.Hour},
+		{Type: LogTypeDown, Datetime: now.Add(-5 * time.Minute), Duration: 10 * time.Minute},
+	}
+	got := slo.BudgetFromLogs(logs, now)
+	want := ErrorBudget{
+		SLO:       slo,
+		Now:       now,
+		Elapsed:   15 * 24 * time.Hour,
+		Budget:    43*time.Minute + 12*time.Second,
+		Downtime:  48 * time.Minute,
+		Remaining: -4*time.Minute - 48*time.Second,
+		BurnRate:  float64(48*time.Minute) / float64(15*24*time.Hour) / 0.001,
+	}
+	approx := cmp.Options{
+		cmpopts.EquateApprox(0, 1e-9),
+		cmp.Comparer(func(x, y time.Duration) bool {
+			return (x - y).Abs() < time.Millisecond
+		}),
+	}
+	if !cmp.Equal(want, got, approx) {
+		t.Error(cmp.Diff(want, got, approx))
+	}
+	fromUptime := slo.BudgetFromUptime(99.95, now)
+	if fromUptime.Downtime.Round(time.Second) != 10*time.Minute+48*time.Second {
+		t.Errorf("want downtime 10m48s from uptime, got %s", fromUptime.Downtime)
+	}
+	if fromUptime.BurnRate < 0.49 || fromUptime.BurnRate > 0.51 {
+		t.Errorf("want burn rate 0.5 from uptime, got %f", fromUptime.BurnRate)
+	}
+	strict := SLO{Target: 100, Start: slo.Start, End: slo.End}
+	if b := strict.Budget(time.Minute, now); !math.IsInf(b.BurnRate, 1) {
+		t.Errorf("want infinite burn rate for 100%% target, got %f", b.BurnRate)
 	}
 }
 
-func TestDeleteMonitor(t *testing.T) {
+func TestGetErrorBudgetCountsDowntimeStartingBeforeWindow(t *testing.T) {
 	t.Parallel()
-	client := New("dummy")
-	ts := cannedResponseServer(t, "testdata/deleteMonitor.json")
+	now := time.Now().Truncate(time.Second)
+	slo := SLO{Target: 99, Start: now.Add(-24 * time.Hour), End: now.Add(24 * time.Hour)}
+	down := slo.Start.Add(-time.Hour)
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			LogsStartDate string `json:"logs_start_date"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		logs := ""
+		// like the API, only return logs starting on or after the start date
+		if start, _ := strconv.ParseInt(req.LogsStartDate, 10, 64); down.Unix() >= start {
+			logs = fmt.Sprintf(`{"type": 1, "datetime": %d, "duration": 7200}`, down.Unix())
+		}
+		fmt.Fprintf(w, `{"stat": "ok", "monitors": [{"id": 1, "logs": [%s]}]}`, logs)
+	}))
 	defer ts.Close()
-	client.HTTPClient = ts.Client()
-	client.URL = ts.URL
-	var want int64 = 777810874
-	if err := client.DeleteMonitor(want); err != nil {
-		t.Error(err)
+	client := New("dummy", WithHTTPClient(ts.Client()), WithBaseURL(ts.URL))
+	b, err := client.GetErrorBudget(1, slo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Downtime.Round(time.Second) != time.Hour {
+		t.Errorf("want downtime 1h from down period starting before window, got %s", b.Downtime)
 	}
 }
 
-func TestRenderMonitor(t *testing.T) {
+func TestMonitorsEquivalentIgnoresServerFields(t *testing.T) {
 	t.Parallel()
-	tcs := []struct {
-		name     string
-		input    Monitor
-		wantFile string
-	}{
-		{
-			name: "Simple HTTP",
-			input: Monitor{
-				ID:            777749809,
-				FriendlyName:  "Google",
-				URL:           "http://www.google.com",
-				Type:          TypeHTTP,
-				Port:          0,
-				AlertContacts: []string{"3", "5", "7"},
-				Status:        StatusUp,
-			},
-			wantFile: "testdata/monitor_http.txt",
-		},
-		{
-			name: "Keyword exists",
-			input: Monitor{
-				ID:           777749810,
-				FriendlyName: "Google",
-				URL:          "http://www.google.com",
-				Type:         TypeKeyword,
-				KeywordType:  KeywordExists,
-				KeywordValue: "bogus",
-				Port:         80,
-				Status:       StatusMaybeDown,
-			},
-			wantFile: "testdata/monitor_keyword.txt",
-		},
-		{
-			name: "Keyword not exists",
-			input: Monitor{
-				ID:           777749811,
-				FriendlyName: "Google",
-				URL:          "http://www.google.com",
-				Type:         TypeKeyword,
-				KeywordType:  KeywordNotExists,
-				KeywordValue: "bogus",
-				Port:         80,
-				Status:       StatusUnknown,
-			},
-			wantFile: "testdata/monitor_keyword_notexists.txt",
-		},
-		{
-			name: "Subtype",
-			input: Monitor{
-				ID:           777749812,
-				FriendlyName: "Google",
-				URL:          "http://www.google.com",
-				Type:         TypePort,
-				SubType:      SubTypeFTP,
-				Port:         80,
-				Status:       StatusPaused,
-			},
-			wantFile: "testdata/monitor_subtype.txt",
-		},
+	desired := Monitor{
+		FriendlyName:  "Example",
+		URL:           "https://example.com",
+		Type:          TypeHTTP,
+		Interval:      5 * time.Minute,
+		AlertContacts: []ContactID{"1", "2"},
 	}
-	for _, tc := range tcs {
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-			wantBytes, err := ioutil.ReadFile(tc.wantFile)
-			if err != nil {
-				t.Fatal(err)
-			}
-			want := string(wantBytes)
-			got := render(monitorTemplate, tc.input)
-			if !cmp.Equal(want, got) {
-				t.Error(cmp.Diff(want, got))
-			}
+	existing := desired
+	existing.ID = 780689017
+	existing.Status = StatusDown
+	existing.AlertContacts = []ContactID{"2", "1"}
+	existing.Logs = []MonitorLog{{Type: LogTypeDown}}
+	existing.ResponseTimes = []ResponseTime{{Value: time.Second}}
+	existing.UptimeRatios = []float64{99.9}
+	existing.AllTimeUptimeRatio = 99.5
+	if !MonitorsEquivalent(desired, existing) {
+		t.Error("want monitors differing only in server fields to be equivalent")
+	}
+	existing.Interval = time.Minute
+	if MonitorsEquivalent(desired, existing) {
+		t.Error("want monitors with different intervals not to be equivalent")
+	}
+}
 
-		})
+// pagedSearchServer returns a server whose getMonitors results are n monitors
+// matching any search, served a page at a time according to the request's
+// offset and limit, together with a function returning the verb and monitor ID
+// of each other request it has received.
+func pagedSearchServer(t *testing.T, n int) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	calls := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			Offset string `json:"offset"`
+			Limit  string `json:"limit"`
+			ID     string `json:"id"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		verb := strings.TrimPrefix(r.URL.Path, "/v2/")
+		if verb != "getMonitors" {
+			mu.Lock()
+			calls = append(calls, verb+" "+req.ID)
+			mu.Unlock()
+			fmt.Fprintf(w, `{"stat": "ok", "monitor": {"id": %s}}`, req.ID)
+			return
+		}
+		offset, _ := strconv.Atoi(req.Offset)
+		limit, _ := strconv.Atoi(req.Limit)
+		monitors := []string{}
+		for i := offset; i < offset+limit && i < n; i++ {
+			monitors = append(monitors, fmt.Sprintf(`{"id": %d, "url": "https://example.com/%d", "status": 2}`, i+1, i+1))
+		}
+		fmt.Fprintf(w, `{"stat": "ok", "pagination": {"offset": %d, "limit": %d, "total": %d}, "monitors": [%s]}`,
+			offset, limit, n, strings.Join(monitors, ","))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
 	}
 }
 
-func TestRenderAccount(t *testing.T) {
+func TestAddAlertContactBySearchChangesMonitorsOnEveryPage(t *testing.T) {
 	t.Parallel()
-	input := Account{
-		Email:           "j.random@example.com",
-		MonitorLimit:    300,
-		MonitorInterval: 1,
-		UpMonitors:      208,
-		DownMonitors:    2,
-		PausedMonitors:  0,
-	}
-	wantBytes, err := ioutil.ReadFile("testdata/account_template.txt")
+	ts, calls := pagedSearchServer(t, 120)
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	changed, err := client.AddAlertContactBySearch("example.com", "2053888")
 	if err != nil {
 		t.Fatal(err)
 	}
-	want := string(wantBytes)
-	got := render(accountTemplate, input)
-	if !cmp.Equal(want, got) {
-		t.Error(cmp.Diff(want, got))
+	if len(changed) != 120 {
+		t.Errorf("want 120 monitors changed, got %d", len(changed))
+	}
+	if n := len(calls()); n != 120 {
+		t.Errorf("want 120 edits, got %d", n)
 	}
 }
 
-func TestFriendlyType(t *testing.T) {
+func TestGetMonitorsByURLFindsMatchBeyondFirstPage(t *testing.T) {
 	t.Parallel()
-	m := Monitor{
-		Type: TypeHTTP,
+	ts, _ := pagedSearchServer(t, 120)
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	monitors, err := client.GetMonitorsByURL("https://example.com/75")
+	if err != nil {
+		t.Fatal(err)
 	}
-	want := "HTTP"
-	got := m.FriendlyType()
-	if !cmp.Equal(want, got) {
-		t.Error(cmp.Diff(want, got))
+	if len(monitors) != 1 || monitors[0].ID != 75 {
+		t.Errorf("want monitor 75, got %+v", monitors)
 	}
 }
 
-func TestFriendlySubType(t *testing.T) {
+func TestDeleteMonitorsBySearchDeletesMatchesOnEveryPage(t *testing.T) {
 	t.Parallel()
-	tcs := []struct {
-		name string
-		mon  Monitor
-		want string
-	}{
-		{
-			name: "HTTPS",
-			mon: Monitor{
-				Type:    TypePort,
-				SubType: SubTypeHTTPS,
-			},
-			want: "HTTPS (443)",
-		},
-		{
-			name: "Custom port",
-			mon: Monitor{
-				Type:    TypePort,
-				SubType: SubTypeCustomPort,
-				Port:    8080,
-			},
-			want: "Custom port (8080)",
-		},
+	ts, calls := pagedSearchServer(t, 120)
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	deleted, err := client.DeleteMonitorsBySearch("example.com")
+	if err != nil {
+		t.Fatal(err)
 	}
-	for _, tc := range tcs {
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-			got := tc.mon.FriendlySubType()
-			if !cmp.Equal(tc.want, got) {
-				t.Error(cmp.Diff(tc.want, got))
-			}
-		})
+	if len(deleted) != 120 {
+		t.Errorf("want 120 monitors deleted, got %d", len(deleted))
+	}
+	got := calls()
+	if len(got) != 120 {
+		t.Fatalf("want 120 calls, got %d", len(got))
+	}
+	if got[119] != "deleteMonitor 120" {
+		t.Errorf("want last call deleteMonitor 120, got %q", got[119])
 	}
 }
 
-func TestFriendlyKeywordType(t *testing.T) {
+func TestPauseAllWithSearchPausesMatchesOnEveryPage(t *testing.T) {
 	t.Parallel()
-	m := Monitor{
-		Type:        TypeKeyword,
-		KeywordType: KeywordExists,
+	ts, calls := pagedSearchServer(t, 120)
+	client := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	results, err := client.PauseAll("example.com")
+	if err != nil {
+		t.Fatal(err)
 	}
-	want := "Exists"
-	got := m.FriendlyKeywordType()
-	if !cmp.Equal(want, got) {
-		t.Error(cmp.Diff(want, got))
+	if len(results) != 120 {
+		t.Errorf("want 120 results, got %d", len(results))
+	}
+	if n := len(calls()); n != 120 {
+		t.Errorf("want 120 edits, got %d", n)
 	}
-}
-
-// cannedResponseServer returns a test TLS server which responds to any request
-// with a specified file of canned JSON data.
-func cannedResponseServer(t *testing.T, path string) *httptest.Server {
-	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		data, err := os.Open(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		defer data.Close()
-		io.Copy(w, data)
-	}))
 }
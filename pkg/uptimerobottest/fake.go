@@ -0,0 +1,588 @@
+// Package uptimerobottest provides helpers for testing code which uses the
+// uptimerobot package, without calling the real Uptime Robot API.
+package uptimerobottest
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+)
+
+// maxRecordsPerRequest is the maximum number of records the API will return
+// in a single page.
+const maxRecordsPerRequest = 50
+
+// FakeClient is an in-memory implementation of uptimerobot.API, for testing
+// code which manages monitors and alert contacts. It behaves like the real
+// API: it assigns IDs to new monitors and contacts, matches search strings
+// against monitors' names and URLs, and returns monitors in pages of at most
+// 50. The zero value is an empty account, ready to use, and a FakeClient is
+// safe for concurrent use.
+type FakeClient struct {
+	// Account is returned by GetAccountDetails, with its UpMonitors,
+	// DownMonitors, and PausedMonitors fields set from the current monitors.
+	Account uptimerobot.Account
+
+	mu        sync.Mutex
+	monitors  []uptimerobot.Monitor
+	contacts  []uptimerobot.AlertContact
+	lastID    uptimerobot.MonitorID
+	contactID int
+}
+
+var _ uptimerobot.API = (*FakeClient)(nil)
+
+// SetMonitorStatus sets the status of the monitor with the specified ID, for
+// example to simulate a monitor going down.
+func (f *FakeClient) SetMonitorStatus(ID uptimerobot.MonitorID, s uptimerobot.Status) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, err := f.find(ID)
+	if err != nil {
+		return err
+	}
+	f.monitors[i].Status = s
+	return nil
+}
+
+// GetAccountDetails returns the fake's Account, with the monitor counts set.
+func (f *FakeClient) GetAccountDetails() (uptimerobot.Account, error) {
+	return f.GetAccountDetailsContext(context.Background())
+}
+
+// GetAccountDetailsContext is like GetAccountDetails, but returns the
+// context's error if it is done.
+func (f *FakeClient) GetAccountDetailsContext(ctx context.Context) (uptimerobot.Account, error) {
+	if err := ctx.Err(); err != nil {
+		return uptimerobot.Account{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a := f.Account
+	a.UpMonitors, a.DownMonitors, a.PausedMonitors = 0, 0, 0
+	for _, m := range f.monitors {
+		switch m.Status {
+		case uptimerobot.StatusUp:
+			a.UpMonitors++
+		case uptimerobot.StatusDown, uptimerobot.StatusMaybeDown:
+			a.DownMonitors++
+		case uptimerobot.StatusPaused:
+			a.PausedMonitors++
+		}
+	}
+	return a, nil
+}
+
+// GetMonitor returns the monitor with the specified ID, or an
+// uptimerobot.NotFoundError if there is no such monitor.
+func (f *FakeClient) GetMonitor(ID uptimerobot.MonitorID, opts ...uptimerobot.Option) (uptimerobot.Monitor, error) {
+	return f.GetMonitorContext(context.Background(), ID, opts...)
+}
+
+// GetMonitorContext is like GetMonitor, but returns the context's error if it
+// is done.
+func (f *FakeClient) GetMonitorContext(ctx context.Context, ID uptimerobot.MonitorID, opts ...uptimerobot.Option) (uptimerobot.Monitor, error) {
+	monitors, err := f.GetMonitorsByIDsContext(ctx, []uptimerobot.MonitorID{ID}, opts...)
+	if err != nil {
+		return uptimerobot.Monitor{}, err
+	}
+	if len(monitors) == 0 {
+		return uptimerobot.Monitor{}, notFound(ID)
+	}
+	return monitors[0], nil
+}
+
+// GetMonitorsByIDs returns the monitors with the specified IDs, ignoring any
+// IDs with no corresponding monitor.
+func (f *FakeClient) GetMonitorsByIDs(IDs []uptimerobot.MonitorID, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	return f.GetMonitorsByIDsContext(context.Background(), IDs, opts...)
+}
+
+// GetMonitorsByIDsContext is like GetMonitorsByIDs, but returns the context's
+// error if it is done.
+func (f *FakeClient) GetMonitorsByIDsContext(ctx context.Context, IDs []uptimerobot.MonitorID, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	monitors := []uptimerobot.Monitor{}
+	for _, ID := range IDs {
+		if i, err := f.find(ID); err == nil {
+			monitors = append(monitors, copyMonitor(f.monitors[i]))
+		}
+	}
+	return uptimerobot.FilterMonitors(monitors, opts...), nil
+}
+
+// AllMonitors returns all the monitors which satisfy the filtering options,
+// in the order they were created.
+func (f *FakeClient) AllMonitors(opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	return f.AllMonitorsContext(context.Background(), opts...)
+}
+
+// AllMonitorsContext is like AllMonitors, but returns the context's error if
+// it is done.
+func (f *FakeClient) AllMonitorsContext(ctx context.Context, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	monitors := []uptimerobot.Monitor{}
+	err := f.MonitorsContext(ctx, func(m uptimerobot.Monitor) bool {
+		monitors = append(monitors, m)
+		return true
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return monitors, nil
+}
+
+// Monitors calls fn for each monitor which satisfies the filtering options,
+// fetching them one page at a time, and stops if fn returns false.
+func (f *FakeClient) Monitors(fn func(uptimerobot.Monitor) bool, opts ...uptimerobot.Option) error {
+	return f.MonitorsContext(context.Background(), fn, opts...)
+}
+
+// MonitorsContext is like Monitors, but returns the context's error if it is
+// done.
+func (f *FakeClient) MonitorsContext(ctx context.Context, fn func(uptimerobot.Monitor) bool, opts ...uptimerobot.Option) error {
+	offset := 0
+	total := 0
+	for offset <= total {
+		page, err := f.GetMonitorsPageContext(ctx, offset, maxRecordsPerRequest, opts...)
+		if err != nil {
+			return err
+		}
+		for _, m := range page.Monitors {
+			if !fn(m) {
+				return nil
+			}
+		}
+		total = page.Total
+		offset = page.Offset + maxRecordsPerRequest
+	}
+	return nil
+}
+
+// GetMonitorsPage returns the page of monitors starting at the specified
+// offset, containing at most limit monitors. As with the API, a limit outside
+// the range 1 to 50 is treated as 50.
+func (f *FakeClient) GetMonitorsPage(offset, limit int, opts ...uptimerobot.Option) (uptimerobot.MonitorPage, error) {
+	return f.GetMonitorsPageContext(context.Background(), offset, limit, opts...)
+}
+
+// GetMonitorsPageContext is like GetMonitorsPage, but returns the context's
+// error if it is done.
+func (f *FakeClient) GetMonitorsPageContext(ctx context.Context, offset, limit int, opts ...uptimerobot.Option) (uptimerobot.MonitorPage, error) {
+	if err := ctx.Err(); err != nil {
+		return uptimerobot.MonitorPage{}, err
+	}
+	if limit < 1 || limit > maxRecordsPerRequest {
+		limit = maxRecordsPerRequest
+	}
+	f.mu.Lock()
+	monitors := uptimerobot.FilterMonitors(f.snapshot(), opts...)
+	f.mu.Unlock()
+	page := uptimerobot.MonitorPage{
+		Monitors: []uptimerobot.Monitor{},
+		Pagination: uptimerobot.Pagination{
+			Offset: offset,
+			Limit:  limit,
+			Total:  len(monitors),
+		},
+	}
+	if offset < len(monitors) {
+		end := offset + limit
+		if end > len(monitors) {
+			end = len(monitors)
+		}
+		page.Monitors = monitors[offset:end]
+	}
+	return page, nil
+}
+
+// MonitorCount returns the number of monitors which satisfy the filtering
+// options.
+func (f *FakeClient) MonitorCount(opts ...uptimerobot.Option) (int, error) {
+	return f.MonitorCountContext(context.Background(), opts...)
+}
+
+// MonitorCountContext is like MonitorCount, but returns the context's error if
+// it is done.
+func (f *FakeClient) MonitorCountContext(ctx context.Context, opts ...uptimerobot.Option) (int, error) {
+	page, err := f.GetMonitorsPageContext(ctx, 0, 1, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return page.Total, nil
+}
+
+// SearchMonitors returns the monitors whose FriendlyName or URL contains the
+// search string, ignoring case, and which satisfy the filtering options.
+func (f *FakeClient) SearchMonitors(s string, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	return f.SearchMonitorsContext(context.Background(), s, opts...)
+}
+
+// SearchMonitorsContext is like SearchMonitors, but returns the context's
+// error if it is done.
+func (f *FakeClient) SearchMonitorsContext(ctx context.Context, s string, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s = strings.ToLower(s)
+	matches := []uptimerobot.Monitor{}
+	for _, m := range f.snapshot() {
+		if strings.Contains(strings.ToLower(m.FriendlyName), s) || strings.Contains(strings.ToLower(m.URL), s) {
+			matches = append(matches, m)
+		}
+	}
+	return uptimerobot.FilterMonitors(matches, opts...), nil
+}
+
+// GetMonitorsByURL returns the monitors whose URL is exactly the specified
+// URL.
+func (f *FakeClient) GetMonitorsByURL(URL string, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	return f.GetMonitorsByURLContext(context.Background(), URL, opts...)
+}
+
+// GetMonitorsByURLContext is like GetMonitorsByURL, but returns the context's
+// error if it is done.
+func (f *FakeClient) GetMonitorsByURLContext(ctx context.Context, URL string, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	monitors, err := f.SearchMonitorsContext(ctx, URL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	matches := []uptimerobot.Monitor{}
+	for _, m := range monitors {
+		if m.URL == URL {
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+// CreateMonitor adds a monitor with the specified details, and returns its
+// newly assigned ID. As with the API, the FriendlyName, URL, and Type fields
+// are required, and the new monitor's status is StatusUnknown (not yet
+// checked).
+func (f *FakeClient) CreateMonitor(m uptimerobot.Monitor) (uptimerobot.MonitorID, error) {
+	return f.CreateMonitorContext(context.Background(), m)
+}
+
+// CreateMonitorContext is like CreateMonitor, but returns the context's error
+// if it is done.
+func (f *FakeClient) CreateMonitorContext(ctx context.Context, m uptimerobot.Monitor) (uptimerobot.MonitorID, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if m.FriendlyName == "" || m.URL == "" || m.Type == 0 {
+		return 0, errors.New("monitor friendly name, URL, and type are required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastID++
+	m = copyMonitor(m)
+	m.ID = f.lastID
+	m.Status = uptimerobot.StatusUnknown
+	m.Logs = nil
+	m.ResponseTimes = nil
+	m.UptimeRatios = nil
+	m.UptimeRanges = nil
+	m.AllTimeUptimeRatio = 0
+	f.monitors = append(f.monitors, m)
+	return m.ID, nil
+}
+
+// EditMonitor makes the specified changes to an existing monitor, leaving any
+// settings not set in p unchanged. Like the API, it returns a Monitor with
+// only the ID field set.
+func (f *FakeClient) EditMonitor(p uptimerobot.EditMonitorParams) (uptimerobot.Monitor, error) {
+	return f.EditMonitorContext(context.Background(), p)
+}
+
+// EditMonitorContext is like EditMonitor, but returns the context's error if
+// it is done.
+func (f *FakeClient) EditMonitorContext(ctx context.Context, p uptimerobot.EditMonitorParams) (uptimerobot.Monitor, error) {
+	if err := ctx.Err(); err != nil {
+		return uptimerobot.Monitor{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, err := f.find(p.ID)
+	if err != nil {
+		return uptimerobot.Monitor{}, err
+	}
+	m := &f.monitors[i]
+	if p.FriendlyName != nil {
+		m.FriendlyName = *p.FriendlyName
+	}
+	if p.URL != nil {
+		m.URL = *p.URL
+	}
+	if p.SubType != nil {
+		m.SubType = *p.SubType
+	}
+	if p.Port != nil {
+		m.Port = *p.Port
+	}
+	if p.KeywordType != nil {
+		m.KeywordType = *p.KeywordType
+	}
+	if p.KeywordValue != nil {
+		m.KeywordValue = *p.KeywordValue
+	}
+	if p.Interval != nil {
+		m.Interval = *p.Interval
+	}
+	if p.Timeout != nil {
+		m.Timeout = *p.Timeout
+	}
+	if p.IgnoreSSLErrors != nil {
+		m.IgnoreSSLErrors = *p.IgnoreSSLErrors
+	}
+	if p.AlertContacts != nil {
+		m.AlertContacts = append([]uptimerobot.ContactID{}, *p.AlertContacts...)
+		m.ContactSettings = nil
+		for _, ID := range m.AlertContacts {
+			if cs, ok := p.ContactSettings[ID]; ok {
+				if m.ContactSettings == nil {
+					m.ContactSettings = map[uptimerobot.ContactID]uptimerobot.ContactSettings{}
+				}
+				m.ContactSettings[ID] = cs
+			}
+		}
+	}
+	return uptimerobot.Monitor{ID: p.ID}, nil
+}
+
+// PauseMonitor sets the status of the monitor with the ID given in m to
+// StatusPaused.
+func (f *FakeClient) PauseMonitor(m uptimerobot.Monitor) (uptimerobot.Monitor, error) {
+	return f.PauseMonitorContext(context.Background(), m)
+}
+
+// PauseMonitorContext is like PauseMonitor, but returns the context's error if
+// it is done.
+func (f *FakeClient) PauseMonitorContext(ctx context.Context, m uptimerobot.Monitor) (uptimerobot.Monitor, error) {
+	return f.setStatus(ctx, m.ID, uptimerobot.StatusPaused)
+}
+
+// StartMonitor resumes the monitor with the ID given in m, setting its status
+// to StatusUnknown (not yet checked).
+func (f *FakeClient) StartMonitor(m uptimerobot.Monitor) (uptimerobot.Monitor, error) {
+	return f.StartMonitorContext(context.Background(), m)
+}
+
+// StartMonitorContext is like StartMonitor, but returns the context's error if
+// it is done.
+func (f *FakeClient) StartMonitorContext(ctx context.Context, m uptimerobot.Monitor) (uptimerobot.Monitor, error) {
+	return f.setStatus(ctx, m.ID, uptimerobot.StatusUnknown)
+}
+
+// DeleteMonitor deletes the monitor with the specified ID, or returns an
+// uptimerobot.NotFoundError if there is no such monitor.
+func (f *FakeClient) DeleteMonitor(ID uptimerobot.MonitorID) error {
+	return f.DeleteMonitorContext(context.Background(), ID)
+}
+
+// DeleteMonitorContext is like DeleteMonitor, but returns the context's error
+// if it is done.
+func (f *FakeClient) DeleteMonitorContext(ctx context.Context, ID uptimerobot.MonitorID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, err := f.find(ID)
+	if err != nil {
+		return err
+	}
+	f.monitors = append(f.monitors[:i], f.monitors[i+1:]...)
+	return nil
+}
+
+// AllAlertContacts returns all the alert contacts, in the order they were
+// created.
+func (f *FakeClient) AllAlertContacts() ([]uptimerobot.AlertContact, error) {
+	return f.AllAlertContactsContext(context.Background())
+}
+
+// AllAlertContactsContext is like AllAlertContacts, but returns the context's
+// error if it is done.
+func (f *FakeClient) AllAlertContactsContext(ctx context.Context) ([]uptimerobot.AlertContact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]uptimerobot.AlertContact{}, f.contacts...), nil
+}
+
+// GetAlertContact returns the alert contact with the specified ID, or an
+// uptimerobot.NotFoundError if there is no such contact.
+func (f *FakeClient) GetAlertContact(ID uptimerobot.ContactID) (uptimerobot.AlertContact, error) {
+	return f.GetAlertContactContext(context.Background(), ID)
+}
+
+// GetAlertContactContext is like GetAlertContact, but returns the context's
+// error if it is done.
+func (f *FakeClient) GetAlertContactContext(ctx context.Context, ID uptimerobot.ContactID) (uptimerobot.AlertContact, error) {
+	if err := ctx.Err(); err != nil {
+		return uptimerobot.AlertContact{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, a := range f.contacts {
+		if a.ID == ID {
+			return a, nil
+		}
+	}
+	return uptimerobot.AlertContact{}, uptimerobot.NotFoundError{
+		Resource: "alert contact",
+		ID:       ID.String(),
+	}
+}
+
+// CreateAlertContact adds an alert contact with the specified details, and
+// returns its newly assigned ID. The FriendlyName, Type, and Value fields are
+// required.
+func (f *FakeClient) CreateAlertContact(a uptimerobot.AlertContact) (uptimerobot.ContactID, error) {
+	return f.CreateAlertContactContext(context.Background(), a)
+}
+
+// CreateAlertContactContext is like CreateAlertContact, but returns the
+// context's error if it is done.
+func (f *FakeClient) CreateAlertContactContext(ctx context.Context, a uptimerobot.AlertContact) (uptimerobot.ContactID, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if a.FriendlyName == "" || a.Type == 0 || a.Value == "" {
+		return "", errors.New("alert contact friendly name, type, and value are required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.contactID++
+	a.ID = uptimerobot.ContactID(strconv.Itoa(f.contactID))
+	f.contacts = append(f.contacts, a)
+	return a.ID, nil
+}
+
+// AddAlertContactToMonitor assigns the specified alert contact to an existing
+// monitor, keeping any alert contacts already assigned to it.
+func (f *FakeClient) AddAlertContactToMonitor(monitorID uptimerobot.MonitorID, contactID uptimerobot.ContactID) error {
+	return f.AddAlertContactToMonitorContext(context.Background(), monitorID, contactID)
+}
+
+// AddAlertContactToMonitorContext is like AddAlertContactToMonitor, but
+// returns the context's error if it is done.
+func (f *FakeClient) AddAlertContactToMonitorContext(ctx context.Context, monitorID uptimerobot.MonitorID, contactID uptimerobot.ContactID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, err := f.find(monitorID)
+	if err != nil {
+		return err
+	}
+	for _, ID := range f.monitors[i].AlertContacts {
+		if ID == contactID {
+			return nil
+		}
+	}
+	f.monitors[i].AlertContacts = append(f.monitors[i].AlertContacts, contactID)
+	return nil
+}
+
+// RemoveAlertContactFromMonitor removes the specified alert contact from an
+// existing monitor, keeping any other alert contacts assigned to it.
+func (f *FakeClient) RemoveAlertContactFromMonitor(monitorID uptimerobot.MonitorID, contactID uptimerobot.ContactID) error {
+	return f.RemoveAlertContactFromMonitorContext(context.Background(), monitorID, contactID)
+}
+
+// RemoveAlertContactFromMonitorContext is like RemoveAlertContactFromMonitor,
+// but returns the context's error if it is done.
+func (f *FakeClient) RemoveAlertContactFromMonitorContext(ctx context.Context, monitorID uptimerobot.MonitorID, contactID uptimerobot.ContactID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, err := f.find(monitorID)
+	if err != nil {
+		return err
+	}
+	contacts := []uptimerobot.ContactID{}
+	for _, ID := range f.monitors[i].AlertContacts {
+		if ID != contactID {
+			contacts = append(contacts, ID)
+		}
+	}
+	f.monitors[i].AlertContacts = contacts
+	delete(f.monitors[i].ContactSettings, contactID)
+	return nil
+}
+
+// setStatus sets the status of the monitor with the specified ID.
+func (f *FakeClient) setStatus(ctx context.Context, ID uptimerobot.MonitorID, s uptimerobot.Status) (uptimerobot.Monitor, error) {
+	if err := ctx.Err(); err != nil {
+		return uptimerobot.Monitor{}, err
+	}
+	if err := f.SetMonitorStatus(ID, s); err != nil {
+		return uptimerobot.Monitor{}, err
+	}
+	return uptimerobot.Monitor{ID: ID}, nil
+}
+
+// find returns the index of the monitor with the specified ID. The caller
+// must hold f.mu.
+func (f *FakeClient) find(ID uptimerobot.MonitorID) (int, error) {
+	for i, m := range f.monitors {
+		if m.ID == ID {
+			return i, nil
+		}
+	}
+	return 0, notFound(ID)
+}
+
+// snapshot returns copies of all the monitors, so that callers can't modify
+// the fake's state. The caller must hold f.mu.
+func (f *FakeClient) snapshot() []uptimerobot.Monitor {
+	monitors := make([]uptimerobot.Monitor, len(f.monitors))
+	for i, m := range f.monitors {
+		monitors[i] = copyMonitor(m)
+	}
+	return monitors
+}
+
+// copyMonitor returns a copy of m which shares no slices with it.
+func copyMonitor(m uptimerobot.Monitor) uptimerobot.Monitor {
+	if m.AlertContacts != nil {
+		m.AlertContacts = append([]uptimerobot.ContactID{}, m.AlertContacts...)
+	}
+	if m.Logs != nil {
+		m.Logs = append([]uptimerobot.MonitorLog{}, m.Logs...)
+	}
+	if m.ResponseTimes != nil {
+		m.ResponseTimes = append([]uptimerobot.ResponseTime{}, m.ResponseTimes...)
+	}
+	if m.UptimeRatios != nil {
+		m.UptimeRatios = append([]float64{}, m.UptimeRatios...)
+	}
+	if m.UptimeRanges != nil {
+		m.UptimeRanges = append([]float64{}, m.UptimeRanges...)
+	}
+	return m
+}
+
+// notFound returns the error for a monitor ID with no corresponding monitor.
+func notFound(ID uptimerobot.MonitorID) error {
+	return uptimerobot.NotFoundError{
+		Resource: "monitor",
+		ID:       ID.String(),
+	}
+}
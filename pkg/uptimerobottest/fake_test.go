@@ -0,0 +1,195 @@
+package uptimerobottest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFakeClientCreateAndSearch(t *testing.T) {
+	t.Parallel()
+	f := &FakeClient{}
+	webID, err := f.CreateMonitor(uptimerobot.Monitor{
+		FriendlyName: "Example web",
+		URL:          "https://example.com/",
+		Type:         uptimerobot.TypeHTTP,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiID, err := f.CreateMonitor(uptimerobot.Monitor{
+		FriendlyName: "API",
+		URL:          "https://api.example.com/",
+		Type:         uptimerobot.TypeKeyword,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if webID == apiID {
+		t.Fatalf("want distinct IDs, got %d twice", webID)
+	}
+	if _, err := f.CreateMonitor(uptimerobot.Monitor{URL: "https://example.org/"}); err == nil {
+		t.Error("want error creating monitor without name and type, got nil")
+	}
+	found, err := f.SearchMonitors("EXAMPLE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 {
+		t.Errorf("want 2 monitors matching search, got %d", len(found))
+	}
+	found, err = f.SearchMonitors("example", uptimerobot.WithTypes(uptimerobot.TypeKeyword))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].ID != apiID {
+		t.Errorf("want only monitor %d, got %v", apiID, found)
+	}
+	exact, err := f.GetMonitorsByURL("https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exact) != 1 || exact[0].ID != webID {
+		t.Errorf("want only monitor %d, got %v", webID, exact)
+	}
+	m, err := f.GetMonitor(webID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Status != uptimerobot.StatusUnknown {
+		t.Errorf("want new monitor status %v, got %v", uptimerobot.StatusUnknown, m.Status)
+	}
+}
+
+func TestFakeClientPaginates(t *testing.T) {
+	t.Parallel()
+	f := &FakeClient{}
+	for i := 0; i < 120; i++ {
+		_, err := f.CreateMonitor(uptimerobot.Monitor{
+			FriendlyName: fmt.Sprintf("Monitor %d", i),
+			URL:          fmt.Sprintf("https://example.com/%d", i),
+			Type:         uptimerobot.TypeHTTP,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	page, err := f.GetMonitorsPage(100, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := uptimerobot.Pagination{Offset: 100, Limit: 50, Total: 120}
+	if !cmp.Equal(want, page.Pagination) {
+		t.Error(cmp.Diff(want, page.Pagination))
+	}
+	if len(page.Monitors) != 20 {
+		t.Errorf("want 20 monitors on last page, got %d", len(page.Monitors))
+	}
+	all, err := f.AllMonitors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 120 {
+		t.Errorf("want 120 monitors, got %d", len(all))
+	}
+	count, err := f.MonitorCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 120 {
+		t.Errorf("want count 120, got %d", count)
+	}
+}
+
+func TestFakeClientEditPauseAndDelete(t *testing.T) {
+	t.Parallel()
+	f := &FakeClient{}
+	ID, err := f.CreateMonitor(uptimerobot.Monitor{
+		FriendlyName:  "Example",
+		URL:           "https://example.com/",
+		Type:          uptimerobot.TypeHTTP,
+		AlertContacts: []uptimerobot.ContactID{"1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.EditMonitor(uptimerobot.EditMonitorParams{
+		ID:           ID,
+		FriendlyName: uptimerobot.String("Renamed"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddAlertContactToMonitor(ID, "2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.RemoveAlertContactFromMonitor(ID, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.PauseMonitor(uptimerobot.Monitor{ID: ID}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.GetMonitor(ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := uptimerobot.Monitor{
+		ID:            ID,
+		FriendlyName:  "Renamed",
+		URL:           "https://example.com/",
+		Type:          uptimerobot.TypeHTTP,
+		AlertContacts: []uptimerobot.ContactID{"2"},
+		Status:        uptimerobot.StatusPaused,
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	account, err := f.GetAccountDetails()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.PausedMonitors != 1 {
+		t.Errorf("want 1 paused monitor, got %d", account.PausedMonitors)
+	}
+	if err := f.DeleteMonitor(ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.GetMonitor(ID)
+	var notFound uptimerobot.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("want NotFoundError for deleted monitor, got %v", err)
+	}
+}
+
+func TestFakeClientAlertContacts(t *testing.T) {
+	t.Parallel()
+	f := &FakeClient{}
+	ID, err := f.CreateAlertContact(uptimerobot.AlertContact{
+		FriendlyName: "On call",
+		Type:         uptimerobot.AlertContactTypeEmail,
+		Value:        "oncall@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.GetAlertContact(ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FriendlyName != "On call" {
+		t.Errorf("want contact %q, got %q", "On call", got.FriendlyName)
+	}
+	all, err := f.AllAlertContacts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Errorf("want 1 contact, got %d", len(all))
+	}
+	if _, err := f.GetAlertContact("9999"); err == nil {
+		t.Error("want error for unknown contact, got nil")
+	}
+}
@@ -0,0 +1,244 @@
+package uptimerobottest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+)
+
+// Mode determines whether a Recorder records or replays API interactions.
+type Mode int
+
+const (
+	// Replay answers requests from previously recorded interactions, without
+	// making any network requests.
+	Replay Mode = iota
+	// Record sends requests to the real API, and records the interactions.
+	Record
+)
+
+// Interaction is a single recorded API request and its response. The request
+// is stored without its api_key field, so that recordings can safely be
+// committed to version control.
+type Interaction struct {
+	Verb     string          `json:"verb"`
+	Request  json.RawMessage `json:"request"`
+	Status   int             `json:"status"`
+	Header   http.Header     `json:"header,omitempty"`
+	Response string          `json:"response"`
+}
+
+// recordedHeaders lists the response headers which are recorded: only those
+// which affect how the client handles a response.
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+// Recorder is an http.RoundTripper which records API interactions to a
+// fixture file (a 'cassette'), or replays them from it, so that tests can run
+// deterministically without an API key and without hitting the rate limit.
+//
+// In Replay mode, each request is answered with the first recorded
+// interaction, not already replayed, which has the same verb and request
+// parameters. A request with no such interaction returns an error.
+//
+// In Record mode, requests are sent using Transport (or
+// http.DefaultTransport, if it is nil), and the cassette is rewritten after
+// each interaction. Responses are recorded as they are, so take care not to
+// commit recordings containing details you want to keep private.
+type Recorder struct {
+	Transport http.RoundTripper
+
+	path         string
+	mode         Mode
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder returns a Recorder using the cassette at path. In Replay mode,
+// the cassette is read immediately, and an error is returned if it can't be.
+// In Record mode, any existing cassette is replaced.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		path: path,
+		mode: mode,
+	}
+	if mode == Record {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("reading cassette %s: %v", path, err)
+	}
+	for i, in := range r.interactions {
+		req, err := normalizeRequest(in.Request)
+		if err != nil {
+			return nil, fmt.Errorf("reading cassette %s: interaction %d: %v", path, i, err)
+		}
+		r.interactions[i].Request = req
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+// RoundTrip records or replays a single API request, according to the
+// recorder's mode.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := []byte{}
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	params, err := normalizeRequest(body)
+	if err != nil {
+		return nil, err
+	}
+	verb := path.Base(req.URL.Path)
+	if r.mode == Record {
+		return r.record(req, verb, params, body)
+	}
+	return r.replay(req, verb, params)
+}
+
+// record sends the request using the recorder's transport, and records the
+// interaction.
+func (r *Recorder) record(req *http.Request, verb string, params json.RawMessage, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	in := Interaction{
+		Verb:     verb,
+		Request:  params,
+		Status:   resp.StatusCode,
+		Header:   http.Header{},
+		Response: string(data),
+	}
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			in.Header.Set(h, v)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, in)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay answers the request from the first matching recorded interaction
+// which has not already been replayed.
+func (r *Recorder) replay(req *http.Request, verb string, params json.RawMessage) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] || in.Verb != verb || !bytes.Equal(in.Request, params) {
+			continue
+		}
+		r.used[i] = true
+		header := in.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        strconv.Itoa(in.Status) + " " + http.StatusText(in.Status),
+			StatusCode:    in.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(in.Response))),
+			ContentLength: int64(len(in.Response)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction in %s for %s %s", r.path, verb, params)
+}
+
+// save writes the recorded interactions to the cassette. The caller must hold
+// r.mu.
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// normalizeRequest returns the JSON request parameters in a canonical form,
+// with the api_key field removed, so that equivalent requests compare equal.
+func normalizeRequest(data []byte) (json.RawMessage, error) {
+	params := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &params); err != nil {
+			return nil, fmt.Errorf("request is not a JSON object: %v", err)
+		}
+	}
+	delete(params, "api_key")
+	return json.Marshal(params)
+}
+
+// NewRecordingClient returns a Client whose requests go through a Recorder
+// using the cassette at path. If the environment variable UPTIMEROBOT_RECORD
+// is set, the client records interactions with the real API, using the API
+// key in UPTIMEROBOT_API_KEY. Otherwise, it replays the recorded
+// interactions, needing neither an API key nor network access. Any
+// ClientOptions given are applied after those which set up the recorder.
+func NewRecordingClient(t testing.TB, path string, opts ...uptimerobot.ClientOption) *uptimerobot.Client {
+	t.Helper()
+	mode := Replay
+	key := "dummy"
+	if os.Getenv("UPTIMEROBOT_RECORD") != "" {
+		mode = Record
+		key = os.Getenv("UPTIMEROBOT_API_KEY")
+		if key == "" {
+			t.Fatal("'UPTIMEROBOT_API_KEY' must be set to record API interactions")
+		}
+	}
+	rec, err := NewRecorder(path, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts = append([]uptimerobot.ClientOption{
+		uptimerobot.WithHTTPClient(&http.Client{
+			Transport: rec,
+			Timeout:   30 * time.Second,
+		}),
+	}, opts...)
+	client := uptimerobot.New(key, opts...)
+	return &client
+}
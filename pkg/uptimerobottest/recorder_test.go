@@ -0,0 +1,70 @@
+package uptimerobottest
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecorderRecordsAndReplays(t *testing.T) {
+	t.Parallel()
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	ts := NewServer(t, map[string]string{
+		"getMonitors": "testdata/getMonitors.json",
+	})
+	rec, err := NewRecorder(cassette, Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Transport = ts.Client().Transport
+	client := uptimerobot.New("secret-key",
+		uptimerobot.WithHTTPClient(&http.Client{Transport: rec}),
+		uptimerobot.WithBaseURL(ts.URL),
+	)
+	want, err := client.SearchMonitors("google")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.Close()
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-key") {
+		t.Error("cassette contains API key")
+	}
+	rec, err = NewRecorder(cassette, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = uptimerobot.New("dummy",
+		uptimerobot.WithHTTPClient(&http.Client{Transport: rec}),
+		uptimerobot.WithBaseURL(ts.URL),
+	)
+	got, err := client.SearchMonitors("google")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+	if _, err := client.SearchMonitors("google"); err == nil {
+		t.Error("want error replaying interaction twice, got nil")
+	}
+	if _, err := client.SearchMonitors("other"); err == nil {
+		t.Error("want error for request with different parameters, got nil")
+	}
+}
+
+func TestNewRecorderReplayRequiresCassette(t *testing.T) {
+	t.Parallel()
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), Replay)
+	if err == nil {
+		t.Error("want error for missing cassette, got nil")
+	}
+}
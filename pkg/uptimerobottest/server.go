@@ -0,0 +1,58 @@
+package uptimerobottest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+)
+
+// NewServer starts a TLS test server which answers each API request with the
+// contents of a fixture file. The fixtures map gives the file for each API
+// verb, for example:
+//
+//	ts := uptimerobottest.NewServer(t, map[string]string{
+//		"getMonitors":   "testdata/getMonitors.json",
+//		"deleteMonitor": "testdata/deleteMonitor.json",
+//	})
+//
+// A request for a verb with no fixture, or whose fixture can't be read, fails
+// the test. The server is closed when the test ends.
+func NewServer(t testing.TB, fixtures map[string]string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verb := strings.TrimPrefix(r.URL.Path, "/v2/")
+		path, ok := fixtures[verb]
+		if !ok {
+			t.Errorf("unexpected API request %s: no fixture", verb)
+			http.Error(w, "no fixture for "+verb, http.StatusNotFound)
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("reading fixture for %s: %v", verb, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// NewClient starts a server with NewServer, and returns a Client configured
+// to send its requests to that server. Any ClientOptions given are applied
+// after those which point the client at the server.
+func NewClient(t testing.TB, fixtures map[string]string, opts ...uptimerobot.ClientOption) *uptimerobot.Client {
+	t.Helper()
+	ts := NewServer(t, fixtures)
+	opts = append([]uptimerobot.ClientOption{
+		uptimerobot.WithHTTPClient(ts.Client()),
+		uptimerobot.WithBaseURL(ts.URL),
+	}, opts...)
+	client := uptimerobot.New("dummy", opts...)
+	return &client
+}
@@ -0,0 +1,60 @@
+package uptimerobottest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+)
+
+func TestNewClientServesFixtures(t *testing.T) {
+	t.Parallel()
+	client := NewClient(t, map[string]string{
+		"getMonitors":   "testdata/getMonitors.json",
+		"deleteMonitor": "testdata/deleteMonitor.json",
+	}, uptimerobot.WithRetries(0))
+	monitors, err := client.AllMonitors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 4 {
+		t.Errorf("want 4 monitors, got %d", len(monitors))
+	}
+	if monitors[0].FriendlyName != "Google" {
+		t.Errorf("want first monitor %q, got %q", "Google", monitors[0].FriendlyName)
+	}
+	if err := client.DeleteMonitor(777810874); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// recordingTB is a testing.TB which records errors instead of failing the
+// test, so that we can check that the server reports unexpected requests.
+type recordingTB struct {
+	testing.TB
+	mu     sync.Mutex
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestNewServerFailsTestOnUnexpectedVerb(t *testing.T) {
+	t.Parallel()
+	rec := &recordingTB{TB: t}
+	client := NewClient(rec, map[string]string{
+		"getMonitors": "testdata/getMonitors.json",
+	})
+	if err := client.DeleteMonitor(1); err == nil {
+		t.Error("want error for verb with no fixture, got nil")
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.errors) != 1 {
+		t.Errorf("want 1 test error, got %q", rec.errors)
+	}
+}
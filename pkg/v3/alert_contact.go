@@ -0,0 +1,70 @@
+package v3
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+)
+
+// alertContact is the v3 API's representation of an alert contact, whose type
+// and status are given by name.
+type alertContact struct {
+	ID           uptimerobot.ContactID `json:"id"`
+	FriendlyName string                `json:"friendlyName"`
+	Type         string                `json:"type"`
+	Status       string                `json:"status"`
+	Value        string                `json:"value"`
+}
+
+// alertContactPage is a page of alert contacts, with a link to the next page,
+// if any.
+type alertContactPage struct {
+	Data     []alertContact `json:"data"`
+	NextLink string         `json:"nextLink"`
+}
+
+// contactStatuses maps the v3 names of alert contact statuses to their v2
+// values.
+var contactStatuses = map[string]int{
+	"NOT_ACTIVATED": 0,
+	"PAUSED":        1,
+	"ACTIVE":        2,
+}
+
+// toAlertContact converts a v3 alert contact to an AlertContact. A type which
+// the uptimerobot package doesn't know is given as 0.
+func toAlertContact(w alertContact) uptimerobot.AlertContact {
+	t, _ := uptimerobot.ParseAlertContactType(w.Type)
+	return uptimerobot.AlertContact{
+		ID:           w.ID,
+		FriendlyName: w.FriendlyName,
+		Type:         int(t),
+		Status:       contactStatuses[strings.ToUpper(w.Status)],
+		Value:        w.Value,
+	}
+}
+
+// AllAlertContacts returns all the alert contacts in the account.
+func (c *Client) AllAlertContacts() ([]uptimerobot.AlertContact, error) {
+	return c.AllAlertContactsContext(context.Background())
+}
+
+// AllAlertContactsContext is like AllAlertContacts, but uses the specified
+// context for its API requests.
+func (c *Client) AllAlertContactsContext(ctx context.Context) ([]uptimerobot.AlertContact, error) {
+	contacts := []uptimerobot.AlertContact{}
+	next := "/alert-contacts"
+	for next != "" {
+		page := alertContactPage{}
+		if err := c.do(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, w := range page.Data {
+			contacts = append(contacts, toAlertContact(w))
+		}
+		next = page.NextLink
+	}
+	return contacts, nil
+}
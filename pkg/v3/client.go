@@ -0,0 +1,196 @@
+// Package v3 is a client for version 3 of the Uptime Robot API, a REST API
+// which uses bearer token authentication. It uses the same Monitor and
+// AlertContact types as the v2 client in the uptimerobot package, and
+// implements uptimerobot.MonitorStore, so that the high-level helpers, such as
+// uptimerobot.SyncMonitorsIn and uptimerobot.EnsureMonitorsIn, work with
+// either. This lets you migrate from one API to the other a program at a
+// time.
+//
+// So far, the client covers monitors and alert contacts.
+package v3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+)
+
+// defaultUserAgent identifies requests made by this package.
+const defaultUserAgent = "uptimerobot-go/" + uptimerobot.Version + " (v3)"
+
+// Client represents an Uptime Robot v3 API client. Create one with New.
+//
+// The URL field determines where requests are sent; by default this is
+// 'https://api.uptimerobot.com/v3'. If the RateLimiter field is set, the
+// client calls its Wait method before each request. A request rejected
+// because of the API's rate limit returns an uptimerobot.RateLimitError, and
+// a request for a monitor which doesn't exist returns an
+// uptimerobot.NotFoundError.
+//
+// A Client is safe for concurrent use by multiple goroutines, provided that
+// its exported fields are not changed while it is in use.
+type Client struct {
+	apiKey      string
+	HTTPClient  *http.Client
+	URL         string
+	UserAgent   string
+	RateLimiter uptimerobot.RateLimiter
+}
+
+// ClientOption represents a configuration setting which can be passed to New.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to make requests.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = hc
+	}
+}
+
+// WithBaseURL sets the URL to which requests are sent, for example the URL of
+// a test server.
+func WithBaseURL(URL string) ClientOption {
+	return func(c *Client) {
+		c.URL = URL
+	}
+}
+
+// WithRateLimiter sets the RateLimiter which the client consults before each
+// request.
+func WithRateLimiter(l uptimerobot.RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.RateLimiter = l
+	}
+}
+
+// New takes an Uptime Robot API key and returns a Client for the v3 API. The
+// client can be configured by passing any number of ClientOptions.
+func New(apiKey string, opts ...ClientOption) Client {
+	c := Client{
+		apiKey: apiKey,
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		URL:       "https://api.uptimerobot.com/v3",
+		UserAgent: defaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// do sends a request with the specified method to the specified path, which
+// may be a complete URL, such as a link to the next page of results. So that
+// the API key is never sent anywhere else, a complete URL must have the same
+// scheme and host as the client's URL. If in is not nil, it is sent as the
+// JSON request body, and if out is not nil, the JSON response body is decoded
+// into it.
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	requestURL := c.URL + path
+	if u, err := url.Parse(path); err == nil && u.IsAbs() {
+		base, err := url.Parse(c.URL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL %q: %v", c.URL, err)
+		}
+		if !strings.EqualFold(u.Scheme, base.Scheme) || !strings.EqualFold(u.Host, base.Host) {
+			return fmt.Errorf("refusing to follow link to %s, which is not on %s", u.Redacted(), base.Host)
+		}
+		requestURL = path
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %v", err)
+	}
+	verb := method + " " + req.URL.Path
+	if resp.StatusCode == http.StatusTooManyRequests {
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return uptimerobot.RateLimitError{
+			RetryAfter: time.Duration(secs) * time.Second,
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(verb, resp.StatusCode, data)
+	}
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return uptimerobot.APIError{
+			Verb:       verb,
+			StatusCode: resp.StatusCode,
+			Body:       truncate(data),
+			Err:        err,
+		}
+	}
+	return nil
+}
+
+// newAPIError returns an uptimerobot.APIError describing an unsuccessful
+// response, including the API's error message, if any.
+func newAPIError(verb string, status int, data []byte) uptimerobot.APIError {
+	e := uptimerobot.APIError{
+		Verb:       verb,
+		StatusCode: status,
+		Body:       truncate(data),
+	}
+	details := uptimerobot.Error{}
+	if err := json.Unmarshal(data, &details); err != nil || len(details) == 0 {
+		return e
+	}
+	e.Details = details
+	e.Type, _ = details["error"].(string)
+	if t, ok := details["type"].(string); ok {
+		e.Type = t
+	}
+	e.Message, _ = details["message"].(string)
+	return e
+}
+
+// truncate returns at most the first 512 bytes of a response body, for
+// inclusion in an error.
+func truncate(data []byte) string {
+	if len(data) > 512 {
+		return string(data[:512])
+	}
+	return string(data)
+}
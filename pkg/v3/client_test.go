@@ -0,0 +1,254 @@
+package v3
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeAPI is a minimal in-memory implementation of the v3 monitors API,
+// serving at most two monitors per page.
+type fakeAPI struct {
+	mu       sync.Mutex
+	monitors []map[string]interface{}
+	lastID   int
+	requests []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+	if r.Header.Get("Authorization") != "Bearer dummy" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"invalid API key"}`)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/monitors":
+		start := 0
+		fmt.Sscan(r.URL.Query().Get("cursor"), &start)
+		end := start + 2
+		if end > len(f.monitors) {
+			end = len(f.monitors)
+		}
+		page := map[string]interface{}{"data": f.monitors[start:end]}
+		if end < len(f.monitors) {
+			page["nextLink"] = fmt.Sprintf("http://%s/monitors?cursor=%d", r.Host, end)
+		}
+		json.NewEncoder(w).Encode(page)
+	case r.Method == http.MethodPost && r.URL.Path == "/monitors":
+		m := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&m)
+		f.lastID++
+		m["id"] = f.lastID
+		f.monitors = append(f.monitors, m)
+		json.NewEncoder(w).Encode(m)
+	case strings.HasPrefix(r.URL.Path, "/monitors/"):
+		i := f.find(strings.TrimPrefix(r.URL.Path, "/monitors/"))
+		if i < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"monitor not found"}`)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(f.monitors[i])
+		case http.MethodPatch:
+			json.NewDecoder(r.Body).Decode(&f.monitors[i])
+			json.NewEncoder(w).Encode(f.monitors[i])
+		case http.MethodDelete:
+			f.monitors = append(f.monitors[:i], f.monitors[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// find returns the index of the monitor with the specified ID, or -1 if there
+// is none.
+func (f *fakeAPI) find(ID string) int {
+	for i, m := range f.monitors {
+		if fmt.Sprint(m["id"]) == ID {
+			return i
+		}
+	}
+	return -1
+}
+
+func newTestClient(t *testing.T, f *fakeAPI) Client {
+	t.Helper()
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+	return New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+}
+
+func TestCreateAndGetMonitor(t *testing.T) {
+	t.Parallel()
+	f := &fakeAPI{}
+	c := newTestClient(t, f)
+	want := uptimerobot.Monitor{
+		FriendlyName:  "Example",
+		URL:           "example.com",
+		Type:          uptimerobot.TypePort,
+		SubType:       uptimerobot.SubTypeHTTPS,
+		Interval:      5 * time.Minute,
+		Timeout:       30 * time.Second,
+		AlertContacts: []uptimerobot.ContactID{"0102759"},
+	}
+	ID, err := c.CreateMonitor(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ID != 1 {
+		t.Errorf("want ID 1, got %d", ID)
+	}
+	if f.monitors[0]["type"] != "PORT" || f.monitors[0]["port"] != 443.0 || f.monitors[0]["interval"] != 300.0 {
+		t.Errorf("unexpected request body %v", f.monitors[0])
+	}
+	got, err := c.GetMonitor(ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want.ID = ID
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestGetMonitorReturnsNotFoundError(t *testing.T) {
+	t.Parallel()
+	c := newTestClient(t, &fakeAPI{})
+	_, err := c.GetMonitor(99)
+	if !errors.Is(err, uptimerobot.ErrMonitorNotFound) {
+		t.Errorf("want ErrMonitorNotFound, got %v", err)
+	}
+}
+
+func TestAllMonitorsFollowsNextLink(t *testing.T) {
+	t.Parallel()
+	f := &fakeAPI{}
+	c := newTestClient(t, f)
+	for i := 0; i < 5; i++ {
+		_, err := c.CreateMonitor(uptimerobot.Monitor{
+			URL:  fmt.Sprintf("https://example.com/%d", i),
+			Type: uptimerobot.TypeHTTP,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	monitors, err := c.AllMonitors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 5 {
+		t.Fatalf("want 5 monitors, got %d", len(monitors))
+	}
+	if monitors[4].URL != "https://example.com/4" {
+		t.Errorf("want last monitor https://example.com/4, got %q", monitors[4].URL)
+	}
+}
+
+func TestAllMonitorsRefusesNextLinkToAnotherHost(t *testing.T) {
+	t.Parallel()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to other host with Authorization %q", r.Header.Get("Authorization"))
+	}))
+	defer other.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data": [{"id": 1, "url": "https://example.com", "type": "HTTP"}], "nextLink": "%s/monitors?cursor=1"}`, other.URL)
+	}))
+	defer ts.Close()
+	c := New("dummy", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	if _, err := c.AllMonitors(); err == nil {
+		t.Error("want error following link to another host, got nil")
+	}
+}
+
+func TestEditMonitorSendsOnlyChangedFields(t *testing.T) {
+	t.Parallel()
+	f := &fakeAPI{
+		monitors: []map[string]interface{}{
+			{"id": 1, "friendlyName": "Old", "url": "https://example.com", "type": "HTTP", "interval": 300},
+		},
+	}
+	c := newTestClient(t, f)
+	name := "New"
+	got, err := c.EditMonitor(uptimerobot.EditMonitorParams{
+		ID:           1,
+		FriendlyName: &name,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FriendlyName != "New" || got.Interval != 5*time.Minute {
+		t.Errorf("unexpected monitor after edit: %+v", got)
+	}
+	want := "PATCH /monitors/1"
+	if f.requests[len(f.requests)-1] != want {
+		t.Errorf("want request %q, got %q", want, f.requests[len(f.requests)-1])
+	}
+}
+
+func TestSyncMonitorsUsesSharedHelper(t *testing.T) {
+	t.Parallel()
+	f := &fakeAPI{
+		monitors: []map[string]interface{}{
+			{"id": 1, "friendlyName": "Stale", "url": "https://stale.example.com", "type": "HTTP", "interval": 300},
+			{"id": 2, "friendlyName": "Old name", "url": "https://kept.example.com", "type": "HTTP", "interval": 300},
+		},
+		lastID: 2,
+	}
+	c := newTestClient(t, f)
+	desired := []uptimerobot.Monitor{
+		{FriendlyName: "New name", URL: "https://kept.example.com", Type: uptimerobot.TypeHTTP, Interval: 5 * time.Minute},
+		{FriendlyName: "Added", URL: "https://added.example.com", Type: uptimerobot.TypeHTTP, Interval: 5 * time.Minute},
+	}
+	report, err := c.SyncMonitors(desired, uptimerobot.WithPrune())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Created) != 1 || len(report.Updated) != 1 || len(report.Deleted) != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	monitors, err := c.AllMonitors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, m := range monitors {
+		got = append(got, m.FriendlyName)
+	}
+	want := []string{"New name", "Added"}
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestRequestsWithoutValidKeyReturnAPIError(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewServer(&fakeAPI{})
+	defer ts.Close()
+	c := New("bogus", WithBaseURL(ts.URL), WithHTTPClient(ts.Client()))
+	_, err := c.AllMonitors()
+	var apiErr uptimerobot.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("want APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "invalid API key" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
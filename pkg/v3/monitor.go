@@ -0,0 +1,372 @@
+package v3
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	uptimerobot "github.com/bitfield/uptimerobot/pkg"
+)
+
+var _ uptimerobot.MonitorStore = (*Client)(nil)
+
+// monitor is the v3 API's representation of a monitor. Durations are in
+// seconds, and types and statuses are given by name.
+type monitor struct {
+	ID                    uptimerobot.MonitorID `json:"id,omitempty"`
+	FriendlyName          string                `json:"friendlyName,omitempty"`
+	URL                   string                `json:"url,omitempty"`
+	Type                  string                `json:"type,omitempty"`
+	Port                  uptimerobot.FlexInt   `json:"port,omitempty"`
+	KeywordType           string                `json:"keywordType,omitempty"`
+	KeywordValue          string                `json:"keywordValue,omitempty"`
+	Interval              uptimerobot.FlexInt   `json:"interval,omitempty"`
+	Timeout               uptimerobot.FlexInt   `json:"timeout,omitempty"`
+	IgnoreSSLErrors       bool                  `json:"ignoreSslErrors,omitempty"`
+	Status                string                `json:"status,omitempty"`
+	AssignedAlertContacts []assignedContact     `json:"assignedAlertContacts,omitempty"`
+}
+
+// assignedContact is an alert contact assigned to a monitor.
+type assignedContact struct {
+	AlertContactID uptimerobot.ContactID `json:"alertContactId"`
+	Threshold      int                   `json:"threshold"`
+	Recurrence     int                   `json:"recurrence"`
+}
+
+// monitorPage is a page of monitors, with a link to the next page, if any.
+type monitorPage struct {
+	Data     []monitor `json:"data"`
+	NextLink string    `json:"nextLink"`
+}
+
+var typeNames = map[int]string{
+	uptimerobot.TypeHTTP:      "HTTP",
+	uptimerobot.TypeKeyword:   "KEYWORD",
+	uptimerobot.TypePing:      "PING",
+	uptimerobot.TypePort:      "PORT",
+	uptimerobot.TypeHeartbeat: "HEARTBEAT",
+}
+
+var keywordTypeNames = map[int]string{
+	uptimerobot.KeywordExists:    "ALERT_EXISTS",
+	uptimerobot.KeywordNotExists: "ALERT_NOT_EXISTS",
+}
+
+var statuses = map[string]uptimerobot.Status{
+	"PAUSED":      uptimerobot.StatusPaused,
+	"NOT_CHECKED": uptimerobot.StatusUnknown,
+	"UP":          uptimerobot.StatusUp,
+	"SEEMS_DOWN":  uptimerobot.StatusMaybeDown,
+	"DOWN":        uptimerobot.StatusDown,
+}
+
+// subTypePorts maps the subtypes of v2 port monitors to their standard ports,
+// since v3 port monitors have only a port.
+var subTypePorts = map[int]int{
+	uptimerobot.SubTypeHTTP:  80,
+	uptimerobot.SubTypeHTTPS: 443,
+	uptimerobot.SubTypeFTP:   21,
+	uptimerobot.SubTypeSMTP:  25,
+	uptimerobot.SubTypePOP3:  110,
+	uptimerobot.SubTypeIMAP:  143,
+}
+
+// nameOf returns the key in names whose value is v, or 0 if there is none.
+func nameOf(names map[int]string, v string) int {
+	for k, n := range names {
+		if strings.EqualFold(n, v) {
+			return k
+		}
+	}
+	return 0
+}
+
+// toMonitor converts a v3 monitor to a Monitor.
+func toMonitor(w monitor) uptimerobot.Monitor {
+	m := uptimerobot.Monitor{
+		ID:              w.ID,
+		FriendlyName:    w.FriendlyName,
+		URL:             w.URL,
+		Type:            nameOf(typeNames, w.Type),
+		Port:            int(w.Port),
+		KeywordType:     nameOf(keywordTypeNames, w.KeywordType),
+		KeywordValue:    w.KeywordValue,
+		Interval:        time.Duration(w.Interval) * time.Second,
+		Timeout:         time.Duration(w.Timeout) * time.Second,
+		IgnoreSSLErrors: w.IgnoreSSLErrors,
+		Status:          statuses[strings.ToUpper(w.Status)],
+		AlertContacts:   make([]uptimerobot.ContactID, len(w.AssignedAlertContacts)),
+	}
+	for i, a := range w.AssignedAlertContacts {
+		m.AlertContacts[i] = a.AlertContactID
+		if a.Threshold != 0 || a.Recurrence != 0 {
+			if m.ContactSettings == nil {
+				m.ContactSettings = map[uptimerobot.ContactID]uptimerobot.ContactSettings{}
+			}
+			m.ContactSettings[a.AlertContactID] = uptimerobot.ContactSettings{
+				Threshold:  a.Threshold,
+				Recurrence: a.Recurrence,
+			}
+		}
+	}
+	// Port monitors on a standard port have the corresponding subtype, as
+	// in the v2 API
+	if m.Type == uptimerobot.TypePort {
+		m.SubType = uptimerobot.SubTypeCustomPort
+		for st, port := range subTypePorts {
+			if port == m.Port {
+				m.SubType = st
+				m.Port = 0
+			}
+		}
+	}
+	return m
+}
+
+// fromMonitor converts a Monitor to the v3 representation.
+func fromMonitor(m uptimerobot.Monitor) monitor {
+	w := monitor{
+		FriendlyName:    m.FriendlyName,
+		URL:             m.URL,
+		Type:            typeNames[m.Type],
+		Port:            uptimerobot.FlexInt(m.Port),
+		KeywordType:     keywordTypeNames[m.KeywordType],
+		KeywordValue:    m.KeywordValue,
+		Interval:        uptimerobot.FlexInt(m.Interval / time.Second),
+		Timeout:         uptimerobot.FlexInt(m.Timeout / time.Second),
+		IgnoreSSLErrors: m.IgnoreSSLErrors,
+	}
+	if port, ok := subTypePorts[m.SubType]; ok && m.Type == uptimerobot.TypePort && m.Port == 0 {
+		w.Port = uptimerobot.FlexInt(port)
+	}
+	w.AssignedAlertContacts = assignedContacts(m.AlertContacts, m.ContactSettings)
+	return w
+}
+
+// assignedContacts returns the v3 representation of the specified alert
+// contacts, with their notification settings, if any.
+func assignedContacts(IDs []uptimerobot.ContactID, settings map[uptimerobot.ContactID]uptimerobot.ContactSettings) []assignedContact {
+	contacts := make([]assignedContact, len(IDs))
+	for i, ID := range IDs {
+		contacts[i] = assignedContact{
+			AlertContactID: ID,
+			Threshold:      settings[ID].Threshold,
+			Recurrence:     settings[ID].Recurrence,
+		}
+	}
+	return contacts
+}
+
+// editBody returns the v3 request body for the changes described by p,
+// including only the fields which are set.
+func editBody(p uptimerobot.EditMonitorParams) map[string]interface{} {
+	body := map[string]interface{}{}
+	if p.FriendlyName != nil {
+		body["friendlyName"] = *p.FriendlyName
+	}
+	if p.URL != nil {
+		body["url"] = *p.URL
+	}
+	if p.Port != nil {
+		body["port"] = *p.Port
+	}
+	if p.SubType != nil {
+		if port, ok := subTypePorts[*p.SubType]; ok && p.Port == nil {
+			body["port"] = port
+		}
+	}
+	if p.KeywordType != nil {
+		body["keywordType"] = keywordTypeNames[*p.KeywordType]
+	}
+	if p.KeywordValue != nil {
+		body["keywordValue"] = *p.KeywordValue
+	}
+	if p.Interval != nil {
+		body["interval"] = int(*p.Interval / time.Second)
+	}
+	if p.Timeout != nil {
+		body["timeout"] = int(*p.Timeout / time.Second)
+	}
+	if p.IgnoreSSLErrors != nil {
+		body["ignoreSslErrors"] = *p.IgnoreSSLErrors
+	}
+	if p.AlertContacts != nil {
+		body["assignedAlertContacts"] = assignedContacts(*p.AlertContacts, p.ContactSettings)
+	}
+	return body
+}
+
+// AllMonitors returns all the monitors in the account, with their alert
+// contacts. The options accepted by the v2 client, such as WithLogs, are
+// ignored.
+func (c *Client) AllMonitors(opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	return c.AllMonitorsContext(context.Background(), opts...)
+}
+
+// AllMonitorsContext is like AllMonitors, but uses the specified context for
+// its API requests.
+func (c *Client) AllMonitorsContext(ctx context.Context, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	monitors := []uptimerobot.Monitor{}
+	next := "/monitors"
+	for next != "" {
+		page := monitorPage{}
+		if err := c.do(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, w := range page.Data {
+			monitors = append(monitors, toMonitor(w))
+		}
+		next = page.NextLink
+	}
+	return monitors, nil
+}
+
+// GetMonitor returns the monitor with the specified ID. If there is no such
+// monitor, the error is an uptimerobot.NotFoundError, for which
+// errors.Is(err, uptimerobot.ErrMonitorNotFound) is true.
+func (c *Client) GetMonitor(ID uptimerobot.MonitorID) (uptimerobot.Monitor, error) {
+	return c.GetMonitorContext(context.Background(), ID)
+}
+
+// GetMonitorContext is like GetMonitor, but uses the specified context for its
+// API request.
+func (c *Client) GetMonitorContext(ctx context.Context, ID uptimerobot.MonitorID) (uptimerobot.Monitor, error) {
+	w := monitor{}
+	if err := c.do(ctx, http.MethodGet, monitorPath(ID), nil, &w); err != nil {
+		return uptimerobot.Monitor{}, notFound(err, ID)
+	}
+	return toMonitor(w), nil
+}
+
+// GetMonitorsByURL returns the monitors whose URL is exactly the specified
+// URL.
+func (c *Client) GetMonitorsByURL(URL string, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	return c.GetMonitorsByURLContext(context.Background(), URL, opts...)
+}
+
+// GetMonitorsByURLContext is like GetMonitorsByURL, but uses the specified
+// context for its API requests.
+func (c *Client) GetMonitorsByURLContext(ctx context.Context, URL string, opts ...uptimerobot.Option) ([]uptimerobot.Monitor, error) {
+	monitors, err := c.AllMonitorsContext(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	matches := []uptimerobot.Monitor{}
+	for _, m := range monitors {
+		if m.URL == URL {
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+// CreateMonitor creates a new monitor with the specified details, and returns
+// its ID.
+func (c *Client) CreateMonitor(m uptimerobot.Monitor) (uptimerobot.MonitorID, error) {
+	return c.CreateMonitorContext(context.Background(), m)
+}
+
+// CreateMonitorContext is like CreateMonitor, but uses the specified context
+// for its API request.
+func (c *Client) CreateMonitorContext(ctx context.Context, m uptimerobot.Monitor) (uptimerobot.MonitorID, error) {
+	created := monitor{}
+	if err := c.do(ctx, http.MethodPost, "/monitors", fromMonitor(m), &created); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+// EditMonitor changes the settings of an existing monitor, as given by p, and
+// returns the updated monitor. Fields which are nil in p are left unchanged.
+func (c *Client) EditMonitor(p uptimerobot.EditMonitorParams) (uptimerobot.Monitor, error) {
+	return c.EditMonitorContext(context.Background(), p)
+}
+
+// EditMonitorContext is like EditMonitor, but uses the specified context for
+// its API request.
+func (c *Client) EditMonitorContext(ctx context.Context, p uptimerobot.EditMonitorParams) (uptimerobot.Monitor, error) {
+	w := monitor{}
+	if err := c.do(ctx, http.MethodPatch, monitorPath(p.ID), editBody(p), &w); err != nil {
+		return uptimerobot.Monitor{}, notFound(err, p.ID)
+	}
+	return toMonitor(w), nil
+}
+
+// DeleteMonitor deletes the monitor with the specified ID.
+func (c *Client) DeleteMonitor(ID uptimerobot.MonitorID) error {
+	return c.DeleteMonitorContext(context.Background(), ID)
+}
+
+// DeleteMonitorContext is like DeleteMonitor, but uses the specified context
+// for its API request.
+func (c *Client) DeleteMonitorContext(ctx context.Context, ID uptimerobot.MonitorID) error {
+	return notFound(c.do(ctx, http.MethodDelete, monitorPath(ID), nil, nil), ID)
+}
+
+// PauseMonitor pauses the monitor with the specified ID.
+func (c *Client) PauseMonitor(ID uptimerobot.MonitorID) error {
+	return c.PauseMonitorContext(context.Background(), ID)
+}
+
+// PauseMonitorContext is like PauseMonitor, but uses the specified context
+// for its API request.
+func (c *Client) PauseMonitorContext(ctx context.Context, ID uptimerobot.MonitorID) error {
+	return notFound(c.do(ctx, http.MethodPost, monitorPath(ID)+"/pause", nil, nil), ID)
+}
+
+// StartMonitor resumes the paused monitor with the specified ID.
+func (c *Client) StartMonitor(ID uptimerobot.MonitorID) error {
+	return c.StartMonitorContext(context.Background(), ID)
+}
+
+// StartMonitorContext is like StartMonitor, but uses the specified context
+// for its API request.
+func (c *Client) StartMonitorContext(ctx context.Context, ID uptimerobot.MonitorID) error {
+	return notFound(c.do(ctx, http.MethodPost, monitorPath(ID)+"/start", nil, nil), ID)
+}
+
+// EnsureMonitors makes sure that each of the specified monitors exists, as
+// uptimerobot.Client.EnsureMonitors does for the v2 API.
+func (c *Client) EnsureMonitors(monitors []uptimerobot.Monitor) ([]uptimerobot.EnsureResult, error) {
+	return c.EnsureMonitorsContext(context.Background(), monitors)
+}
+
+// EnsureMonitorsContext is like EnsureMonitors, but uses the specified context
+// for its API requests.
+func (c *Client) EnsureMonitorsContext(ctx context.Context, monitors []uptimerobot.Monitor) ([]uptimerobot.EnsureResult, error) {
+	return uptimerobot.EnsureMonitorsIn(ctx, c, monitors)
+}
+
+// SyncMonitors makes the monitors in the account match the desired set, as
+// uptimerobot.Client.SyncMonitors does for the v2 API, and accepts the same
+// options.
+func (c *Client) SyncMonitors(desired []uptimerobot.Monitor, opts ...uptimerobot.SyncOption) (uptimerobot.SyncReport, error) {
+	return c.SyncMonitorsContext(context.Background(), desired, opts...)
+}
+
+// SyncMonitorsContext is like SyncMonitors, but uses the specified context for
+// its API requests.
+func (c *Client) SyncMonitorsContext(ctx context.Context, desired []uptimerobot.Monitor, opts ...uptimerobot.SyncOption) (uptimerobot.SyncReport, error) {
+	return uptimerobot.SyncMonitorsIn(ctx, c, desired, opts...)
+}
+
+// monitorPath returns the path of the monitor with the specified ID.
+func monitorPath(ID uptimerobot.MonitorID) string {
+	return "/monitors/" + url.PathEscape(ID.String())
+}
+
+// notFound returns an uptimerobot.NotFoundError for the monitor with the
+// specified ID if err is an APIError with the HTTP status 404, or otherwise
+// err unchanged.
+func notFound(err error, ID uptimerobot.MonitorID) error {
+	if apiErr, ok := err.(uptimerobot.APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+		return uptimerobot.NotFoundError{
+			Resource: "monitor",
+			ID:       ID.String(),
+		}
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package uptimerobot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxWebhookSize is the largest JSON webhook request body that ParseWebhook
+// will read.
+const maxWebhookSize = 1 << 20
+
+// Webhook is an alert notification sent by a webhook alert contact. The
+// AlertType field is AlertTypeDown, AlertTypeUp, or AlertTypeSSLExpiry. Fields
+// which the webhook doesn't include are left as their zero values.
+type Webhook struct {
+	MonitorID             MonitorID
+	MonitorURL            string
+	MonitorFriendlyName   string
+	AlertType             int
+	AlertTypeFriendlyName string
+	AlertDetails          string
+	AlertDuration         time.Duration
+	AlertDateTime         time.Time
+	SSLExpiryDate         string
+	SSLExpiryDaysLeft     int
+}
+
+// ParseWebhook parses the alert notification in a request sent by a webhook
+// alert contact. The webhook's fields, such as monitorID and alertType, may be
+// given as a JSON object in the request body (if the contact is set to send
+// JSON), as a form-encoded request body, or as query parameters in the URL;
+// they are named as in the contact's settings, without the asterisks. For
+// example, a contact with the URL:
+//
+//	https://example.com/alert?monitorID=*monitorID*&alertType=*alertType*&alertDateTime=*alertDateTime*
+//
+// gives a Webhook with the MonitorID, AlertType, and AlertDateTime fields
+// set. ParseWebhook returns an error if the request has no monitor ID, or if
+// any numeric field is invalid.
+func ParseWebhook(r *http.Request) (Webhook, error) {
+	fields, err := webhookFields(r)
+	if err != nil {
+		return Webhook{}, err
+	}
+	if fields["monitorID"] == "" {
+		return Webhook{}, errors.New("webhook has no monitorID")
+	}
+	w := Webhook{
+		MonitorURL:            fields["monitorURL"],
+		MonitorFriendlyName:   fields["monitorFriendlyName"],
+		AlertTypeFriendlyName: fields["alertTypeFriendlyName"],
+		AlertDetails:          fields["alertDetails"],
+		SSLExpiryDate:         fields["sslExpiryDate"],
+	}
+	w.MonitorID, err = ParseMonitorID(fields["monitorID"])
+	if err != nil {
+		return Webhook{}, err
+	}
+	ints := map[string]*int{
+		"alertType":         &w.AlertType,
+		"sslExpiryDaysLeft": &w.SSLExpiryDaysLeft,
+	}
+	for name, p := range ints {
+		if fields[name] == "" {
+			continue
+		}
+		*p, err = strconv.Atoi(fields[name])
+		if err != nil {
+			return Webhook{}, fmt.Errorf("invalid webhook %s %q", name, fields[name])
+		}
+	}
+	if s := fields["alertDuration"]; s != "" {
+		secs, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return Webhook{}, fmt.Errorf("invalid webhook alertDuration %q", s)
+		}
+		w.AlertDuration = time.Duration(secs) * time.Second
+	}
+	if s := fields["alertDateTime"]; s != "" {
+		secs, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return Webhook{}, fmt.Errorf("invalid webhook alertDateTime %q", s)
+		}
+		w.AlertDateTime = time.Unix(secs, 0)
+	}
+	return w, nil
+}
+
+// webhookFields returns the fields of a webhook request, from its JSON body
+// if it has one, or otherwise from its form body and query parameters.
+func webhookFields(r *http.Request) (map[string]string, error) {
+	fields := map[string]string{}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		dec := json.NewDecoder(io.LimitReader(r.Body, maxWebhookSize))
+		dec.UseNumber()
+		raw := map[string]interface{}{}
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("decoding webhook: %v", err)
+		}
+		for k, v := range raw {
+			if v != nil {
+				fields[k] = fmt.Sprint(v)
+			}
+		}
+		return fields, nil
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("decoding webhook: %v", err)
+	}
+	for k := range r.Form {
+		fields[k] = r.Form.Get(k)
+	}
+	return fields, nil
+}